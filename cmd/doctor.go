@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/adamakhtar/wing_commander/internal/doctor"
+	"github.com/spf13/cobra"
+)
+
+// doctorCmd represents the doctor command
+var doctorCmd = &cobra.Command{
+	Use:   "doctor [project path]",
+	Short: "Check that a project is set up to run its tests with Wing Commander",
+	Long: `Check the project's setup and print a checklist with a fix for every
+problem found: the project path, the configuration, that the test command can
+be found, that test_file_pattern matches test files, that the reporter writes
+a summary matching the schema and that git is available for change detection.
+
+The reporter is checked with a dry run of one test file. Failing tests don't
+fail the check. Use --skip-dry-run to leave it out.
+
+Exits with 1 when a check failed.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		os.Exit(diagnose(cmd, args))
+	},
+}
+
+var doctorSkipDryRun bool
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+	addTestRunFlags(doctorCmd)
+
+	doctorCmd.Flags().BoolVar(&doctorSkipDryRun, "skip-dry-run", false, "Don't run a test file to check the reporter")
+}
+
+func diagnose(cmd *cobra.Command, args []string) int {
+	// Report a bad project path as a failed check rather than exiting in buildConfig
+	if len(args) > 0 {
+		if path, err := filepath.Abs(args[0]); err == nil {
+			if check := doctor.CheckProjectPath(path); check.Status == doctor.StatusFail {
+				printChecks([]doctor.Check{check})
+				return 1
+			}
+		}
+	}
+
+	config := buildConfig(cmd, args)
+	closeLogger := setupLogger(config)
+	defer closeLogger()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	checks := doctor.Run(ctx, config, doctor.Options{SkipDryRun: doctorSkipDryRun})
+	printChecks(checks)
+	if doctor.Failed(checks) {
+		return 1
+	}
+	return 0
+}
+
+// printChecks prints the checklist, with the fix for each warning or
+// failure below it, and a count of the problems found.
+func printChecks(checks []doctor.Check) {
+	problems := 0
+	for _, check := range checks {
+		detail := strings.ReplaceAll(check.Detail, "\n", "\n   ")
+		fmt.Printf("%s %s: %s\n", checkIcon(check.Status), check.Name, detail)
+		if check.Fix != "" && (check.Status == doctor.StatusFail || check.Status == doctor.StatusWarn) {
+			fmt.Printf("   Fix: %s\n", check.Fix)
+		}
+		if check.Status == doctor.StatusFail || check.Status == doctor.StatusWarn {
+			problems++
+		}
+	}
+
+	fmt.Println()
+	switch problems {
+	case 0:
+		fmt.Println("No problems found")
+	case 1:
+		fmt.Println("1 problem found")
+	default:
+		fmt.Printf("%d problems found\n", problems)
+	}
+}
+
+func checkIcon(status doctor.Status) string {
+	switch status {
+	case doctor.StatusPass:
+		return "✅"
+	case doctor.StatusWarn:
+		return "⚠️ "
+	case doctor.StatusFail:
+		return "❌"
+	default:
+		return "➖"
+	}
+}
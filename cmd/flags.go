@@ -0,0 +1,315 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/adamakhtar/wing_commander/internal/config"
+	"github.com/adamakhtar/wing_commander/internal/projectfs"
+	"github.com/adamakhtar/wing_commander/internal/types"
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+)
+
+var (
+	configPath           string
+	showConfig           bool
+	testFileGlob         string
+	runCommand           string
+	runTestCaseCommand   string
+	testResultsPath      string
+	testFramework        string
+	hungTestQuietPeriod  int
+	testFailureExitCodes []int
+	historyLimit         int
+	retryFailures        int
+	excludePatterns      []string
+	pathMappings         []string
+	gemsPath             string
+	commandPrefix        string
+	targetProjectPath    string
+	disableInjection     bool
+	debug                bool
+)
+
+// addTestRunFlags registers the flags shared by every command that runs tests.
+func addTestRunFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&configPath, "config", "c", "", "Config file to read settings from (default .wing_commander/config.yml in the project)")
+
+	cmd.Flags().BoolVar(&showConfig, "show-config", false, "Print the effective settings and where each came from, then exit")
+
+	cmd.Flags().BoolVarP(&debug, "debug", "d", false, "Enable logging to debug problems.")
+	cmd.Flags().StringVarP(&testFileGlob, "test-file-glob", "p", "", "A glob pattern to use to match test files in the project (e.g. 'test/**/*.rb')")
+
+	cmd.Flags().StringVarP(&runCommand, "run-command", "r", "", "The command to execute tests on the command line (e.g. 'rake test'), may use placeholders such as %{paths}")
+
+	cmd.Flags().StringVar(&runTestCaseCommand, "run-test-case-command", "", "Optional command template for re-running failed test cases, e.g. 'bin/rails test %{locations}' (defaults to --run-command)")
+
+	cmd.Flags().StringVar(&testFramework, "test-framework", "", "The test framework used by the project (minitest, rspec, go, pytest, jest or vitest, default minitest)")
+
+	cmd.Flags().IntVar(&hungTestQuietPeriod, "hung-test-quiet-period", 0, "Seconds a started test may go without results before it is reported as possibly hung (default 30, negative disables)")
+
+	cmd.Flags().IntSliceVar(&testFailureExitCodes, "test-failure-exit-codes", nil, "Exit codes meaning tests ran and some failed (default 1); 0 means passed and any other code is treated as a crashed suite")
+
+	cmd.Flags().IntVar(&historyLimit, "history-limit", 0, "Number of finished test runs kept in .wing_commander/history (default 50, negative disables the history)")
+
+	cmd.Flags().IntVar(&retryFailures, "retry-failures", 0, "Re-run failed tests up to N times after each test run, tests passing on a retry count as flaky (default 0, off)")
+
+	cmd.Flags().StringVarP(&testResultsPath, "test-results-path", "t", "", "path to the summary file the test results are written to (default '.wing_commander/test_results/summary.yml')")
+
+	cmd.Flags().StringArrayVar(&excludePatterns, "exclude-pattern", nil, "Leave project files whose path contains this out of filtered backtraces (e.g. '/vendor/bundle/'), may be repeated and replaces the configured patterns")
+
+	cmd.Flags().StringArrayVar(&pathMappings, "path-mapping", nil, "Map absolute paths in results produced elsewhere to local ones, as FROM=TO (e.g. '/app=.'), may be repeated")
+
+	cmd.Flags().StringVar(&commandPrefix, "command-prefix", "", "Run test commands through this prefix, e.g. 'docker compose exec -T web'")
+
+	cmd.Flags().StringVar(&targetProjectPath, "target-project-path", "", "Project root where --command-prefix runs tests (e.g. '/app'), paths are translated to and from it")
+
+	cmd.Flags().BoolVar(&disableInjection, "disable-reporter-injection", false, "Don't load the Minitest reporter into test processes through RUBYOPT, for projects that register it in test_helper.rb")
+
+	cmd.Flags().StringVar(&gemsPath, "gems-path", "", "Local directory of installed gems that gem files in results from elsewhere are mapped to (e.g. 'vendor/bundle/ruby/3.3.0/gems')")
+}
+
+// buildConfig initializes the project filesystem and builds the config. Flags
+// given on the command line take precedence over the config file, which takes
+// precedence over the defaults, setting by setting. With --show-config the
+// effective settings are printed and the process exits.
+func buildConfig(cmd *cobra.Command, args []string) *config.Config {
+	projectPathAbs := processProjectPathArg(args)
+
+	file := configFilePath(cmd, projectPathAbs)
+	cfg, err := config.LoadConfig(file)
+	if err != nil {
+		fmt.Printf("❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := applyFlags(cmd, cfg); err != nil {
+		fmt.Printf("❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+	cfg.ResolveDefaults()
+
+	framework, err := config.ValidateFramework(string(cfg.TestFramework))
+	if err != nil {
+		fmt.Printf("❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+	cfg.TestFramework = framework
+
+	if err := projectfs.InitProjectFS(projectPathAbs, cfg.TestFilePattern); err != nil {
+		fmt.Printf("❌ Error initializing ProjectFS: %v\n", err)
+		os.Exit(1)
+	}
+
+	cfg.TestResultsPath = processTestResultsPathOption(projectPathAbs, cfg.TestResultsPath)
+
+	configurePathMappings(cfg)
+	projectfs.GetProjectFS().SetExcludePatterns(cfg.ExcludePatterns)
+
+	if showConfig {
+		printSettings(os.Stdout, cfg, file)
+		os.Exit(0)
+	}
+	return cfg
+}
+
+// configFilePath returns the config file given with --config, which must
+// exist, or the project's default config file, which may not.
+func configFilePath(cmd *cobra.Command, projectRoot types.AbsPath) string {
+	if !cmd.Flags().Changed("config") {
+		return filepath.Join(projectRoot.String(), config.DefaultConfigPath())
+	}
+
+	path, err := filepath.Abs(configPath)
+	if err != nil {
+		fmt.Printf("❌ Error getting absolute path for config file %s: %v\n", configPath, err)
+		os.Exit(1)
+	}
+	if info, err := os.Stat(path); err != nil || info.IsDir() {
+		fmt.Printf("❌ Error: config file %s must be an existing file\n", configPath)
+		os.Exit(1)
+	}
+	return path
+}
+
+// applyFlags overrides the config with the flags given on the command line and
+// records them as the source of those settings. Flags left at their defaults
+// don't override anything.
+func applyFlags(cmd *cobra.Command, cfg *config.Config) error {
+	overrides := []struct {
+		flag    string
+		setting string
+		apply   func() error
+	}{
+		{"test-framework", config.SettingTestFramework, func() error {
+			framework, err := config.ValidateFramework(testFramework)
+			cfg.TestFramework = framework
+			return err
+		}},
+		{"run-command", config.SettingTestCommand, func() error {
+			cfg.TestCommand = runCommand
+			return nil
+		}},
+		{"run-test-case-command", config.SettingRunTestCaseCommand, func() error {
+			cfg.RunTestCaseCommand = runTestCaseCommand
+			return nil
+		}},
+		{"test-file-glob", config.SettingTestFilePattern, func() error {
+			cfg.TestFilePattern = testFileGlob
+			return nil
+		}},
+		{"test-results-path", config.SettingTestResultsPath, func() error {
+			cfg.TestResultsPath = testResultsPath
+			return nil
+		}},
+		{"hung-test-quiet-period", config.SettingHungTestQuietPeriod, func() error {
+			cfg.HungTestQuietPeriod = hungTestQuietPeriod
+			return nil
+		}},
+		{"test-failure-exit-codes", config.SettingTestFailureExitCodes, func() error {
+			cfg.TestFailureExitCodes = testFailureExitCodes
+			return nil
+		}},
+		{"history-limit", config.SettingHistoryLimit, func() error {
+			cfg.HistoryLimit = historyLimit
+			return nil
+		}},
+		{"retry-failures", config.SettingRetryFailures, func() error {
+			cfg.RetryFailures = retryFailures
+			return nil
+		}},
+		{"debug", config.SettingDebug, func() error {
+			cfg.Debug = debug
+			return nil
+		}},
+		{"exclude-pattern", config.SettingExcludePatterns, func() error {
+			cfg.ExcludePatterns = excludePatterns
+			return nil
+		}},
+		{"path-mapping", config.SettingPathMappings, func() error {
+			mappings := make([]config.PathMapping, 0, len(pathMappings))
+			for _, value := range pathMappings {
+				mapping, err := config.ParsePathMapping(value)
+				if err != nil {
+					return err
+				}
+				mappings = append(mappings, mapping)
+			}
+			cfg.PathMappings = mappings
+			return nil
+		}},
+		{"gems-path", config.SettingGemsPath, func() error {
+			cfg.GemsPath = gemsPath
+			return nil
+		}},
+		{"command-prefix", config.SettingCommandPrefix, func() error {
+			cfg.ExecutionTarget.CommandPrefix = commandPrefix
+			return nil
+		}},
+		{"disable-reporter-injection", config.SettingDisableReporterInjection, func() error {
+			cfg.DisableReporterInjection = disableInjection
+			return nil
+		}},
+		{"target-project-path", config.SettingTargetProjectPath, func() error {
+			if !filepath.IsAbs(targetProjectPath) {
+				return fmt.Errorf("--target-project-path %s must be an absolute path", targetProjectPath)
+			}
+			cfg.ExecutionTarget.ProjectPath = targetProjectPath
+			return nil
+		}},
+	}
+
+	for _, o := range overrides {
+		if !cmd.Flags().Changed(o.flag) {
+			continue
+		}
+		if err := o.apply(); err != nil {
+			return err
+		}
+		cfg.SetSource(o.setting, config.SourceCLI)
+	}
+	return nil
+}
+
+// printSettings writes every effective setting with the source it came from.
+func printSettings(w io.Writer, cfg *config.Config, file string) {
+	if _, err := os.Stat(file); err == nil {
+		fmt.Fprintf(w, "Config file: %s\n\n", file)
+	} else {
+		fmt.Fprintf(w, "Config file: %s (not found, using defaults)\n\n", file)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SETTING\tVALUE\tSOURCE")
+	for _, setting := range cfg.Settings() {
+		value := setting.Value
+		if value == "" {
+			value = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", setting.Name, value, setting.Source)
+	}
+	tw.Flush()
+}
+
+// logSettings logs every effective setting with the source it came from.
+func logSettings(cfg *config.Config) {
+	for _, setting := range cfg.Settings() {
+		log.Info("Config setting", "name", setting.Name, "value", setting.Value, "source", setting.Source)
+	}
+}
+
+// configurePathMappings registers the configured path mappings with ProjectFS
+// so results produced elsewhere are parsed with local paths.
+func configurePathMappings(cfg *config.Config) {
+	fs := projectfs.GetProjectFS()
+	for _, mapping := range cfg.PathMappings {
+		fs.AddPathMapping(projectfs.PathMapping{
+			From: types.AbsPath(filepath.Clean(mapping.From)),
+			To:   resolveProjectPath(mapping.To),
+		})
+	}
+	if cfg.GemsPath != "" {
+		fs.SetGemsPath(resolveProjectPath(cfg.GemsPath))
+	}
+}
+
+// resolveProjectPath resolves a path from the config against the project root,
+// expanding a leading ~ to the home directory.
+func resolveProjectPath(path string) types.AbsPath {
+	if home, err := os.UserHomeDir(); err == nil && (path == "~" || strings.HasPrefix(path, "~/")) {
+		path = filepath.Join(home, strings.TrimPrefix(path, "~"))
+	}
+	if filepath.IsAbs(path) {
+		return types.AbsPath(filepath.Clean(path))
+	}
+	return types.AbsPath(filepath.Join(projectfs.GetProjectFS().RootPath.String(), path))
+}
+
+// processTestResultsPathOption resolves the summary path against the project
+// root. The summary may not exist yet, it is written by the first run.
+func processTestResultsPathOption(projectRoot types.AbsPath, providedPath string) string {
+	var absPath string
+	if filepath.IsAbs(providedPath) {
+		absPath = providedPath
+	} else {
+		relPath, err := types.NewRelPath(providedPath)
+		if err != nil {
+			fmt.Printf("❌ Error: testResultsPath %s must be relative or absolute\n", providedPath)
+			os.Exit(1)
+		}
+		absPath = projectfs.GetProjectFS().Abs(relPath).String()
+	}
+
+	info, err := os.Stat(absPath)
+	if err == nil && info.IsDir() {
+		fmt.Printf("❌ Error: testResultsPath %s must be a file, not a directory\n", absPath)
+		os.Exit(1)
+	}
+
+	return absPath
+}
@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/adamakhtar/wing_commander/internal/config"
+	"github.com/adamakhtar/wing_commander/internal/reporters"
+	"github.com/spf13/cobra"
+)
+
+// initCmd represents the init command
+var initCmd = &cobra.Command{
+	Use:   "init [project path]",
+	Short: "Set up a project: write its config and install the reporter",
+	Long: `Detect the project's test framework, write .wing_commander/config.yml and
+install the files Wing Commander runs tests with: the bin/test runner for
+Minitest, or the RSpec formatter.
+
+The Minitest reporter is loaded into test runs by Wing Commander and needs no
+changes to the project. The .rspec change enabling the RSpec formatter is
+printed, or made with --apply. Existing files are left alone unless --force is
+given.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := initProject(args); err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+var (
+	initTestFramework string
+	initApply         bool
+	initForce         bool
+)
+
+func init() {
+	rootCmd.AddCommand(initCmd)
+
+	initCmd.Flags().StringVar(&initTestFramework, "test-framework", "", "The test framework to set up instead of the detected one (minitest, rspec, go, pytest, jest or vitest)")
+	initCmd.Flags().BoolVar(&initApply, "apply", false, "Edit .rspec to load the formatter instead of printing the change")
+	initCmd.Flags().BoolVar(&initForce, "force", false, "Overwrite an existing config file and installed files that differ")
+}
+
+func initProject(args []string) error {
+	root := processProjectPathArg(args).String()
+
+	framework, err := initFramework(root)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Setting up %s in %s\n\n", framework, root)
+
+	files, err := reporters.Files(framework)
+	if err != nil {
+		return err
+	}
+	results, err := reporters.Install(root, files, initForce)
+	if err != nil {
+		return err
+	}
+	runnerInstalled := false
+	for _, result := range results {
+		printInstallResult(result)
+		if result.Path == "bin/test" && result.Status != reporters.StatusSkipped {
+			runnerInstalled = true
+		}
+	}
+
+	if err := writeInitConfig(root, framework, runnerInstalled); err != nil {
+		return err
+	}
+
+	if change, ok := reporters.HelperChangeFor(framework); ok {
+		if err := initHelperChange(root, change); err != nil {
+			return err
+		}
+	}
+	if framework == config.FrameworkMinitest {
+		fmt.Println("✅ the Minitest reporter is loaded into test runs through RUBYOPT, test/test_helper.rb needs no changes")
+		if !fileContains(filepath.Join(root, "Gemfile"), "minitest-reporters") {
+			fmt.Println("\n⚠️  The reporter needs the minitest-reporters gem, add `gem \"minitest-reporters\"` to the Gemfile's test group")
+		}
+	}
+
+	fmt.Println("\nRun `wing_commander start` in the project to run its tests.")
+	return nil
+}
+
+// initFramework returns the framework given with --test-framework or detected
+// from the project's files.
+func initFramework(root string) (config.TestFramework, error) {
+	if initTestFramework != "" {
+		return config.ValidateFramework(initTestFramework)
+	}
+	framework, ok := config.DetectFramework(root)
+	if !ok {
+		return "", errors.New("could not detect the test framework, pass --test-framework")
+	}
+	return framework, nil
+}
+
+func printInstallResult(result reporters.InstallResult) {
+	switch result.Status {
+	case reporters.StatusSkipped:
+		fmt.Printf("⚠️  %s exists and differs, skipped (use --force to overwrite)\n", result.Path)
+	case reporters.StatusUnchanged:
+		fmt.Printf("✅ %s is up to date\n", result.Path)
+	default:
+		fmt.Printf("✅ %s %s\n", result.Status, result.Path)
+	}
+}
+
+// writeInitConfig writes the framework's default settings to the project's
+// config file. Minitest projects run tests through the installed bin/test.
+func writeInitConfig(root string, framework config.TestFramework, runnerInstalled bool) error {
+	configFile := filepath.Join(root, config.DefaultConfigPath())
+	if _, err := os.Stat(configFile); err == nil && !initForce {
+		fmt.Printf("⚠️  %s exists, skipped (use --force to overwrite)\n", config.DefaultConfigPath())
+		return nil
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.TestFramework = framework
+	cfg.TestCommand = config.GetDefaultTestCommand(framework)
+	if framework == config.FrameworkMinitest && runnerInstalled {
+		cfg.TestCommand = "bin/test"
+	}
+	cfg.RunTestCaseCommand = cfg.TestCommand
+	cfg.TestFilePattern = config.GetDefaultTestFilePattern(framework)
+
+	if err := config.SaveConfig(cfg, configFile); err != nil {
+		return err
+	}
+	fmt.Printf("✅ wrote %s\n", config.DefaultConfigPath())
+	return nil
+}
+
+func fileContains(path string, substr string) bool {
+	data, err := os.ReadFile(path)
+	return err == nil && strings.Contains(string(data), substr)
+}
+
+// initHelperChange makes the change loading the reporter with --apply, and
+// prints it otherwise.
+func initHelperChange(root string, change reporters.HelperChange) error {
+	if change.IsApplied(root) {
+		fmt.Printf("✅ %s already loads the reporter\n", change.Path)
+		return nil
+	}
+
+	if initApply {
+		if err := change.Apply(root); err != nil {
+			return err
+		}
+		fmt.Printf("✅ updated %s to load the reporter\n", change.Path)
+		return nil
+	}
+
+	fmt.Printf("\nAdd to %s to load the reporter (or re-run with --apply):\n\n", change.Path)
+	for _, line := range strings.Split(strings.TrimRight(change.Snippet, "\n"), "\n") {
+		fmt.Printf("    %s\n", line)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/adamakhtar/wing_commander/internal/report"
+	"github.com/adamakhtar/wing_commander/internal/runner"
+	"github.com/adamakhtar/wing_commander/internal/testrun"
+	"github.com/spf13/cobra"
+)
+
+// Exit codes of the run command
+const (
+	exitPassed      = 0
+	exitTestsFailed = 1
+	exitCrashed     = 2
+	exitInterrupted = 130
+)
+
+// runCmd represents the run command
+var runCmd = &cobra.Command{
+	Use:   "run [project path]",
+	Short: "Run the test suite without the TUI and print a report",
+	Long: `Run the whole test suite once and print a report of the results, for CI
+and scripts. Failures are grouped and classified the same way as in the TUI.
+
+Exits with 0 when every test passed, 1 when tests failed, 2 when the suite
+crashed or could not be run and 130 when interrupted.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		os.Exit(run(cmd, args))
+	},
+}
+
+var (
+	reportFormat string
+	reportOutput string
+)
+
+func init() {
+	rootCmd.AddCommand(runCmd)
+	addTestRunFlags(runCmd)
+
+	runCmd.Flags().StringVarP(&reportFormat, "format", "f", string(report.FormatText), "Report format: text, json or junit")
+	runCmd.Flags().StringVarP(&reportOutput, "output", "o", "", "Write the report to this file instead of stdout")
+}
+
+func run(cmd *cobra.Command, args []string) int {
+	format, err := report.ValidateFormat(reportFormat)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		return exitCrashed
+	}
+
+	config := buildConfig(cmd, args)
+	closeLogger := setupLogger(config)
+	defer closeLogger()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	testRuns := testrun.NewTestRuns()
+	testRun, err := testRuns.Add(nil, testrun.ModeRunWholeSuite)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		return exitCrashed
+	}
+
+	testRunner := runner.NewTestRunner(config)
+	result, err := testRunner.ExecuteTests(ctx, testRun, runner.Hooks{})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error running tests: %v\n", err)
+		return exitCrashed
+	}
+	if err := testRunner.RetryFailures(ctx, testRun, result, runner.Hooks{}); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  %v, reporting the attempts so far\n", err)
+	}
+
+	var out io.Writer = os.Stdout
+	if reportOutput != "" {
+		file, err := os.Create(reportOutput)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error creating report file: %v\n", err)
+			return exitCrashed
+		}
+		defer file.Close()
+		out = file
+	}
+
+	if err := report.Write(out, format, result); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error writing report: %v\n", err)
+		return exitCrashed
+	}
+
+	return runExitCode(result)
+}
+
+// runExitCode maps the outcome of a test run to the run command's exit code.
+func runExitCode(result *runner.TestExecutionResult) int {
+	switch {
+	case result.Cancelled:
+		return exitInterrupted
+	case result.Crashed:
+		return exitCrashed
+	case result.Metrics.FailedTests > 0:
+		return exitTestsFailed
+	default:
+		return exitPassed
+	}
+}
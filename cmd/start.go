@@ -10,7 +10,6 @@ import (
 
 	"github.com/adamakhtar/wing_commander/internal/config"
 	"github.com/adamakhtar/wing_commander/internal/logger"
-	"github.com/adamakhtar/wing_commander/internal/projectfs"
 	"github.com/adamakhtar/wing_commander/internal/types"
 	"github.com/adamakhtar/wing_commander/internal/ui/styles"
 
@@ -26,55 +25,20 @@ var startCmd = &cobra.Command{
 	Args:  cobra.MaximumNArgs(1),
 	Long:  "",
 	Run: func(cmd *cobra.Command, args []string) {
-		start(args)
+		start(cmd, args)
 	},
 }
 
-var (
-	testFileGlob    string
-	runCommand         string
-	runTestCaseCommand string
-	testResultsPath    string
-	debug              bool
-)
-
 func init() {
 	rootCmd.AddCommand(startCmd)
-
-	startCmd.Flags().BoolVarP(&debug, "debug", "d", false, "Enable logging to debug problems.")
-	startCmd.Flags().StringVarP(&testFileGlob, "test-file-glob", "p", "", "A glob pattern to use to match test files in the project (e.g. 'test/**/*.rb')")
-
-	startCmd.Flags().StringVarP(&runCommand, "run-command", "r", "", "The command to execute tests on the command line (e.g. 'rake test')")
-	if err := startCmd.MarkFlagRequired("run-command"); err != nil {
-		panic(err)
-	}
-
-	startCmd.Flags().StringVar(&runTestCaseCommand, "run-test-case-command", "", "Optional command template for running a single test case (supports %{test_case_name} and %{line_number} placeholders)")
-
-	startCmd.Flags().StringVarP(&testResultsPath, "test-results-path", "t", "", "path to the directory where the test results are written (e.g. '.wing_commander/test_results')")
-	if err := startCmd.MarkFlagRequired("test-results-path"); err != nil {
-		panic(err)
-	}
+	addTestRunFlags(startCmd)
 }
 
-func start(args []string) {
-	closeLogger := setupLogger()
+func start(cmd *cobra.Command, args []string) {
+	config := buildConfig(cmd, args)
+	closeLogger := setupLogger(config)
 	defer closeLogger()
 
-	projectPathAbs := processProjectPathArg(args)
-	if err := projectfs.InitProjectFS(projectPathAbs, testFileGlob); err != nil {
-		fmt.Printf("❌ Error initializing ProjectFS: %v\n", err)
-		os.Exit(1)
-	}
-
-	testResultsPath := processTestResultsPathOption(projectPathAbs, testResultsPath)
-
-	rtcCommand := runTestCaseCommand
-	if rtcCommand == "" {
-		rtcCommand = runCommand
-	}
-
-	config := config.NewConfig(runCommand, testFileGlob, testResultsPath, rtcCommand, debug)
 	styles := styles.BuildStyles(styles.DefaultTheme)
 	model := ui.NewModel(config, styles)
 
@@ -123,33 +87,14 @@ func processProjectPathArg(args []string) types.AbsPath {
 	return absPath
 }
 
-func processTestResultsPathOption(projectRoot types.AbsPath, providedPath string) string {
-	var absPath string
-	if filepath.IsAbs(providedPath) {
-		absPath = providedPath
-	} else {
-		relPath, err := types.NewRelPath(providedPath)
-		if err != nil {
-			fmt.Printf("❌ Error: testResultsPath %s must be relative or absolute\n", providedPath)
-			os.Exit(1)
-		}
-		absPath = projectfs.GetProjectFS().Abs(relPath).String()
-	}
-
-	info, err := os.Stat(absPath)
-	if err != nil || info.IsDir() {
-		fmt.Printf("❌ Error: testResultsPath %s must be an existing file\n", absPath)
-		os.Exit(1)
-	}
-
-	return absPath
-}
-
-func setupLogger() func() error {
-	closeLogger, err := logger.SetupLogger(debug)
+// setupLogger sets up logging when the config enables debug and logs the
+// effective settings.
+func setupLogger(cfg *config.Config) func() error {
+	closeLogger, err := logger.SetupLogger(cfg.Debug)
 	if err != nil {
 		fmt.Printf("Error setting up logger: %v\n", err)
 		os.Exit(1)
 	}
+	logSettings(cfg)
 	return closeLogger
 }
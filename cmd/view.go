@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/adamakhtar/wing_commander/internal/ui"
+	"github.com/adamakhtar/wing_commander/internal/ui/styles"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+)
+
+// viewCmd represents the view command
+var viewCmd = &cobra.Command{
+	Use:   "view <summary-file>...",
+	Short: "Browse existing summary files without running tests",
+	Long: `Open one or more summary files, e.g. downloaded from CI or a teammate, in
+the TUI without running the test command first.
+
+Absolute paths written on another machine are mapped onto the local checkout
+when the summaries' test files are found in it, so backtraces and code
+previews work. Re-running failures uses --run-command, or the framework's
+default command when it isn't given.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		view(cmd, args)
+	},
+}
+
+var viewProjectPath string
+
+func init() {
+	rootCmd.AddCommand(viewCmd)
+	addTestRunFlags(viewCmd)
+
+	viewCmd.Flags().StringVar(&viewProjectPath, "project-path", "", "The local checkout the summaries were produced from (defaults to the current directory)")
+}
+
+func view(cmd *cobra.Command, args []string) {
+	summaryPaths := make([]string, 0, len(args))
+	for _, arg := range args {
+		path, err := filepath.Abs(arg)
+		if err != nil {
+			fmt.Printf("❌ Error getting absolute path for %s: %v\n", arg, err)
+			os.Exit(1)
+		}
+		if info, err := os.Stat(path); err != nil || info.IsDir() {
+			fmt.Printf("❌ Error: summary file %s must be an existing file\n", arg)
+			os.Exit(1)
+		}
+		summaryPaths = append(summaryPaths, path)
+	}
+
+	var projectArgs []string
+	if viewProjectPath != "" {
+		projectArgs = []string{viewProjectPath}
+	}
+	config := buildConfig(cmd, projectArgs)
+	closeLogger := setupLogger(config)
+	defer closeLogger()
+
+	styles := styles.BuildStyles(styles.DefaultTheme)
+	model, err := ui.NewViewModel(config, styles, summaryPaths)
+	if err != nil {
+		fmt.Printf("❌ Error loading summary files: %v\n", err)
+		os.Exit(1)
+	}
+
+	program := tea.NewProgram(model, tea.WithAltScreen())
+
+	if _, err := program.Run(); err != nil {
+		fmt.Printf("❌ Error running TUI: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1 @@
+../../../internal/reporters/ruby/minitest/bin/wing_commander_runner.rb
\ No newline at end of file
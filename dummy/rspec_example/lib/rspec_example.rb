@@ -0,0 +1,8 @@
+# frozen_string_literal: true
+
+require_relative "rspec_example/helper"
+require_relative "rspec_example/worker"
+
+module RspecExample
+  class Error < StandardError; end
+end
@@ -0,0 +1,13 @@
+# frozen_string_literal: true
+
+module RspecExample
+  class Helper
+    def process(a, b, raise_error:)
+      if raise_error
+        raise "Error in Helper#process"
+      else
+        a + b
+      end
+    end
+  end
+end
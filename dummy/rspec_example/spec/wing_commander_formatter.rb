@@ -0,0 +1 @@
+../../../internal/reporters/ruby/rspec/spec/wing_commander_formatter.rb
\ No newline at end of file
@@ -0,0 +1,28 @@
+# frozen_string_literal: true
+
+RSpec.describe RspecExample::Worker do
+  subject(:worker) { described_class.new }
+
+  # Scenario 1: Production code error - Worker -> Helper backtrace
+  it "raises from production code" do
+    worker.addition(5, 3, raise_error: true) # Fails in Helper#process
+  end
+
+  # Scenario 2: Error in test definition
+  it "raises from the spec itself" do
+    undefined_variable # NameError in spec itself
+  end
+
+  # Scenario 3: Assertion failure - Worker -> Helper backtrace
+  it "adds numbers" do
+    expect(worker.addition(5, 3)).to eq(10) # Fails (expected 10, got 8)
+  end
+
+  it "succeeds" do
+    expect(true).to be(true)
+  end
+
+  it "is skipped" do
+    skip "This spec is skipped"
+  end
+end
@@ -15,6 +15,10 @@ type Backtrace struct {
 	Frames []types.StackFrame
 }
 
+// FrameParser parses a single backtrace line into a StackFrame.
+// Each test framework adapter supplies one matching its runtime's frame format.
+type FrameParser func(frameStr string) types.StackFrame
+
 // NewBacktrace creates a new empty Backtrace
 func NewBacktrace() Backtrace {
 	return Backtrace{
@@ -32,18 +36,23 @@ func (b *Backtrace) Append(frameStr string) {
 		return
 	}
 
-	frame := b.parseStackFrame(frameStr)
+	frame := ParseStackFrame(frameStr)
+	b.Frames = append(b.Frames, frame)
+}
+
+// AppendFrame adds an already parsed stack frame to the backtrace.
+func (b *Backtrace) AppendFrame(frame types.StackFrame) {
 	b.Frames = append(b.Frames, frame)
 }
 
-// parseStackFrame parses a backtrace frame string into a StackFrame.
+// ParseStackFrame parses a Ruby style backtrace frame string into a StackFrame.
 // Common formats:
 // - "app/models/user.rb:42:in `create_user'"
 // - "app/models/user.rb:42"
-func (b *Backtrace) parseStackFrame(frameStr string) types.StackFrame {
+func ParseStackFrame(frameStr string) types.StackFrame {
 	parts := strings.Split(frameStr, ":")
 	if len(parts) < 2 {
-		absPath, err := b.convertPath(frameStr)
+		absPath, err := ConvertPath(frameStr)
 		if err != nil {
 			log.Warn("failed to parse frame string", "frame", frameStr, "error", err)
 			// Return minimal StackFrame with empty path on error
@@ -61,7 +70,7 @@ func (b *Backtrace) parseStackFrame(frameStr string) types.StackFrame {
 	if len(parts) >= 2 {
 		// Parse line number
 		if _, err := fmt.Sscanf(parts[1], "%d", &line); err != nil {
-			absPath, err := b.convertPath(file)
+			absPath, err := ConvertPath(file)
 			if err != nil {
 				log.Warn("failed to parse frame string", "frame", frameStr, "error", err)
 			}
@@ -82,7 +91,7 @@ func (b *Backtrace) parseStackFrame(frameStr string) types.StackFrame {
 		}
 	}
 
-	absPath, err := b.convertPath(file)
+	absPath, err := ConvertPath(file)
 	if err != nil {
 		log.Warn("failed to convert path in frame string", "frame", frameStr, "error", err)
 		return types.StackFrame{}
@@ -95,18 +104,18 @@ func (b *Backtrace) parseStackFrame(frameStr string) types.StackFrame {
 	}
 }
 
-// convertPath converts a file path string to AbsPath.
+// ConvertPath converts a file path string to AbsPath.
 // If the path is relative, it uses ProjectFS to convert it to absolute.
-// If the path is already absolute, it uses NewAbsPath directly.
-func (b *Backtrace) convertPath(path string) (types.AbsPath, error) {
+// If the path is already absolute, it is rewritten by ProjectFS path mappings.
+func ConvertPath(path string) (types.AbsPath, error) {
 	cleaned := filepath.Clean(path)
+	fs := projectfs.GetProjectFS()
 
 	if filepath.IsAbs(cleaned) {
-		return types.NewAbsPath(cleaned)
+		return types.NewAbsPath(fs.MapPath(cleaned))
 	}
 
 	// Relative path - convert using ProjectFS
-	fs := projectfs.GetProjectFS()
 	relPath, err := types.NewRelPath(cleaned)
 	if err != nil {
 		return types.AbsPath(""), fmt.Errorf("failed to create RelPath: %w", err)
@@ -116,13 +125,14 @@ func (b *Backtrace) convertPath(path string) (types.AbsPath, error) {
 }
 
 // FilterProjectStackFramesOnly returns a new Backtrace containing only
-// stack frames whose file path is within the project's root path.
+// stack frames whose file path is within the project's root path and not
+// matched by an exclude pattern, e.g. gems vendored into the project.
 func (b Backtrace) FilterProjectStackFramesOnly() Backtrace {
 	filtered := NewBacktrace()
 	fs := projectfs.GetProjectFS()
 
 	for _, frame := range b.Frames {
-		if fs.IsProjectFile(frame.FilePath) {
+		if fs.IsProjectFile(frame.FilePath) && !fs.IsExcludedFile(frame.FilePath) {
 			filtered.Frames = append(filtered.Frames, frame)
 		}
 	}
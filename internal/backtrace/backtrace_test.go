@@ -158,6 +158,24 @@ func TestFilterProjectStackFramesOnly(t *testing.T) {
 	assert.Equal(t, types.AbsPath("/path/to/project/app/another.rb"), frames[1].FilePath)
 }
 
+func TestFilterProjectStackFramesOnly_ExcludePatterns(t *testing.T) {
+	rootPath, _ := types.NewAbsPath("/path/to/project")
+	err := projectfs.InitProjectFS(rootPath, "")
+	if err != nil {
+		t.Fatalf("failed to initialize ProjectFS: %v", err)
+	}
+	projectfs.GetProjectFS().SetExcludePatterns([]string{"/vendor/bundle/"})
+
+	bt := NewBacktrace()
+	bt.Append("/path/to/project/vendor/bundle/ruby/3.3.0/gems/rack-3.0.0/lib/rack.rb:5")
+	bt.Append("/path/to/project/app/test.rb:10")
+
+	frames := bt.FilterProjectStackFramesOnly().AllStackFrames()
+
+	assert.Len(t, frames, 1)
+	assert.Equal(t, types.AbsPath("/path/to/project/app/test.rb"), frames[0].FilePath)
+}
+
 func TestFilterProjectStackFramesOnly_EmptyBacktrace(t *testing.T) {
 	// Setup ProjectFS singleton for tests
 	rootPath, _ := types.NewAbsPath("/path/to/project")
@@ -0,0 +1,59 @@
+package backtrace
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/adamakhtar/wing_commander/internal/types"
+	"github.com/charmbracelet/log"
+)
+
+// goLocationRegex matches a Go source location at the start of a line, e.g.
+// "\t/src/app/worker.go:42 +0x1d" (goroutine dump) or "worker_test.go:12: msg" (t.Error output).
+var goLocationRegex = regexp.MustCompile(`^\s*(\S+\.go):(\d+)(?::|\s|$)`)
+
+// ParseGoStackFrame parses a Go backtrace frame into a StackFrame.
+// A frame is either a bare location or, for goroutine dumps, the function line
+// and location line joined by a newline:
+// - "example.com/app/worker.(*Worker).Run(0x0?)\n\t/src/app/worker.go:42 +0x1d"
+// - "/src/app/worker_test.go:12"
+func ParseGoStackFrame(frameStr string) types.StackFrame {
+	var function string
+	location := frameStr
+	if i := strings.LastIndex(frameStr, "\n"); i >= 0 {
+		function = GoFunctionName(frameStr[:i])
+		location = frameStr[i+1:]
+	}
+
+	matches := goLocationRegex.FindStringSubmatch(location)
+	if matches == nil {
+		log.Warn("failed to parse go frame string", "frame", frameStr)
+		return types.StackFrame{}
+	}
+
+	line, _ := strconv.Atoi(matches[2])
+	absPath, err := ConvertPath(matches[1])
+	if err != nil {
+		log.Warn("failed to convert path in go frame string", "frame", frameStr, "error", err)
+		return types.StackFrame{}
+	}
+
+	return types.StackFrame{
+		FilePath: absPath,
+		Line:     line,
+		Function: function,
+	}
+}
+
+// GoFunctionName strips the argument list from a goroutine dump function line,
+// e.g. "example.com/app.(*Worker).Run(0x0?)" becomes "example.com/app.(*Worker).Run".
+func GoFunctionName(line string) string {
+	line = strings.TrimSpace(line)
+	if strings.HasSuffix(line, ")") {
+		if i := strings.LastIndex(line, "("); i > 0 {
+			return line[:i]
+		}
+	}
+	return line
+}
@@ -0,0 +1,54 @@
+package backtrace
+
+import (
+	"testing"
+
+	"github.com/adamakhtar/wing_commander/internal/projectfs"
+	"github.com/adamakhtar/wing_commander/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseGoStackFrame(t *testing.T) {
+	rootPath, _ := types.NewAbsPath("/path/to/project")
+	if err := projectfs.InitProjectFS(rootPath, ""); err != nil {
+		t.Fatalf("failed to initialize ProjectFS: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		frameStr string
+		want     types.StackFrame
+	}{
+		{
+			name:     "goroutine dump frame",
+			frameStr: "example.com/app/worker.(*Worker).Run(0xc000012345, 0x0?)\n\t/src/app/worker.go:42 +0x1d",
+			want:     types.StackFrame{FilePath: "/src/app/worker.go", Line: 42, Function: "example.com/app/worker.(*Worker).Run"},
+		},
+		{
+			name:     "inlined frame without offset",
+			frameStr: "example.com/app/worker.helper(...)\n\t/src/app/helper.go:7",
+			want:     types.StackFrame{FilePath: "/src/app/helper.go", Line: 7, Function: "example.com/app/worker.helper"},
+		},
+		{
+			name:     "relative location",
+			frameStr: "worker/worker_test.go:12",
+			want:     types.StackFrame{FilePath: "/path/to/project/worker/worker_test.go", Line: 12},
+		},
+		{
+			name:     "t.Error output line",
+			frameStr: "    worker/worker_test.go:12: expected 2, got 3",
+			want:     types.StackFrame{FilePath: "/path/to/project/worker/worker_test.go", Line: 12},
+		},
+		{
+			name:     "not a frame",
+			frameStr: "goroutine 7 [running]:",
+			want:     types.StackFrame{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ParseGoStackFrame(tt.frameStr))
+		})
+	}
+}
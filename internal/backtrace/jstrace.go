@@ -0,0 +1,49 @@
+package backtrace
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/adamakhtar/wing_commander/internal/types"
+	"github.com/charmbracelet/log"
+)
+
+var (
+	// jsCallSiteRegex matches a V8 frame with a function name: "at Cart.total (/src/app/cart.js:10:5)".
+	jsCallSiteRegex = regexp.MustCompile(`^\s*at (?:async )?(.+?) \((.+):(\d+):(\d+)\)$`)
+	// jsLocationRegex matches an anonymous V8 frame: "at /src/app/cart.js:10:5".
+	jsLocationRegex = regexp.MustCompile(`^\s*at (?:async )?(.+):(\d+):(\d+)$`)
+)
+
+// ParseJSStackFrame parses a V8 (Node.js) stack frame into a StackFrame.
+// Common formats:
+// - "at Cart.total (/src/app/cart.js:10:5)"
+// - "at async Object.<anonymous> (file:///src/app/cart.test.ts:4:3)"
+// - "at /src/app/cart.js:10:5"
+func ParseJSStackFrame(frameStr string) types.StackFrame {
+	var function, file, line, column string
+	if matches := jsCallSiteRegex.FindStringSubmatch(frameStr); matches != nil {
+		function, file, line, column = matches[1], matches[2], matches[3], matches[4]
+	} else if matches := jsLocationRegex.FindStringSubmatch(frameStr); matches != nil {
+		file, line, column = matches[1], matches[2], matches[3]
+	} else {
+		log.Warn("failed to parse js frame string", "frame", frameStr)
+		return types.StackFrame{}
+	}
+
+	absPath, err := ConvertPath(strings.TrimPrefix(file, "file://"))
+	if err != nil {
+		log.Warn("failed to convert path in js frame string", "frame", frameStr, "error", err)
+		return types.StackFrame{}
+	}
+
+	lineNumber, _ := strconv.Atoi(line)
+	columnNumber, _ := strconv.Atoi(column)
+	return types.StackFrame{
+		FilePath: absPath,
+		Line:     lineNumber,
+		Column:   columnNumber,
+		Function: function,
+	}
+}
@@ -0,0 +1,54 @@
+package backtrace
+
+import (
+	"testing"
+
+	"github.com/adamakhtar/wing_commander/internal/projectfs"
+	"github.com/adamakhtar/wing_commander/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseJSStackFrame(t *testing.T) {
+	rootPath, _ := types.NewAbsPath("/path/to/project")
+	if err := projectfs.InitProjectFS(rootPath, ""); err != nil {
+		t.Fatalf("failed to initialize ProjectFS: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		frameStr string
+		want     types.StackFrame
+	}{
+		{
+			name:     "named frame",
+			frameStr: "    at Cart.total (/src/app/cart.js:10:5)",
+			want:     types.StackFrame{FilePath: "/src/app/cart.js", Line: 10, Column: 5, Function: "Cart.total"},
+		},
+		{
+			name:     "async frame with file url",
+			frameStr: "at async Object.<anonymous> (file:///src/app/cart.test.ts:4:3)",
+			want:     types.StackFrame{FilePath: "/src/app/cart.test.ts", Line: 4, Column: 3, Function: "Object.<anonymous>"},
+		},
+		{
+			name:     "constructor frame",
+			frameStr: "at new Cart (src/cart.js:2:11)",
+			want:     types.StackFrame{FilePath: "/path/to/project/src/cart.js", Line: 2, Column: 11, Function: "new Cart"},
+		},
+		{
+			name:     "anonymous frame",
+			frameStr: "    at /src/app/cart.js:10:5",
+			want:     types.StackFrame{FilePath: "/src/app/cart.js", Line: 10, Column: 5},
+		},
+		{
+			name:     "not a frame",
+			frameStr: "Expected: 85",
+			want:     types.StackFrame{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ParseJSStackFrame(tt.frameStr))
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package backtrace
+
+import (
+	"regexp"
+	"strconv"
+
+	"github.com/adamakhtar/wing_commander/internal/types"
+	"github.com/charmbracelet/log"
+)
+
+var (
+	// pyTracebackRegex matches a Python traceback frame: `File "/src/app/cart.py", line 12, in total`.
+	pyTracebackRegex = regexp.MustCompile(`^\s*File "([^"]+)", line (\d+)(?:, in (.+))?`)
+	// pyLocationRegex matches a pytest location line: "tests/test_cart.py:12: AssertionError".
+	pyLocationRegex = regexp.MustCompile(`^\s*(\S+\.py):(\d+)(?::|\s|$)`)
+)
+
+// ParsePythonStackFrame parses a Python backtrace frame into a StackFrame.
+// Common formats:
+// - `File "/src/app/cart.py", line 12, in total` (Python traceback)
+// - "tests/test_cart.py:12: AssertionError" (pytest's long traceback style)
+func ParsePythonStackFrame(frameStr string) types.StackFrame {
+	var file string
+	var line int
+	var function string
+
+	if matches := pyTracebackRegex.FindStringSubmatch(frameStr); matches != nil {
+		file = matches[1]
+		line, _ = strconv.Atoi(matches[2])
+		function = matches[3]
+	} else if matches := pyLocationRegex.FindStringSubmatch(frameStr); matches != nil {
+		file = matches[1]
+		line, _ = strconv.Atoi(matches[2])
+	} else {
+		log.Warn("failed to parse python frame string", "frame", frameStr)
+		return types.StackFrame{}
+	}
+
+	absPath, err := ConvertPath(file)
+	if err != nil {
+		log.Warn("failed to convert path in python frame string", "frame", frameStr, "error", err)
+		return types.StackFrame{}
+	}
+
+	return types.StackFrame{
+		FilePath: absPath,
+		Line:     line,
+		Function: function,
+	}
+}
@@ -0,0 +1,59 @@
+package backtrace
+
+import (
+	"testing"
+
+	"github.com/adamakhtar/wing_commander/internal/projectfs"
+	"github.com/adamakhtar/wing_commander/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePythonStackFrame(t *testing.T) {
+	rootPath, _ := types.NewAbsPath("/path/to/project")
+	if err := projectfs.InitProjectFS(rootPath, ""); err != nil {
+		t.Fatalf("failed to initialize ProjectFS: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		frameStr string
+		want     types.StackFrame
+	}{
+		{
+			name:     "traceback frame",
+			frameStr: `  File "/src/shop/cart.py", line 12, in total`,
+			want:     types.StackFrame{FilePath: "/src/shop/cart.py", Line: 12, Function: "total"},
+		},
+		{
+			name:     "relative traceback frame",
+			frameStr: `File "shop/cart.py", line 8, in <module>`,
+			want:     types.StackFrame{FilePath: "/path/to/project/shop/cart.py", Line: 8, Function: "<module>"},
+		},
+		{
+			name:     "traceback frame without function",
+			frameStr: `File "shop/cart.py", line 8`,
+			want:     types.StackFrame{FilePath: "/path/to/project/shop/cart.py", Line: 8},
+		},
+		{
+			name:     "pytest location",
+			frameStr: "tests/test_cart.py:9: AssertionError",
+			want:     types.StackFrame{FilePath: "/path/to/project/tests/test_cart.py", Line: 9},
+		},
+		{
+			name:     "pytest location without exception",
+			frameStr: "tests/test_cart.py:16:",
+			want:     types.StackFrame{FilePath: "/path/to/project/tests/test_cart.py", Line: 16},
+		},
+		{
+			name:     "not a frame",
+			frameStr: "E       assert 90 == 85",
+			want:     types.StackFrame{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ParsePythonStackFrame(tt.frameStr))
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -15,15 +16,43 @@ type TestFramework string
 
 const (
 	FrameworkMinitest TestFramework = "minitest"
+	FrameworkRSpec    TestFramework = "rspec"
+	FrameworkGoTest   TestFramework = "go"
+	FrameworkPytest   TestFramework = "pytest"
+	FrameworkJest     TestFramework = "jest"
+	FrameworkVitest   TestFramework = "vitest"
 )
 
+// supportedFrameworks lists the frameworks that have a registered adapter.
+var supportedFrameworks = []TestFramework{
+	FrameworkMinitest,
+	FrameworkRSpec,
+	FrameworkGoTest,
+	FrameworkPytest,
+	FrameworkJest,
+	FrameworkVitest,
+}
+
 const (
 	defaultConfigDir       = ".wing_commander"
 	defaultConfigFile      = "config.yml"
 	defaultResultsPath     = ".wing_commander/test_results/summary.yml"
+	defaultStreamPath      = ".wing_commander/test_results/stream.jsonl"
+	defaultHungTestPeriod  = 30
+	defaultHistoryPath     = ".wing_commander/history"
+	defaultHistoryLimit    = 50
 	defaultMinitestCommand = "bundle exec rake test {{.Paths}}"
+	defaultRSpecCommand    = "bundle exec rspec"
+	defaultGoTestCommand   = "go test -json"
+	defaultPytestCommand   = "pytest --junitxml=.wing_commander/test_results/junit.xml"
+	defaultJestCommand     = "npx jest --json --outputFile=.wing_commander/test_results/jest.json"
+	defaultVitestCommand   = "npx vitest run --reporter=json --outputFile=.wing_commander/test_results/vitest.json"
 )
 
+// defaultTestFailureExitCodes are the exit codes test commands use to say the
+// suite ran but some tests failed.
+var defaultTestFailureExitCodes = []int{1}
+
 var defaultExcludePatterns = []string{
 	"/gems/",
 	"/lib/ruby/",
@@ -32,13 +61,64 @@ var defaultExcludePatterns = []string{
 
 // Config represents the Wing Commander configuration.
 type Config struct {
-	TestFramework      TestFramework `yaml:"test_framework"`
-	TestCommand        string        `yaml:"test_command"`
-	RunTestCaseCommand string        `yaml:"run_test_case_command"`
-	TestFilePattern    string        `yaml:"test_file_pattern"`
-	TestResultsPath    string        `yaml:"test_results_path"`
-	Debug              bool          `yaml:"debug"`
-	ExcludePatterns    []string      `yaml:"exclude_patterns"`
+	TestFramework        TestFramework   `yaml:"test_framework"`
+	TestCommand          string          `yaml:"test_command"`
+	RunTestCaseCommand   string          `yaml:"run_test_case_command"`
+	TestFilePattern      string          `yaml:"test_file_pattern"`
+	TestResultsPath      string          `yaml:"test_results_path"`
+	TestStreamPath       string          `yaml:"test_stream_path"`
+	HungTestQuietPeriod  int             `yaml:"hung_test_quiet_period"`
+	TestFailureExitCodes []int           `yaml:"test_failure_exit_codes"`
+	HistoryLimit         int             `yaml:"history_limit"`
+	RetryFailures        int             `yaml:"retry_failures"`
+	Debug                bool            `yaml:"debug"`
+	ExcludePatterns      []string        `yaml:"exclude_patterns"`
+	PathMappings         []PathMapping   `yaml:"path_mappings,omitempty"`
+	GemsPath             string          `yaml:"gems_path,omitempty"`
+	ExecutionTarget      ExecutionTarget `yaml:"execution_target,omitempty"`
+	// DisableReporterInjection stops the runner loading the Minitest reporter
+	// into test processes, for projects that register it themselves.
+	DisableReporterInjection bool `yaml:"disable_reporter_injection,omitempty"`
+
+	// sources records where settings that aren't defaults came from, keyed
+	// by setting name.
+	sources map[string]Source
+}
+
+// ExecutionTarget runs the test command somewhere other than the host, e.g. in
+// a Docker Compose service that has the project mounted.
+type ExecutionTarget struct {
+	// CommandPrefix is prepended to every test command, e.g. "docker compose exec -T web".
+	CommandPrefix string `yaml:"command_prefix"`
+	// ProjectPath is the project root inside the target, e.g. /app. Paths are
+	// translated between it and the host project root.
+	ProjectPath string `yaml:"project_path"`
+}
+
+// IsLocal reports whether test commands run directly on the host.
+func (t ExecutionTarget) IsLocal() bool {
+	return t.CommandPrefix == ""
+}
+
+// PathMapping rewrites absolute paths in test results produced on another
+// machine or in a container, e.g. /app to the project root. A relative To is
+// resolved against the project root.
+type PathMapping struct {
+	From string `yaml:"from"`
+	To   string `yaml:"to"`
+}
+
+// ParsePathMapping parses a mapping written as "FROM=TO", e.g. "/app=.".
+func ParsePathMapping(value string) (PathMapping, error) {
+	from, to, ok := strings.Cut(value, "=")
+	from, to = strings.TrimSpace(from), strings.TrimSpace(to)
+	if !ok || from == "" || to == "" {
+		return PathMapping{}, fmt.Errorf("invalid path mapping %q, expected FROM=TO", value)
+	}
+	if !filepath.IsAbs(from) {
+		return PathMapping{}, fmt.Errorf("invalid path mapping %q, FROM must be an absolute path", value)
+	}
+	return PathMapping{From: from, To: to}, nil
 }
 
 // NewConfig creates a new configuration instance, applying sensible defaults for
@@ -49,9 +129,8 @@ func NewConfig(testCommand string, testFilePattern string, testResultsPath strin
 	if testCommand != "" {
 		cfg.TestCommand = testCommand
 	}
-	if runTestCaseCommand != "" {
-		cfg.RunTestCaseCommand = runTestCaseCommand
-	}
+	// Re-runs fall back to the test command given here, not the default one
+	cfg.RunTestCaseCommand = runTestCaseCommand
 	if testFilePattern != "" {
 		cfg.TestFilePattern = testFilePattern
 	}
@@ -67,13 +146,17 @@ func NewConfig(testCommand string, testFilePattern string, testResultsPath strin
 // DefaultConfig returns the baseline configuration for the Wing Commander CLI.
 func DefaultConfig() *Config {
 	cfg := &Config{
-		TestFramework:      FrameworkMinitest,
-		TestCommand:        defaultMinitestCommand,
-		RunTestCaseCommand: "",
-		TestFilePattern:    "",
-		TestResultsPath:    defaultResultsPath,
-		Debug:              false,
-		ExcludePatterns:    append([]string{}, defaultExcludePatterns...),
+		TestFramework:        FrameworkMinitest,
+		TestCommand:          defaultMinitestCommand,
+		RunTestCaseCommand:   "",
+		TestFilePattern:      "",
+		TestResultsPath:      defaultResultsPath,
+		TestStreamPath:       defaultStreamPath,
+		HungTestQuietPeriod:  defaultHungTestPeriod,
+		TestFailureExitCodes: append([]int{}, defaultTestFailureExitCodes...),
+		HistoryLimit:         defaultHistoryLimit,
+		Debug:                false,
+		ExcludePatterns:      append([]string{}, defaultExcludePatterns...),
 	}
 
 	cfg.ensureRunTestCaseCommand()
@@ -81,7 +164,8 @@ func DefaultConfig() *Config {
 }
 
 // LoadConfig reads configuration from disk, falling back to defaults if the file
-// does not exist. Missing fields are backfilled with defaults.
+// does not exist. Missing fields are backfilled with defaults, and settings
+// read from the file are recorded with SourceFile.
 func LoadConfig(path string) (*Config, error) {
 	cfg := DefaultConfig()
 
@@ -113,43 +197,145 @@ func LoadConfig(path string) (*Config, error) {
 
 	if loaded.TestFramework != "" {
 		cfg.TestFramework = loaded.TestFramework
+		cfg.SetSource(SettingTestFramework, SourceFile)
 	}
 	if loaded.TestCommand != "" {
 		cfg.TestCommand = loaded.TestCommand
+		cfg.SetSource(SettingTestCommand, SourceFile)
 	}
 	if loaded.RunTestCaseCommand != "" {
 		cfg.RunTestCaseCommand = loaded.RunTestCaseCommand
+		cfg.SetSource(SettingRunTestCaseCommand, SourceFile)
 	}
 	if loaded.TestFilePattern != "" {
 		cfg.TestFilePattern = loaded.TestFilePattern
+		cfg.SetSource(SettingTestFilePattern, SourceFile)
 	}
 	if loaded.TestResultsPath != "" {
 		cfg.TestResultsPath = loaded.TestResultsPath
+		cfg.SetSource(SettingTestResultsPath, SourceFile)
+	}
+	if loaded.TestStreamPath != "" {
+		cfg.TestStreamPath = loaded.TestStreamPath
+		cfg.SetSource(SettingTestStreamPath, SourceFile)
+	}
+	if loaded.HungTestQuietPeriod != 0 {
+		cfg.HungTestQuietPeriod = loaded.HungTestQuietPeriod
+		cfg.SetSource(SettingHungTestQuietPeriod, SourceFile)
+	}
+	if len(loaded.TestFailureExitCodes) > 0 {
+		cfg.TestFailureExitCodes = loaded.TestFailureExitCodes
+		cfg.SetSource(SettingTestFailureExitCodes, SourceFile)
+	}
+	if loaded.HistoryLimit != 0 {
+		cfg.HistoryLimit = loaded.HistoryLimit
+		cfg.SetSource(SettingHistoryLimit, SourceFile)
+	}
+	if loaded.RetryFailures != 0 {
+		cfg.RetryFailures = loaded.RetryFailures
+		cfg.SetSource(SettingRetryFailures, SourceFile)
 	}
 	if len(loaded.ExcludePatterns) > 0 {
 		cfg.ExcludePatterns = loaded.ExcludePatterns
+		cfg.SetSource(SettingExcludePatterns, SourceFile)
+	}
+	for _, mapping := range loaded.PathMappings {
+		if _, err := ParsePathMapping(mapping.From + "=" + mapping.To); err != nil {
+			return nil, fmt.Errorf("invalid path_mappings in config file %s: %w", configPath, err)
+		}
+	}
+	if len(loaded.PathMappings) > 0 {
+		cfg.PathMappings = loaded.PathMappings
+		cfg.SetSource(SettingPathMappings, SourceFile)
+	}
+	if loaded.GemsPath != "" {
+		cfg.GemsPath = loaded.GemsPath
+		cfg.SetSource(SettingGemsPath, SourceFile)
+	}
+	if loaded.ExecutionTarget.ProjectPath != "" && !filepath.IsAbs(loaded.ExecutionTarget.ProjectPath) {
+		return nil, fmt.Errorf("invalid execution_target in config file %s: project_path must be an absolute path", configPath)
+	}
+	if loaded.ExecutionTarget.CommandPrefix != "" {
+		cfg.ExecutionTarget.CommandPrefix = loaded.ExecutionTarget.CommandPrefix
+		cfg.SetSource(SettingCommandPrefix, SourceFile)
+	}
+	if loaded.ExecutionTarget.ProjectPath != "" {
+		cfg.ExecutionTarget.ProjectPath = loaded.ExecutionTarget.ProjectPath
+		cfg.SetSource(SettingTargetProjectPath, SourceFile)
+	}
+	if loaded.DisableReporterInjection {
+		cfg.DisableReporterInjection = true
+		cfg.SetSource(SettingDisableReporterInjection, SourceFile)
 	}
 	if loaded.Debug {
 		cfg.Debug = true
+		cfg.SetSource(SettingDebug, SourceFile)
 	}
 
-	cfg.ensureRunTestCaseCommand()
+	cfg.ResolveDefaults()
 	return cfg, nil
 }
 
-// SaveConfig writes the current configuration to the default location on disk.
-func SaveConfig(cfg *Config) error {
+// HistoryEnabled reports whether finished test runs are kept in the history.
+func (c *Config) HistoryEnabled() bool {
+	return c.HistoryLimit > 0
+}
+
+// HistoryPath returns the directory, relative to the project root, that test
+// runs are kept in.
+func HistoryPath() string {
+	return defaultHistoryPath
+}
+
+// RetryAttempts returns how many times failed tests are re-run after a test
+// run, zero when retries are off.
+func (c *Config) RetryAttempts() int {
+	return max(c.RetryFailures, 0)
+}
+
+// HungTestTimeout returns how long a started test may go without the results
+// stream changing before it is reported as hung. A negative
+// hung_test_quiet_period disables detection and returns zero.
+func (c *Config) HungTestTimeout() time.Duration {
+	if c.HungTestQuietPeriod <= 0 {
+		return 0
+	}
+	return time.Duration(c.HungTestQuietPeriod) * time.Second
+}
+
+// FailureExitCodes returns the exit codes meaning the suite ran with failing
+// tests. Exit code 0 means it passed, any other code that it crashed.
+func (c *Config) FailureExitCodes() []int {
+	if len(c.TestFailureExitCodes) == 0 {
+		return defaultTestFailureExitCodes
+	}
+	return c.TestFailureExitCodes
+}
+
+// DefaultConfigPath returns the path of the config file relative to the
+// project root.
+func DefaultConfigPath() string {
+	return filepath.Join(defaultConfigDir, defaultConfigFile)
+}
+
+// SaveConfig writes the configuration to path, or to the default location
+// when path is empty, creating its directory as needed.
+func SaveConfig(cfg *Config, path string) error {
 	if cfg == nil {
 		return fmt.Errorf("cannot save nil config")
 	}
 
 	cfg.ensureRunTestCaseCommand()
 
-	if err := os.MkdirAll(defaultConfigDir, 0o755); err != nil {
-		return fmt.Errorf("failed to create config directory %s: %w", defaultConfigDir, err)
+	configPath := path
+	if configPath == "" {
+		configPath = DefaultConfigPath()
 	}
 
-	configPath := filepath.Join(defaultConfigDir, defaultConfigFile)
+	configDir := filepath.Dir(configPath)
+	if err := os.MkdirAll(configDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create config directory %s: %w", configDir, err)
+	}
 
 	data, err := yaml.Marshal(cfg)
 	if err != nil {
@@ -165,17 +351,36 @@ func SaveConfig(cfg *Config) error {
 
 // ValidateFramework ensures that the provided framework is supported.
 func ValidateFramework(value string) (TestFramework, error) {
-	switch strings.ToLower(strings.TrimSpace(value)) {
-	case "", string(FrameworkMinitest):
+	normalized := TestFramework(strings.ToLower(strings.TrimSpace(value)))
+	if normalized == "" {
 		return FrameworkMinitest, nil
-	default:
-		return FrameworkMinitest, fmt.Errorf("unsupported test framework: %s (Wing Commander currently requires WingCommanderReporter YAML output)", value)
 	}
+
+	for _, framework := range supportedFrameworks {
+		if framework == normalized {
+			return framework, nil
+		}
+	}
+
+	return FrameworkMinitest, fmt.Errorf("unsupported test framework: %s (supported: %v)", value, supportedFrameworks)
 }
 
 // GetDefaultTestCommand returns the WingCommanderReporter-aware test command.
 func GetDefaultTestCommand(framework TestFramework) string {
-	return defaultMinitestCommand
+	switch framework {
+	case FrameworkRSpec:
+		return defaultRSpecCommand
+	case FrameworkGoTest:
+		return defaultGoTestCommand
+	case FrameworkPytest:
+		return defaultPytestCommand
+	case FrameworkJest:
+		return defaultJestCommand
+	case FrameworkVitest:
+		return defaultVitestCommand
+	default:
+		return defaultMinitestCommand
+	}
 }
 
 func (cfg *Config) ensureRunTestCaseCommand() {
@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -16,6 +17,8 @@ func TestDefaultConfig(t *testing.T) {
 	assert.Equal(t, defaultMinitestCommand, config.TestCommand)
 	assert.Equal(t, config.TestCommand, config.RunTestCaseCommand)
 	assert.Equal(t, defaultResultsPath, config.TestResultsPath)
+	assert.Equal(t, defaultStreamPath, config.TestStreamPath)
+	assert.Equal(t, 30*time.Second, config.HungTestTimeout())
 	assert.NotEmpty(t, config.ExcludePatterns)
 	assert.Contains(t, config.ExcludePatterns, "/gems/")
 	assert.Contains(t, config.ExcludePatterns, "/lib/ruby/")
@@ -44,6 +47,10 @@ test_results_path: "/tmp/project/.wing_commander/test_results/summary.yml"
 exclude_patterns:
   - "/gems/"
   - "/custom/"
+path_mappings:
+  - from: /app
+    to: .
+gems_path: vendor/bundle/ruby/3.3.0/gems
 run_test_case_command: "bundle exec ruby -Itest %{test_case_name}"`
 
 	err = os.WriteFile(configPath, []byte(configContent), 0o644)
@@ -62,6 +69,8 @@ run_test_case_command: "bundle exec ruby -Itest %{test_case_name}"`
 	assert.Equal(t, "bundle exec ruby -Itest %{test_case_name}", config.RunTestCaseCommand)
 	assert.Equal(t, "/tmp/project/.wing_commander/test_results/summary.yml", config.TestResultsPath)
 	assert.Equal(t, []string{"/gems/", "/custom/"}, config.ExcludePatterns)
+	assert.Equal(t, []PathMapping{{From: "/app", To: "."}}, config.PathMappings)
+	assert.Equal(t, "vendor/bundle/ruby/3.3.0/gems", config.GemsPath)
 }
 
 func TestLoadConfig_InvalidYAML(t *testing.T) {
@@ -95,7 +104,7 @@ func TestSaveConfig(t *testing.T) {
 		ExcludePatterns:    []string{"/vendor/bundle/"},
 	}
 
-	err := SaveConfig(cfg)
+	err := SaveConfig(cfg, "")
 	require.NoError(t, err)
 
 	defer func() {
@@ -111,6 +120,22 @@ func TestSaveConfig(t *testing.T) {
 	assert.Equal(t, cfg.ExcludePatterns, loaded.ExcludePatterns)
 }
 
+func TestSaveConfigToPath(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), ".wing_commander", "config.yml")
+	cfg := DefaultConfig()
+	cfg.TestFramework = FrameworkRSpec
+
+	require.NoError(t, SaveConfig(cfg, configPath))
+
+	data, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "execution_target", "unset optional settings are left out")
+
+	loaded, err := LoadConfig(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, FrameworkRSpec, loaded.TestFramework)
+}
+
 func TestValidateFramework(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -128,9 +153,34 @@ func TestValidateFramework(t *testing.T) {
 			input: "minitest",
 			want:  FrameworkMinitest,
 		},
+		{
+			name:  "rspec supported",
+			input: "RSpec",
+			want:  FrameworkRSpec,
+		},
+		{
+			name:  "go supported",
+			input: "go",
+			want:  FrameworkGoTest,
+		},
+		{
+			name:  "pytest supported",
+			input: "pytest",
+			want:  FrameworkPytest,
+		},
+		{
+			name:  "jest supported",
+			input: "jest",
+			want:  FrameworkJest,
+		},
+		{
+			name:  "vitest supported",
+			input: "vitest",
+			want:  FrameworkVitest,
+		},
 		{
 			name:    "other frameworks rejected",
-			input:   "rspec",
+			input:   "jasmine",
 			want:    FrameworkMinitest,
 			wantErr: true,
 		},
@@ -151,7 +201,11 @@ func TestValidateFramework(t *testing.T) {
 
 func TestGetDefaultTestCommand(t *testing.T) {
 	assert.Equal(t, defaultMinitestCommand, GetDefaultTestCommand(FrameworkMinitest))
-	assert.Equal(t, defaultMinitestCommand, GetDefaultTestCommand(TestFramework("rspec")))
+	assert.Equal(t, defaultRSpecCommand, GetDefaultTestCommand(FrameworkRSpec))
+	assert.Equal(t, defaultGoTestCommand, GetDefaultTestCommand(FrameworkGoTest))
+	assert.Equal(t, defaultPytestCommand, GetDefaultTestCommand(FrameworkPytest))
+	assert.Equal(t, defaultJestCommand, GetDefaultTestCommand(FrameworkJest))
+	assert.Equal(t, defaultVitestCommand, GetDefaultTestCommand(FrameworkVitest))
 }
 
 func TestConfigWithMissingFieldsUsesDefaults(t *testing.T) {
@@ -177,9 +231,44 @@ func TestConfigWithMissingFieldsUsesDefaults(t *testing.T) {
 	assert.Equal(t, defaultMinitestCommand, config.TestCommand)
 	assert.Equal(t, config.TestCommand, config.RunTestCaseCommand)
 	assert.Equal(t, defaultResultsPath, config.TestResultsPath)
+	assert.Equal(t, defaultStreamPath, config.TestStreamPath)
+	assert.Equal(t, defaultHungTestPeriod, config.HungTestQuietPeriod)
+	assert.Equal(t, []int{1}, config.FailureExitCodes())
+	assert.Equal(t, defaultHistoryLimit, config.HistoryLimit)
+	assert.True(t, config.HistoryEnabled())
+	assert.Zero(t, config.RetryAttempts())
 	assert.Equal(t, defaultExcludePatterns, config.ExcludePatterns)
 }
 
+func TestFailureExitCodesFallBackToDefault(t *testing.T) {
+	cfg := &Config{}
+	assert.Equal(t, []int{1}, cfg.FailureExitCodes())
+
+	cfg.TestFailureExitCodes = []int{1, 5}
+	assert.Equal(t, []int{1, 5}, cfg.FailureExitCodes())
+}
+
+func TestHungTestTimeoutDisabledWhenNegative(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.HungTestQuietPeriod = -1
+	assert.Zero(t, cfg.HungTestTimeout())
+}
+
+func TestHistoryDisabledWhenNegative(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.HistoryLimit = -1
+	assert.False(t, cfg.HistoryEnabled())
+}
+
+func TestRetryAttemptsDisabledWhenNegative(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.RetryFailures = -1
+	assert.Zero(t, cfg.RetryAttempts())
+
+	cfg.RetryFailures = 2
+	assert.Equal(t, 2, cfg.RetryAttempts())
+}
+
 func TestRunTestCaseCommandFallsBackToTestCommand(t *testing.T) {
 	cfg := NewConfig("bundle exec rake test", "", defaultResultsPath, "", false)
 	assert.Equal(t, cfg.TestCommand, cfg.RunTestCaseCommand)
@@ -189,3 +278,14 @@ func TestRunTestCaseCommandOverride(t *testing.T) {
 	cfg := NewConfig("bundle exec rake test", "", defaultResultsPath, "bundle exec ruby -Itest %{test_case_name}", false)
 	assert.Equal(t, "bundle exec ruby -Itest %{test_case_name}", cfg.RunTestCaseCommand)
 }
+
+func TestParsePathMapping(t *testing.T) {
+	mapping, err := ParsePathMapping("/app = .")
+	require.NoError(t, err)
+	assert.Equal(t, PathMapping{From: "/app", To: "."}, mapping)
+
+	for _, value := range []string{"/app", "/app=", "=.", "app=."} {
+		_, err := ParsePathMapping(value)
+		assert.Error(t, err, value)
+	}
+}
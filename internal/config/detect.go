@@ -0,0 +1,55 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// defaultTestFilePatterns are the globs matching each framework's test files
+// by convention.
+var defaultTestFilePatterns = map[TestFramework]string{
+	FrameworkMinitest: "test/{*_test.rb,**/*_test.rb}",
+	FrameworkRSpec:    "spec/{*_spec.rb,**/*_spec.rb}",
+	FrameworkGoTest:   "{*_test.go,**/*_test.go}",
+	FrameworkPytest:   "{test_*.py,**/test_*.py}",
+	FrameworkJest:     "**/*.{test,spec}.{js,jsx,ts,tsx}",
+	FrameworkVitest:   "**/*.{test,spec}.{js,jsx,ts,tsx}",
+}
+
+// GetDefaultTestFilePattern returns the glob matching the framework's test
+// files by convention.
+func GetDefaultTestFilePattern(framework TestFramework) string {
+	return defaultTestFilePatterns[framework]
+}
+
+// DetectFramework guesses the test framework of the project at root from the
+// files it contains: .rspec or an rspec gem, a Gemfile, go.mod, a package.json
+// depending on vitest or jest, or pytest's config files. The second value is
+// false when nothing points to a supported framework.
+func DetectFramework(root string) (TestFramework, bool) {
+	exists := func(name string) bool {
+		_, err := os.Stat(filepath.Join(root, name))
+		return err == nil
+	}
+	contains := func(name, substr string) bool {
+		data, err := os.ReadFile(filepath.Join(root, name))
+		return err == nil && strings.Contains(string(data), substr)
+	}
+
+	switch {
+	case exists(".rspec"), contains("Gemfile", `"rspec`), contains("Gemfile", `'rspec`):
+		return FrameworkRSpec, true
+	case exists("Gemfile"):
+		return FrameworkMinitest, true
+	case exists("go.mod"):
+		return FrameworkGoTest, true
+	case contains("package.json", `"vitest"`):
+		return FrameworkVitest, true
+	case contains("package.json", `"jest"`):
+		return FrameworkJest, true
+	case exists("pytest.ini"), exists("conftest.py"), contains("pyproject.toml", "pytest"):
+		return FrameworkPytest, true
+	}
+	return "", false
+}
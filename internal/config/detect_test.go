@@ -0,0 +1,60 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gobwas/glob"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectFramework(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  TestFramework
+	}{
+		{"rspec config", map[string]string{".rspec": "--require spec_helper\n", "Gemfile": ""}, FrameworkRSpec},
+		{"rspec gem", map[string]string{"Gemfile": "gem \"rspec-rails\"\n"}, FrameworkRSpec},
+		{"gemfile", map[string]string{"Gemfile": "gem 'minitest'\n"}, FrameworkMinitest},
+		{"go module", map[string]string{"go.mod": "module example.com/app\n"}, FrameworkGoTest},
+		{"vitest", map[string]string{"package.json": `{"devDependencies": {"vitest": "^1.0.0"}}`}, FrameworkVitest},
+		{"jest", map[string]string{"package.json": `{"devDependencies": {"jest": "^29.0.0"}}`}, FrameworkJest},
+		{"pytest", map[string]string{"pyproject.toml": "[tool.pytest.ini_options]\n"}, FrameworkPytest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			for name, content := range tt.files {
+				require.NoError(t, os.WriteFile(filepath.Join(root, name), []byte(content), 0o644))
+			}
+
+			framework, ok := DetectFramework(root)
+			assert.True(t, ok)
+			assert.Equal(t, tt.want, framework)
+		})
+	}
+}
+
+func TestDetectFrameworkUnknown(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "package.json"), []byte(`{"name": "app"}`), 0o644))
+
+	_, ok := DetectFramework(root)
+	assert.False(t, ok)
+}
+
+func TestGetDefaultTestFilePattern(t *testing.T) {
+	for _, framework := range supportedFrameworks {
+		assert.NotEmpty(t, GetDefaultTestFilePattern(framework), framework)
+	}
+
+	// Test files directly in the test directory match as well as nested ones
+	pattern := glob.MustCompile(GetDefaultTestFilePattern(FrameworkMinitest))
+	assert.True(t, pattern.Match("test/user_test.rb"))
+	assert.True(t, pattern.Match("test/models/user_test.rb"))
+	assert.False(t, pattern.Match("lib/user_test.rb"))
+}
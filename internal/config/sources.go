@@ -0,0 +1,114 @@
+package config
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Source says where the effective value of a setting came from.
+type Source string
+
+const (
+	SourceDefault Source = "default"
+	SourceFile    Source = "config file"
+	SourceCLI     Source = "command line"
+)
+
+// Setting names, as written in the config file.
+const (
+	SettingTestFramework            = "test_framework"
+	SettingTestCommand              = "test_command"
+	SettingRunTestCaseCommand       = "run_test_case_command"
+	SettingTestFilePattern          = "test_file_pattern"
+	SettingTestResultsPath          = "test_results_path"
+	SettingTestStreamPath           = "test_stream_path"
+	SettingHungTestQuietPeriod      = "hung_test_quiet_period"
+	SettingTestFailureExitCodes     = "test_failure_exit_codes"
+	SettingHistoryLimit             = "history_limit"
+	SettingRetryFailures            = "retry_failures"
+	SettingDebug                    = "debug"
+	SettingExcludePatterns          = "exclude_patterns"
+	SettingPathMappings             = "path_mappings"
+	SettingGemsPath                 = "gems_path"
+	SettingCommandPrefix            = "execution_target.command_prefix"
+	SettingTargetProjectPath        = "execution_target.project_path"
+	SettingDisableReporterInjection = "disable_reporter_injection"
+)
+
+// Setting is the effective value of a config setting and where it came from.
+type Setting struct {
+	Name   string
+	Value  string
+	Source Source
+}
+
+// SetSource records where the value of the named setting came from.
+func (c *Config) SetSource(name string, source Source) {
+	if c.sources == nil {
+		c.sources = make(map[string]Source)
+	}
+	c.sources[name] = source
+}
+
+// Source returns where the value of the named setting came from.
+func (c *Config) Source(name string) Source {
+	if source, ok := c.sources[name]; ok {
+		return source
+	}
+	return SourceDefault
+}
+
+// ResolveDefaults fills in defaults that depend on other settings once the
+// config file and command line have been applied: the framework's test
+// command, and re-runs using the test command.
+func (c *Config) ResolveDefaults() {
+	if c.Source(SettingTestCommand) == SourceDefault {
+		c.TestCommand = GetDefaultTestCommand(c.TestFramework)
+	}
+	if c.Source(SettingRunTestCaseCommand) == SourceDefault {
+		c.RunTestCaseCommand = c.TestCommand
+	}
+}
+
+// Settings lists every setting with its effective value and source, in the
+// order they appear in the config file.
+func (c *Config) Settings() []Setting {
+	mappings := make([]string, 0, len(c.PathMappings))
+	for _, mapping := range c.PathMappings {
+		mappings = append(mappings, mapping.From+"="+mapping.To)
+	}
+
+	values := []struct{ name, value string }{
+		{SettingTestFramework, string(c.TestFramework)},
+		{SettingTestCommand, c.TestCommand},
+		{SettingRunTestCaseCommand, c.RunTestCaseCommand},
+		{SettingTestFilePattern, c.TestFilePattern},
+		{SettingTestResultsPath, c.TestResultsPath},
+		{SettingTestStreamPath, c.TestStreamPath},
+		{SettingHungTestQuietPeriod, strconv.Itoa(c.HungTestQuietPeriod)},
+		{SettingTestFailureExitCodes, joinInts(c.FailureExitCodes())},
+		{SettingHistoryLimit, strconv.Itoa(c.HistoryLimit)},
+		{SettingRetryFailures, strconv.Itoa(c.RetryFailures)},
+		{SettingDebug, strconv.FormatBool(c.Debug)},
+		{SettingExcludePatterns, strings.Join(c.ExcludePatterns, ", ")},
+		{SettingPathMappings, strings.Join(mappings, ", ")},
+		{SettingGemsPath, c.GemsPath},
+		{SettingCommandPrefix, c.ExecutionTarget.CommandPrefix},
+		{SettingTargetProjectPath, c.ExecutionTarget.ProjectPath},
+		{SettingDisableReporterInjection, strconv.FormatBool(c.DisableReporterInjection)},
+	}
+
+	settings := make([]Setting, 0, len(values))
+	for _, v := range values {
+		settings = append(settings, Setting{Name: v.name, Value: v.value, Source: c.Source(v.name)})
+	}
+	return settings
+}
+
+func joinInts(values []int) string {
+	parts := make([]string, 0, len(values))
+	for _, value := range values {
+		parts = append(parts, strconv.Itoa(value))
+	}
+	return strings.Join(parts, ", ")
+}
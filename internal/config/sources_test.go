@@ -0,0 +1,75 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfigRecordsFileSources(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yml")
+	configContent := `test_framework: rspec
+exclude_patterns:
+  - "/vendor/"
+`
+	require.NoError(t, os.WriteFile(configPath, []byte(configContent), 0o644))
+
+	cfg, err := LoadConfig(configPath)
+	require.NoError(t, err)
+
+	assert.Equal(t, SourceFile, cfg.Source(SettingTestFramework))
+	assert.Equal(t, SourceFile, cfg.Source(SettingExcludePatterns))
+	assert.Equal(t, SourceDefault, cfg.Source(SettingTestCommand))
+	assert.Equal(t, SourceDefault, cfg.Source(SettingTestResultsPath))
+	assert.Equal(t, []string{"/vendor/"}, cfg.ExcludePatterns)
+}
+
+func TestLoadConfigUsesFrameworkDefaultCommand(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yml")
+	require.NoError(t, os.WriteFile(configPath, []byte("test_framework: rspec\n"), 0o644))
+
+	cfg, err := LoadConfig(configPath)
+	require.NoError(t, err)
+
+	assert.Equal(t, defaultRSpecCommand, cfg.TestCommand)
+	assert.Equal(t, defaultRSpecCommand, cfg.RunTestCaseCommand)
+}
+
+func TestResolveDefaultsKeepsCommandsFromOtherSources(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.TestFramework = FrameworkGoTest
+	cfg.RunTestCaseCommand = "bin/test %{locations}"
+	cfg.SetSource(SettingTestFramework, SourceCLI)
+	cfg.SetSource(SettingRunTestCaseCommand, SourceFile)
+
+	cfg.ResolveDefaults()
+
+	assert.Equal(t, defaultGoTestCommand, cfg.TestCommand)
+	assert.Equal(t, "bin/test %{locations}", cfg.RunTestCaseCommand)
+
+	cfg.TestCommand = "go test -json ./..."
+	cfg.SetSource(SettingTestCommand, SourceCLI)
+	cfg.ResolveDefaults()
+
+	assert.Equal(t, "go test -json ./...", cfg.TestCommand)
+}
+
+func TestSettingsListsValuesAndSources(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.PathMappings = []PathMapping{{From: "/app", To: "."}}
+	cfg.SetSource(SettingPathMappings, SourceCLI)
+
+	settings := cfg.Settings()
+	byName := make(map[string]Setting, len(settings))
+	for _, setting := range settings {
+		byName[setting.Name] = setting
+	}
+
+	assert.Equal(t, SettingTestFramework, settings[0].Name)
+	assert.Equal(t, Setting{Name: SettingPathMappings, Value: "/app=.", Source: SourceCLI}, byName[SettingPathMappings])
+	assert.Equal(t, Setting{Name: SettingTestFailureExitCodes, Value: "1", Source: SourceDefault}, byName[SettingTestFailureExitCodes])
+	assert.Equal(t, "/gems/, /lib/ruby/, /vendor/bundle/", byName[SettingExcludePatterns].Value)
+}
@@ -0,0 +1,321 @@
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/adamakhtar/wing_commander/internal/config"
+	filewalker "github.com/adamakhtar/wing_commander/internal/file_walker"
+	"github.com/adamakhtar/wing_commander/internal/parser"
+	"github.com/adamakhtar/wing_commander/internal/projectfs"
+	"github.com/adamakhtar/wing_commander/internal/runner"
+	"github.com/adamakhtar/wing_commander/internal/testrun"
+)
+
+// Status is the outcome of a single check.
+type Status string
+
+const (
+	StatusPass Status = "pass"
+	StatusWarn Status = "warn" // Wing Commander works, but not fully
+	StatusFail Status = "fail"
+	StatusSkip Status = "skip" // Not checked because an earlier check failed or it doesn't apply
+)
+
+// Check is one line of the doctor's checklist.
+type Check struct {
+	Name   string
+	Status Status
+	Detail string // What was found
+	Fix    string // How to fix a warning or failure
+}
+
+// Options control the checks Run performs.
+type Options struct {
+	// SkipDryRun leaves out the test run confirming the reporter is installed.
+	SkipDryRun bool
+}
+
+// Failed reports whether any of checks failed.
+func Failed(checks []Check) bool {
+	for _, check := range checks {
+		if check.Status == StatusFail {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckProjectPath checks that path is an existing directory.
+func CheckProjectPath(path string) Check {
+	check := Check{Name: "Project path", Detail: path}
+	info, err := os.Stat(path)
+	switch {
+	case err != nil:
+		check.Status = StatusFail
+		check.Detail = fmt.Sprintf("%s does not exist", path)
+		check.Fix = "Pass the project directory, e.g. wing_commander doctor path/to/project"
+	case !info.IsDir():
+		check.Status = StatusFail
+		check.Detail = fmt.Sprintf("%s is not a directory", path)
+		check.Fix = "Pass the project directory rather than a file in it"
+	default:
+		check.Status = StatusPass
+	}
+	return check
+}
+
+// Run checks the project set up by cfg. ProjectFS must be initialized with
+// the project root. Unless opts.SkipDryRun is set, one test file is run to
+// confirm the reporter writes a valid summary.
+func Run(ctx context.Context, cfg *config.Config, opts Options) []Check {
+	root := projectfs.GetProjectFS().RootPath.String()
+	testRunner := runner.NewTestRunner(cfg)
+
+	checks := []Check{CheckProjectPath(root)}
+
+	configCheck := checkConfig(cfg, testRunner)
+	checks = append(checks, configCheck)
+
+	commandCheck := Check{Name: "Test command", Status: StatusSkip, Detail: "Skipped, the configuration is invalid"}
+	if configCheck.Status == StatusPass {
+		commandCheck = checkTestCommand(cfg, testRunner)
+	}
+	checks = append(checks, commandCheck)
+
+	filesCheck, testFiles := checkTestFiles(cfg, root)
+	checks = append(checks, filesCheck)
+
+	var reporterCheck Check
+	switch {
+	case opts.SkipDryRun:
+		reporterCheck = Check{Name: "Reporter", Status: StatusSkip, Detail: "Skipped with --skip-dry-run"}
+	case commandCheck.Status != StatusPass:
+		reporterCheck = Check{Name: "Reporter", Status: StatusSkip, Detail: "Skipped, the test command can't be run"}
+	default:
+		reporterCheck = checkReporter(ctx, cfg, testRunner, testFiles)
+	}
+	checks = append(checks, reporterCheck)
+
+	return append(checks, checkGit(root))
+}
+
+func checkConfig(cfg *config.Config, testRunner *runner.TestRunner) Check {
+	check := Check{Name: "Configuration"}
+	if err := testRunner.ValidateConfig(); err != nil {
+		check.Status = StatusFail
+		check.Detail = err.Error()
+		check.Fix = "Run wing_commander init, or set test_framework and test_command in .wing_commander/config.yml"
+		return check
+	}
+	check.Status = StatusPass
+	check.Detail = fmt.Sprintf("%s, test_command: %s", cfg.TestFramework, cfg.TestCommand)
+	return check
+}
+
+func checkTestCommand(cfg *config.Config, testRunner *runner.TestRunner) Check {
+	check := Check{Name: "Test command"}
+	err := testRunner.CheckTestCommandExists()
+
+	if !cfg.ExecutionTarget.IsLocal() {
+		if err != nil {
+			check.Status = StatusFail
+			check.Detail = err.Error()
+			check.Fix = "Install the command prefix's program or fix execution_target.command_prefix (--command-prefix)"
+			return check
+		}
+		check.Status = StatusPass
+		check.Detail = fmt.Sprintf("Runs through %q, the test command itself is checked by the dry run", cfg.ExecutionTarget.CommandPrefix)
+		return check
+	}
+
+	if err != nil {
+		check.Status = StatusFail
+		check.Detail = err.Error()
+		if strings.Contains(err.Error(), "in the project") {
+			check.Fix = "Run wing_commander init to install bin/test, or set --run-command (test_command) to a command that exists"
+		} else {
+			check.Fix = "Install the command or set --run-command (test_command) to one on your PATH"
+			if defaultCommand := config.GetDefaultTestCommand(cfg.TestFramework); defaultCommand != cfg.TestCommand {
+				check.Fix += ", e.g. " + defaultCommand
+			}
+		}
+		return check
+	}
+	check.Status = StatusPass
+	check.Detail = cfg.TestCommand
+	return check
+}
+
+// checkTestFiles checks that test_file_pattern matches files and returns
+// them. Without a pattern the framework's conventional one is tried.
+func checkTestFiles(cfg *config.Config, root string) (Check, []string) {
+	check := Check{Name: "Test files"}
+	pattern := cfg.TestFilePattern
+	defaultPattern := config.GetDefaultTestFilePattern(cfg.TestFramework)
+	if pattern == "" {
+		pattern = defaultPattern
+	}
+
+	var testFiles []string
+	if pattern != "" {
+		for _, path := range filewalker.FileEntriesRecursive(root, []string{pattern}, []string{}) {
+			if !strings.HasSuffix(path, string(os.PathSeparator)) {
+				testFiles = append(testFiles, path)
+			}
+		}
+	}
+
+	switch {
+	case cfg.TestFilePattern == "":
+		check.Status = StatusWarn
+		check.Detail = "test_file_pattern is not set, the file picker lists every file"
+		check.Fix = "Set --test-file-pattern (test_file_pattern)"
+		if defaultPattern != "" {
+			check.Detail += fmt.Sprintf(". %d files match %s", len(testFiles), defaultPattern)
+			check.Fix += fmt.Sprintf(", e.g. %q", defaultPattern)
+		}
+	case len(testFiles) == 0:
+		check.Status = StatusFail
+		check.Detail = fmt.Sprintf("No files match %s", pattern)
+		check.Fix = "Set --test-file-pattern (test_file_pattern) to a glob matching your test files relative to the project root"
+		if defaultPattern != "" && defaultPattern != pattern {
+			check.Fix += fmt.Sprintf(", e.g. %q", defaultPattern)
+		}
+	default:
+		check.Status = StatusPass
+		check.Detail = fmt.Sprintf("%d files match %s", len(testFiles), pattern)
+	}
+	return check, testFiles
+}
+
+// checkReporter runs the first test file, or the whole suite when there is
+// none, and checks the summary the reporter wrote. Failing tests are fine,
+// only a crashed run or a summary not matching the schema fail the check.
+func checkReporter(ctx context.Context, cfg *config.Config, testRunner *runner.TestRunner, testFiles []string) Check {
+	check := Check{Name: "Reporter"}
+	if cfg.TestFramework == config.FrameworkGoTest {
+		check.Status = StatusSkip
+		check.Detail = "Not needed, results are read from the go test -json output"
+		return check
+	}
+
+	testRuns := testrun.NewTestRuns()
+	var testRun testrun.TestRun
+	var err error
+	ran := "the whole suite"
+	if len(testFiles) > 0 {
+		patterns, patternErr := testrun.PatternsFromStrings(testFiles[:1])
+		if patternErr != nil {
+			return failed(check, patternErr.Error(), "")
+		}
+		testRun, err = testRuns.Add(patterns, testrun.ModeRunSelectedPatterns)
+		ran = testFiles[0]
+	} else {
+		testRun, err = testRuns.Add(nil, testrun.ModeRunWholeSuite)
+	}
+	if err != nil {
+		return failed(check, err.Error(), "")
+	}
+
+	var summary []byte
+	var readErr error
+	hooks := runner.Hooks{OnSummary: func(path string) {
+		summary, readErr = os.ReadFile(path)
+	}}
+	result, err := testRunner.ExecuteTests(ctx, testRun, hooks)
+	if err != nil {
+		return failed(check, fmt.Sprintf("Dry run of %s failed: %v", ran, err), "Check that the test command runs from the project root")
+	}
+	if result.Cancelled {
+		return failed(check, "Dry run was interrupted", "")
+	}
+	if readErr != nil {
+		return failed(check, readErr.Error(), "")
+	}
+	if result.Crashed {
+		detail := fmt.Sprintf("Dry run of %s crashed: %s", ran, result.CrashReason)
+		if summary == nil {
+			return failed(check, detail, reporterFix(cfg))
+		}
+		return failed(check, detail, "Run the test command by hand to see why it fails, or add its exit code to test_failure_exit_codes if it means tests failed")
+	}
+
+	if cfg.TestFramework == config.FrameworkMinitest || cfg.TestFramework == config.FrameworkRSpec {
+		if err := parser.ValidateSummary(summary); err != nil {
+			return failed(check, fmt.Sprintf("The summary of %s doesn't match the schema: %v", ran, err), updateReporterFix(cfg))
+		}
+		check.Status = StatusPass
+		check.Detail = fmt.Sprintf("Dry run of %s wrote a valid summary of %d tests", ran, result.Metrics.TotalTests)
+		return check
+	}
+
+	check.Status = StatusPass
+	check.Detail = fmt.Sprintf("Dry run of %s reported %d tests", ran, result.Metrics.TotalTests)
+	return check
+}
+
+func failed(check Check, detail string, fix string) Check {
+	check.Status = StatusFail
+	check.Detail = detail
+	check.Fix = fix
+	return check
+}
+
+// reporterFix explains how to get the framework's reporter to write the
+// summary the runner reads.
+func reporterFix(cfg *config.Config) string {
+	switch cfg.TestFramework {
+	case config.FrameworkMinitest:
+		if cfg.DisableReporterInjection {
+			return "Register WingCommanderReporter in test/test_helper.rb (see the README's Minitest setup), or remove disable_reporter_injection"
+		}
+		return `Add gem "minitest-reporters" to the Gemfile's test group and run bundle install. The test command must run Ruby with the RUBYOPT it is given`
+	case config.FrameworkRSpec:
+		return "Run wing_commander init --apply to install the formatter and enable it in .rspec"
+	case config.FrameworkPytest:
+		return fmt.Sprintf("Add --junitxml=%s to test_command", cfg.TestResultsPath)
+	case config.FrameworkJest:
+		return fmt.Sprintf("Add --json --outputFile=%s to test_command", cfg.TestResultsPath)
+	case config.FrameworkVitest:
+		return fmt.Sprintf("Add --reporter=json --outputFile=%s to test_command", cfg.TestResultsPath)
+	default:
+		return ""
+	}
+}
+
+// updateReporterFix explains how to replace a reporter writing an outdated
+// summary.
+func updateReporterFix(cfg *config.Config) string {
+	if cfg.TestFramework == config.FrameworkRSpec {
+		return "Reinstall the formatter with wing_commander init --force"
+	}
+	if cfg.DisableReporterInjection {
+		return "Replace your copy of wing_commander_reporter.rb with internal/reporters/ruby/inject/wing_commander_reporter.rb"
+	}
+	return "Remove the WingCommanderReporter registered in test_helper.rb so the injected one is used"
+}
+
+// checkGit checks that git is installed and the project is a repository, which
+// change detection needs.
+func checkGit(root string) Check {
+	check := Check{Name: "Git"}
+	if _, err := exec.LookPath("git"); err != nil {
+		check.Status = StatusWarn
+		check.Detail = "git not found in PATH, changed lines in backtraces won't be highlighted"
+		check.Fix = "Install git"
+		return check
+	}
+	if err := exec.Command("git", "-C", root, "rev-parse", "--is-inside-work-tree").Run(); err != nil {
+		check.Status = StatusWarn
+		check.Detail = "The project is not a git repository, changed lines in backtraces won't be highlighted"
+		check.Fix = "Run git init in the project"
+		return check
+	}
+	check.Status = StatusPass
+	check.Detail = "git is available for change detection"
+	return check
+}
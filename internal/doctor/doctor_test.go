@@ -0,0 +1,149 @@
+package doctor
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/adamakhtar/wing_commander/internal/config"
+	"github.com/adamakhtar/wing_commander/internal/projectfs"
+	"github.com/adamakhtar/wing_commander/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const validSummary = `- test_group_name: UserTest
+  test_case_name: test_name
+  test_status: passed
+  duration: "0.01"
+  test_file_path: test/models/user_test.rb
+  test_line_number: 3
+`
+
+// setupProject creates a Minitest project with one test file and returns its
+// config, writing summary to the run's summary file when the tests run. The
+// test files the runner appends to the command are passed to true.
+func setupProject(t *testing.T, summary string) *config.Config {
+	t.Helper()
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "test", "models"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "test", "models", "user_test.rb"), []byte("class UserTest; end\n"), 0o644))
+	rootPath, _ := types.NewAbsPath(root)
+	require.NoError(t, projectfs.InitProjectFS(rootPath, "test/**/*_test.rb"))
+
+	cfg := config.DefaultConfig()
+	cfg.TestCommand = "true"
+	if summary != "" {
+		cfg.TestCommand = `printf '%s' '` + summary + `' > "$WING_COMMANDER_SUMMARY_PATH"; true`
+	}
+	cfg.TestFilePattern = "test/**/*_test.rb"
+	cfg.TestResultsPath = filepath.Join(root, "summary.yml")
+	cfg.TestStreamPath = filepath.Join(root, "stream.jsonl")
+	return cfg
+}
+
+func findCheck(t *testing.T, checks []Check, name string) Check {
+	t.Helper()
+	for _, check := range checks {
+		if check.Name == name {
+			return check
+		}
+	}
+	t.Fatalf("no %s check in %v", name, checks)
+	return Check{}
+}
+
+func TestCheckProjectPath(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "Gemfile")
+	require.NoError(t, os.WriteFile(file, nil, 0o644))
+
+	assert.Equal(t, StatusPass, CheckProjectPath(dir).Status)
+	assert.Equal(t, StatusFail, CheckProjectPath(file).Status)
+
+	missing := CheckProjectPath(filepath.Join(dir, "missing"))
+	assert.Equal(t, StatusFail, missing.Status)
+	assert.Contains(t, missing.Detail, "does not exist")
+	assert.NotEmpty(t, missing.Fix)
+}
+
+func TestRun_WorkingProject(t *testing.T) {
+	cfg := setupProject(t, validSummary)
+
+	checks := Run(context.Background(), cfg, Options{})
+
+	for _, name := range []string{"Project path", "Configuration", "Test command", "Test files", "Reporter"} {
+		assert.Equal(t, StatusPass, findCheck(t, checks, name).Status, name)
+	}
+	assert.Equal(t, "1 files match test/**/*_test.rb", findCheck(t, checks, "Test files").Detail)
+	assert.Equal(t, "Dry run of test/models/user_test.rb wrote a valid summary of 1 tests", findCheck(t, checks, "Reporter").Detail)
+	assert.False(t, Failed(checks))
+}
+
+func TestRun_ReporterNotInstalled(t *testing.T) {
+	cfg := setupProject(t, "")
+
+	checks := Run(context.Background(), cfg, Options{})
+
+	reporter := findCheck(t, checks, "Reporter")
+	assert.Equal(t, StatusFail, reporter.Status)
+	assert.Contains(t, reporter.Detail, "did not write the summary file")
+	assert.Contains(t, reporter.Fix, "minitest-reporters")
+	assert.True(t, Failed(checks))
+}
+
+func TestRun_SummaryNotMatchingSchema(t *testing.T) {
+	cfg := setupProject(t, "- test_group_name: UserTest\n  test_status: ok\n")
+
+	reporter := findCheck(t, Run(context.Background(), cfg, Options{}), "Reporter")
+
+	assert.Equal(t, StatusFail, reporter.Status)
+	assert.Contains(t, reporter.Detail, "doesn't match the schema")
+	assert.Contains(t, reporter.Detail, "test_case_name is missing")
+}
+
+func TestRun_MissingTestCommand(t *testing.T) {
+	cfg := setupProject(t, validSummary)
+	cfg.TestCommand = "nonexistentcommand12345 test"
+
+	checks := Run(context.Background(), cfg, Options{})
+
+	command := findCheck(t, checks, "Test command")
+	assert.Equal(t, StatusFail, command.Status)
+	assert.Contains(t, command.Fix, "--run-command")
+	assert.Equal(t, StatusSkip, findCheck(t, checks, "Reporter").Status)
+}
+
+func TestRun_TestFilePatternMatchesNothing(t *testing.T) {
+	cfg := setupProject(t, validSummary)
+	cfg.TestFilePattern = "spec/**/*_spec.rb"
+
+	files := findCheck(t, Run(context.Background(), cfg, Options{SkipDryRun: true}), "Test files")
+
+	assert.Equal(t, StatusFail, files.Status)
+	assert.Equal(t, "No files match spec/**/*_spec.rb", files.Detail)
+	assert.Contains(t, files.Fix, `"test/{*_test.rb,**/*_test.rb}"`)
+}
+
+func TestRun_SkipDryRun(t *testing.T) {
+	cfg := setupProject(t, "")
+
+	checks := Run(context.Background(), cfg, Options{SkipDryRun: true})
+
+	assert.Equal(t, StatusSkip, findCheck(t, checks, "Reporter").Status)
+	assert.False(t, Failed(checks))
+}
+
+func TestCheckGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	root := t.TempDir()
+
+	assert.Equal(t, StatusWarn, checkGit(root).Status)
+
+	require.NoError(t, exec.Command("git", "init", "-q", root).Run())
+	assert.Equal(t, StatusPass, checkGit(root).Status)
+}
@@ -0,0 +1,84 @@
+package framework
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/adamakhtar/wing_commander/internal/config"
+	"github.com/adamakhtar/wing_commander/internal/parser"
+	"github.com/adamakhtar/wing_commander/internal/testresult"
+	"github.com/adamakhtar/wing_commander/internal/testrun"
+	"github.com/adamakhtar/wing_commander/internal/types"
+)
+
+// Adapter encapsulates everything framework specific about running tests and
+// reading their results, so the runner and UI stay framework agnostic.
+type Adapter interface {
+	// Framework returns the config value the adapter is registered under.
+	Framework() config.TestFramework
+	// BuildCommand builds the shell command that executes the given test run.
+	BuildCommand(command string, testRun testrun.TestRun) (string, error)
+	// ParseResults parses the results left behind by a finished test command.
+	ParseResults(output Output) (*parser.ParseResult, error)
+	// ParseStackFrame parses a single backtrace line.
+	ParseStackFrame(frameStr string) types.StackFrame
+	// ClassifyFailure decides the failure cause of a failed test.
+	ClassifyFailure(message string, topFrame *types.StackFrame) testresult.FailureCause
+}
+
+// commandOutputReader is implemented by adapters that read results from the
+// test command's output instead of a summary file.
+type commandOutputReader interface {
+	ReadsCommandOutput() bool
+}
+
+// ReadsSummaryFile reports whether the adapter's results come from a summary
+// file the test command writes, which must be fresh after every run.
+func ReadsSummaryFile(adapter Adapter) bool {
+	reader, ok := adapter.(commandOutputReader)
+	return !ok || !reader.ReadsCommandOutput()
+}
+
+// Output is what a finished test command left behind for an adapter to parse.
+type Output struct {
+	ResultsPath   string // Summary file written by the framework's reporter
+	CommandOutput string // Combined stdout/stderr of the test command
+}
+
+var registry = map[config.TestFramework]Adapter{
+	config.FrameworkMinitest: MinitestAdapter{},
+	config.FrameworkRSpec:    RSpecAdapter{},
+	config.FrameworkGoTest:   GoTestAdapter{},
+	config.FrameworkPytest:   PytestAdapter{},
+	config.FrameworkJest:     JestAdapter{},
+	config.FrameworkVitest:   VitestAdapter{},
+}
+
+// Get returns the adapter registered for the given framework.
+func Get(framework config.TestFramework) (Adapter, error) {
+	adapter, ok := registry[framework]
+	if !ok {
+		return nil, fmt.Errorf("unsupported test framework: %s (supported: %v)", framework, Supported())
+	}
+	return adapter, nil
+}
+
+// Supported returns the registered frameworks in alphabetical order.
+func Supported() []config.TestFramework {
+	frameworks := make([]config.TestFramework, 0, len(registry))
+	for framework := range registry {
+		frameworks = append(frameworks, framework)
+	}
+	sort.Slice(frameworks, func(i, j int) bool {
+		return frameworks[i] < frameworks[j]
+	})
+	return frameworks
+}
+
+// ParseOptions wires an adapter's frame parser and classifier into the parser.
+func ParseOptions(adapter Adapter) *parser.ParseOptions {
+	return &parser.ParseOptions{
+		FrameParser:     adapter.ParseStackFrame,
+		ClassifyFailure: adapter.ClassifyFailure,
+	}
+}
@@ -0,0 +1,23 @@
+package framework
+
+import (
+	"testing"
+
+	"github.com/adamakhtar/wing_commander/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGet(t *testing.T) {
+	adapter, err := Get(config.FrameworkMinitest)
+	require.NoError(t, err)
+	assert.Equal(t, config.FrameworkMinitest, adapter.Framework())
+
+	_, err = Get(config.TestFramework("unknown"))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported test framework: unknown")
+}
+
+func TestSupported(t *testing.T) {
+	assert.Equal(t, []config.TestFramework{config.FrameworkGoTest, config.FrameworkJest, config.FrameworkMinitest, config.FrameworkPytest, config.FrameworkRSpec, config.FrameworkVitest}, Supported())
+}
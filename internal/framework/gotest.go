@@ -0,0 +1,139 @@
+package framework
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/adamakhtar/wing_commander/internal/backtrace"
+	"github.com/adamakhtar/wing_commander/internal/config"
+	"github.com/adamakhtar/wing_commander/internal/parser"
+	"github.com/adamakhtar/wing_commander/internal/projectfs"
+	"github.com/adamakhtar/wing_commander/internal/testresult"
+	"github.com/adamakhtar/wing_commander/internal/testrun"
+	"github.com/adamakhtar/wing_commander/internal/types"
+)
+
+// GoTestAdapter runs `go test -json` and folds its event stream, read from the
+// command's output, into test results. No reporter or results file is needed.
+type GoTestAdapter struct{}
+
+func (GoTestAdapter) Framework() config.TestFramework {
+	return config.FrameworkGoTest
+}
+
+// BuildCommand appends package arguments to the command: "./..." for the whole
+// suite, the patterns' package directories otherwise. Re-runs add a -run regex
+// built from the test names, e.g. -run '^TestSave$/^empty_name$'.
+func (GoTestAdapter) BuildCommand(command string, testRun testrun.TestRun) (string, error) {
+	if command == "" {
+		return "", fmt.Errorf("command is required")
+	}
+
+	switch testRun.Mode {
+	case string(testrun.ModeRunWholeSuite):
+		return command + " ./...", nil
+
+	case string(testrun.ModeRunSelectedPatterns):
+		return command + " " + strings.Join(goPackageArgs(testRun.Patterns), " "), nil
+
+	case string(testrun.ModeReRunSingleFailure), string(testrun.ModeReRunAllFailures):
+		runRegex := goRunRegex(testRun.Patterns)
+		if runRegex == "" {
+			return command + " " + strings.Join(goPackageArgs(testRun.Patterns), " "), nil
+		}
+		return command + " -run " + ShellEscape(runRegex) + " " + strings.Join(goPackageArgs(testRun.Patterns), " "), nil
+
+	default:
+		return "", fmt.Errorf("invalid mode: %s", testRun.Mode)
+	}
+}
+
+func (a GoTestAdapter) ParseResults(output Output) (*parser.ParseResult, error) {
+	return parser.ParseGoTestJSON([]byte(output.CommandOutput), ParseOptions(a))
+}
+
+// ReadsCommandOutput tells the runner there's no summary file to check, results
+// come from the `go test -json` events printed by the command.
+func (GoTestAdapter) ReadsCommandOutput() bool {
+	return true
+}
+
+func (GoTestAdapter) ParseStackFrame(frameStr string) types.StackFrame {
+	return backtrace.ParseGoStackFrame(frameStr)
+}
+
+// ClassifyFailure treats t.Error/t.Fatal failures as assertions; only panics are
+// classified by where they happened.
+func (GoTestAdapter) ClassifyFailure(message string, topFrame *types.StackFrame) testresult.FailureCause {
+	if !strings.HasPrefix(message, "panic: ") {
+		return testresult.FailureCauseAssertion
+	}
+	if topFrame != nil && strings.HasSuffix(topFrame.FilePath.String(), "_test.go") {
+		return testresult.FailureCauseTestDefinition
+	}
+	return parser.ClassifyFailure(message, topFrame)
+}
+
+// goPackageArgs returns the unique package directories ("./internal/worker") of the patterns.
+// Patterns may point at a package directory or at a file inside one.
+func goPackageArgs(patterns []testrun.TestPattern) []string {
+	var args []string
+	seen := map[string]bool{}
+	for _, pattern := range patterns {
+		dir := pattern.Path
+		if strings.HasSuffix(dir, ".go") {
+			dir = filepath.Dir(dir)
+		}
+		if filepath.IsAbs(dir) {
+			if rel, err := projectfs.GetProjectFS().Rel(types.AbsPath(dir)); err == nil {
+				dir = rel.String()
+			}
+		}
+
+		arg := dir
+		if !filepath.IsAbs(arg) && arg != "." && !strings.HasPrefix(arg, "./") && !strings.HasPrefix(arg, "../") {
+			arg = "./" + arg
+		}
+		if !seen[arg] {
+			seen[arg] = true
+			args = append(args, ShellEscape(arg))
+		}
+	}
+	return args
+}
+
+// goRunRegex builds the -run regex for the patterns' test names. A single test
+// is matched exactly, subtest levels included; several tests are matched by
+// their top-level names as go test applies each level of the regex independently.
+func goRunRegex(patterns []testrun.TestPattern) string {
+	var names []string
+	for _, pattern := range patterns {
+		if pattern.TestCaseName != nil && *pattern.TestCaseName != "" {
+			names = append(names, *pattern.TestCaseName)
+		}
+	}
+
+	switch len(names) {
+	case 0:
+		return ""
+	case 1:
+		levels := strings.Split(names[0], "/")
+		for i, level := range levels {
+			levels[i] = "^" + regexp.QuoteMeta(level) + "$"
+		}
+		return strings.Join(levels, "/")
+	}
+
+	var topLevel []string
+	seen := map[string]bool{}
+	for _, name := range names {
+		top, _, _ := strings.Cut(name, "/")
+		if !seen[top] {
+			seen[top] = true
+			topLevel = append(topLevel, regexp.QuoteMeta(top))
+		}
+	}
+	return "^(" + strings.Join(topLevel, "|") + ")$"
+}
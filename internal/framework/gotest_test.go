@@ -0,0 +1,105 @@
+package framework
+
+import (
+	"testing"
+
+	"github.com/adamakhtar/wing_commander/internal/projectfs"
+	"github.com/adamakhtar/wing_commander/internal/testresult"
+	"github.com/adamakhtar/wing_commander/internal/testrun"
+	"github.com/adamakhtar/wing_commander/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGoTestAdapter_BuildCommand(t *testing.T) {
+	rootPath, _ := types.NewAbsPath("/path/to/project")
+	require.NoError(t, projectfs.InitProjectFS(rootPath, ""))
+
+	testSave := "TestSave"
+	testLoad := "TestLoad"
+	subtest := "TestSave/empty name"
+	otherSubtest := "TestSave/too_long"
+
+	tests := []struct {
+		name    string
+		testRun testrun.TestRun
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "whole suite",
+			testRun: testrun.TestRun{Mode: string(testrun.ModeRunWholeSuite)},
+			want:    "go test -json ./...",
+		},
+		{
+			name: "selected patterns",
+			testRun: testrun.TestRun{
+				Mode: string(testrun.ModeRunSelectedPatterns),
+				Patterns: []testrun.TestPattern{
+					{Path: "internal/store/store_test.go"},
+					{Path: "/path/to/project/internal/store/cache_test.go"},
+					{Path: "cmd"},
+				},
+			},
+			want: "go test -json './internal/store' './cmd'",
+		},
+		{
+			name: "re-run single test",
+			testRun: testrun.TestRun{
+				Mode:     string(testrun.ModeReRunSingleFailure),
+				Patterns: []testrun.TestPattern{{Path: "/path/to/project/store/store_test.go", TestCaseName: &testSave}},
+			},
+			want: "go test -json -run '^TestSave$' './store'",
+		},
+		{
+			name: "re-run single subtest",
+			testRun: testrun.TestRun{
+				Mode:     string(testrun.ModeReRunSingleFailure),
+				Patterns: []testrun.TestPattern{{Path: "/path/to/project/store/store_test.go", TestCaseName: &subtest}},
+			},
+			want: "go test -json -run '^TestSave$/^empty name$' './store'",
+		},
+		{
+			name: "re-run several failures",
+			testRun: testrun.TestRun{
+				Mode: string(testrun.ModeReRunAllFailures),
+				Patterns: []testrun.TestPattern{
+					{Path: "/path/to/project/store/store_test.go", TestCaseName: &subtest},
+					{Path: "/path/to/project/store/store_test.go", TestCaseName: &otherSubtest},
+					{Path: "/path/to/project/loader/loader_test.go", TestCaseName: &testLoad},
+				},
+			},
+			want: "go test -json -run '^(TestSave|TestLoad)$' './store' './loader'",
+		},
+		{
+			name:    "invalid mode",
+			testRun: testrun.TestRun{Mode: "bogus"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GoTestAdapter{}.BuildCommand("go test -json", tt.testRun)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGoTestAdapter_ClassifyFailure(t *testing.T) {
+	rootPath, _ := types.NewAbsPath("/path/to/project")
+	require.NoError(t, projectfs.InitProjectFS(rootPath, ""))
+
+	testFrame := &types.StackFrame{FilePath: "/path/to/project/store/store_test.go", Line: 10}
+	codeFrame := &types.StackFrame{FilePath: "/path/to/project/store/store.go", Line: 42}
+
+	adapter := GoTestAdapter{}
+	assert.Equal(t, testresult.FailureCauseAssertion, adapter.ClassifyFailure("got 2, want 3", testFrame))
+	assert.Equal(t, testresult.FailureCauseTestDefinition, adapter.ClassifyFailure("panic: nil map", testFrame))
+	assert.Equal(t, testresult.FailureCauseProductionCode, adapter.ClassifyFailure("panic: nil map", codeFrame))
+}
@@ -0,0 +1,127 @@
+package framework
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/adamakhtar/wing_commander/internal/backtrace"
+	"github.com/adamakhtar/wing_commander/internal/config"
+	"github.com/adamakhtar/wing_commander/internal/parser"
+	"github.com/adamakhtar/wing_commander/internal/projectfs"
+	"github.com/adamakhtar/wing_commander/internal/testresult"
+	"github.com/adamakhtar/wing_commander/internal/testrun"
+	"github.com/adamakhtar/wing_commander/internal/types"
+)
+
+// JestAdapter runs Jest suites and reads the JSON report written by
+// `jest --json --outputFile`.
+type JestAdapter struct{}
+
+func (JestAdapter) Framework() config.TestFramework {
+	return config.FrameworkJest
+}
+
+// BuildCommand appends the run's paths to the command. Re-runs also pass an
+// anchored -t pattern built from the failed tests' full names, which both Jest
+// and Vitest match against "<describe titles> <test title>".
+func (JestAdapter) BuildCommand(command string, testRun testrun.TestRun) (string, error) {
+	if command == "" {
+		return "", fmt.Errorf("command is required")
+	}
+
+	switch testRun.Mode {
+	case string(testrun.ModeRunWholeSuite):
+		return command, nil
+
+	case string(testrun.ModeRunSelectedPatterns):
+		escapedPaths := shellEscapeList(testRun.PatternsToFilePaths())
+		return command + " " + strings.Join(escapedPaths, " "), nil
+
+	case string(testrun.ModeReRunSingleFailure), string(testrun.ModeReRunAllFailures):
+		var paths, names []string
+		for _, pattern := range testRun.Patterns {
+			path := jestRelPath(pattern.Path)
+			if !slices.Contains(paths, path) {
+				paths = append(paths, path)
+			}
+			if name := regexp.QuoteMeta(jestFullName(pattern)); name != "" && !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+
+		cmd := command + " " + strings.Join(shellEscapeList(paths), " ")
+		if len(names) > 0 {
+			cmd += " -t " + ShellEscape("^("+strings.Join(names, "|")+")$")
+		}
+		return cmd, nil
+
+	default:
+		return "", fmt.Errorf("invalid mode: %s", testRun.Mode)
+	}
+}
+
+func (a JestAdapter) ParseResults(output Output) (*parser.ParseResult, error) {
+	return parser.ParseJestFile(output.ResultsPath, ParseOptions(a))
+}
+
+func (JestAdapter) ParseStackFrame(frameStr string) types.StackFrame {
+	return backtrace.ParseJSStackFrame(frameStr)
+}
+
+// ClassifyFailure treats expect() and assert module failures as assertions and
+// errors raised from test files as test definition errors.
+func (JestAdapter) ClassifyFailure(message string, topFrame *types.StackFrame) testresult.FailureCause {
+	if strings.Contains(message, "expect(") || strings.HasPrefix(message, "AssertionError") {
+		return testresult.FailureCauseAssertion
+	}
+	if topFrame != nil && isJSTestFile(topFrame.FilePath.String()) {
+		return testresult.FailureCauseTestDefinition
+	}
+	return parser.ClassifyFailure(message, topFrame)
+}
+
+// VitestAdapter runs Vitest suites. Vitest's json reporter writes the same
+// report as Jest and accepts the same file and -t filters.
+type VitestAdapter struct {
+	JestAdapter
+}
+
+func (VitestAdapter) Framework() config.TestFramework {
+	return config.FrameworkVitest
+}
+
+func (a VitestAdapter) ParseResults(output Output) (*parser.ParseResult, error) {
+	return parser.ParseJestFile(output.ResultsPath, ParseOptions(a))
+}
+
+// jestRelPath returns path relative to the project root when possible.
+func jestRelPath(path string) string {
+	if filepath.IsAbs(path) {
+		if rel, err := projectfs.GetProjectFS().Rel(types.AbsPath(path)); err == nil {
+			return filepath.ToSlash(rel.String())
+		}
+	}
+	return path
+}
+
+// jestFullName joins the pattern's describe titles and test title the way Jest
+// reports a test's full name.
+func jestFullName(pattern testrun.TestPattern) string {
+	if pattern.TestCaseName == nil || *pattern.TestCaseName == "" {
+		return ""
+	}
+	if pattern.TestGroupName == nil || *pattern.TestGroupName == "" {
+		return *pattern.TestCaseName
+	}
+	return *pattern.TestGroupName + " " + *pattern.TestCaseName
+}
+
+// isJSTestFile reports whether path follows Jest's default test file naming.
+func isJSTestFile(path string) bool {
+	base := filepath.Base(path)
+	return strings.Contains(base, ".test.") || strings.Contains(base, ".spec.") ||
+		strings.Contains(filepath.ToSlash(path), "/__tests__/")
+}
@@ -0,0 +1,107 @@
+package framework
+
+import (
+	"testing"
+
+	"github.com/adamakhtar/wing_commander/internal/config"
+	"github.com/adamakhtar/wing_commander/internal/projectfs"
+	"github.com/adamakhtar/wing_commander/internal/testresult"
+	"github.com/adamakhtar/wing_commander/internal/testrun"
+	"github.com/adamakhtar/wing_commander/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJestAdapter_BuildCommand(t *testing.T) {
+	rootPath, _ := types.NewAbsPath("/path/to/project")
+	require.NoError(t, projectfs.InitProjectFS(rootPath, ""))
+
+	testCaseName := "takes 10% off (member)"
+	otherTestCaseName := "checks out"
+	groupName := "Cart discount"
+	otherGroupName := "Cart"
+
+	tests := []struct {
+		name    string
+		testRun testrun.TestRun
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "whole suite",
+			testRun: testrun.TestRun{Mode: string(testrun.ModeRunWholeSuite)},
+			want:    "npx jest",
+		},
+		{
+			name: "selected patterns",
+			testRun: testrun.TestRun{
+				Mode:     string(testrun.ModeRunSelectedPatterns),
+				Patterns: []testrun.TestPattern{{Path: "src/cart.test.js"}, {Path: "src/api"}},
+			},
+			want: "npx jest 'src/cart.test.js' 'src/api'",
+		},
+		{
+			name: "re-run single failure",
+			testRun: testrun.TestRun{
+				Mode:     string(testrun.ModeReRunSingleFailure),
+				Patterns: []testrun.TestPattern{{Path: "/path/to/project/src/cart.test.js", TestCaseName: &testCaseName, TestGroupName: &groupName}},
+			},
+			want: `npx jest 'src/cart.test.js' -t '^(Cart discount takes 10% off \(member\))$'`,
+		},
+		{
+			name: "re-run all failures",
+			testRun: testrun.TestRun{
+				Mode: string(testrun.ModeReRunAllFailures),
+				Patterns: []testrun.TestPattern{
+					{Path: "/path/to/project/src/cart.test.js", TestCaseName: &testCaseName, TestGroupName: &groupName},
+					{Path: "/path/to/project/src/cart.test.js", TestCaseName: &otherTestCaseName, TestGroupName: &otherGroupName},
+					{Path: "/path/to/project/src/broken.test.js"},
+				},
+			},
+			want: `npx jest 'src/cart.test.js' 'src/broken.test.js' -t '^(Cart discount takes 10% off \(member\)|Cart checks out)$'`,
+		},
+		{
+			name:    "invalid mode",
+			testRun: testrun.TestRun{Mode: "bogus"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := JestAdapter{}.BuildCommand("npx jest", tt.testRun)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestJestAdapter_ClassifyFailure(t *testing.T) {
+	rootPath, _ := types.NewAbsPath("/path/to/project")
+	require.NoError(t, projectfs.InitProjectFS(rootPath, ""))
+
+	testFrame := &types.StackFrame{FilePath: "/path/to/project/src/cart.test.js", Line: 10}
+	specFrame := &types.StackFrame{FilePath: "/path/to/project/src/__tests__/cart.ts", Line: 3}
+	codeFrame := &types.StackFrame{FilePath: "/path/to/project/src/cart.js", Line: 6}
+
+	adapter := JestAdapter{}
+	assert.Equal(t, testresult.FailureCauseAssertion, adapter.ClassifyFailure("Error: expect(received).toBe(expected)", testFrame))
+	assert.Equal(t, testresult.FailureCauseAssertion, adapter.ClassifyFailure("AssertionError: expected 90 to be 85", testFrame))
+	assert.Equal(t, testresult.FailureCauseTestDefinition, adapter.ClassifyFailure("ReferenceError: cart is not defined", testFrame))
+	assert.Equal(t, testresult.FailureCauseTestDefinition, adapter.ClassifyFailure("ReferenceError: cart is not defined", specFrame))
+	assert.Equal(t, testresult.FailureCauseProductionCode, adapter.ClassifyFailure("TypeError: Cannot read properties of null", codeFrame))
+}
+
+func TestVitestAdapter(t *testing.T) {
+	adapter, err := Get(config.FrameworkVitest)
+	require.NoError(t, err)
+	assert.Equal(t, config.FrameworkVitest, adapter.Framework())
+
+	got, err := adapter.BuildCommand("npx vitest run", testrun.TestRun{Mode: string(testrun.ModeRunWholeSuite)})
+	require.NoError(t, err)
+	assert.Equal(t, "npx vitest run", got)
+}
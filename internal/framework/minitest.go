@@ -0,0 +1,59 @@
+package framework
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/adamakhtar/wing_commander/internal/backtrace"
+	"github.com/adamakhtar/wing_commander/internal/config"
+	"github.com/adamakhtar/wing_commander/internal/parser"
+	"github.com/adamakhtar/wing_commander/internal/testresult"
+	"github.com/adamakhtar/wing_commander/internal/testrun"
+	"github.com/adamakhtar/wing_commander/internal/types"
+)
+
+// MinitestAdapter runs Minitest suites through WingCommanderRunner (bin/test) and
+// reads the YAML summary written by WingCommanderReporter.
+type MinitestAdapter struct{}
+
+func (MinitestAdapter) Framework() config.TestFramework {
+	return config.FrameworkMinitest
+}
+
+// BuildCommand appends the run's paths, or its test cases via --test-cases, to the command.
+func (MinitestAdapter) BuildCommand(command string, testRun testrun.TestRun) (string, error) {
+	if command == "" {
+		return "", fmt.Errorf("command is required")
+	}
+
+	switch testRun.Mode {
+	case string(testrun.ModeRunWholeSuite):
+		return command, nil
+
+	case string(testrun.ModeRunSelectedPatterns):
+		filePathStrings := testRun.PatternsToFilePaths()
+		escapedPaths := shellEscapeList(filePathStrings)
+		return command + " " + strings.Join(escapedPaths, " "), nil
+
+	case string(testrun.ModeReRunSingleFailure), string(testrun.ModeReRunAllFailures):
+		testCaseStrings := testRun.PatternsToTestCaseIdentifiers()
+		commaSeparatedTestCases := strings.Join(testCaseStrings, ",")
+		escapedTestCases := ShellEscape(commaSeparatedTestCases)
+		return command + " --test-cases " + escapedTestCases, nil
+
+	default:
+		return "", fmt.Errorf("invalid mode: %s", testRun.Mode)
+	}
+}
+
+func (a MinitestAdapter) ParseResults(output Output) (*parser.ParseResult, error) {
+	return parser.ParseFile(output.ResultsPath, ParseOptions(a))
+}
+
+func (MinitestAdapter) ParseStackFrame(frameStr string) types.StackFrame {
+	return backtrace.ParseStackFrame(frameStr)
+}
+
+func (MinitestAdapter) ClassifyFailure(message string, topFrame *types.StackFrame) testresult.FailureCause {
+	return parser.ClassifyFailure(message, topFrame)
+}
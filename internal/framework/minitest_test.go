@@ -0,0 +1,78 @@
+package framework
+
+import (
+	"testing"
+
+	"github.com/adamakhtar/wing_commander/internal/testrun"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMinitestAdapter_BuildCommand(t *testing.T) {
+	testCaseOne := "test_one"
+	testCaseTwo := "test_two"
+	groupName := "MyGroup"
+
+	tests := []struct {
+		name    string
+		command string
+		testRun testrun.TestRun
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "whole suite",
+			command: "bundle exec rake test",
+			testRun: testrun.TestRun{Mode: string(testrun.ModeRunWholeSuite)},
+			want:    "bundle exec rake test",
+		},
+		{
+			name:    "empty command",
+			command: "",
+			testRun: testrun.TestRun{Mode: string(testrun.ModeRunWholeSuite)},
+			wantErr: true,
+		},
+		{
+			name:    "selected patterns",
+			command: "bin/test",
+			testRun: testrun.TestRun{
+				Mode: string(testrun.ModeRunSelectedPatterns),
+				Patterns: []testrun.TestPattern{
+					{Path: "test/worker_test.rb"},
+					{Path: "test/it's_test.rb"},
+				},
+			},
+			want: `bin/test 'test/worker_test.rb' 'test/it'\''s_test.rb'`,
+		},
+		{
+			name:    "re-run failures",
+			command: "bin/test",
+			testRun: testrun.TestRun{
+				Mode: string(testrun.ModeReRunAllFailures),
+				Patterns: []testrun.TestPattern{
+					{Path: "test/user_test.rb", TestCaseName: &testCaseOne, TestGroupName: &groupName},
+					{Path: "test/user_test.rb", TestCaseName: &testCaseTwo, TestGroupName: &groupName},
+				},
+			},
+			want: "bin/test --test-cases 'MyGroup#test_one,MyGroup#test_two'",
+		},
+		{
+			name:    "invalid mode",
+			command: "bin/test",
+			testRun: testrun.TestRun{Mode: "bogus"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MinitestAdapter{}.BuildCommand(tt.command, tt.testRun)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package framework
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/adamakhtar/wing_commander/internal/backtrace"
+	"github.com/adamakhtar/wing_commander/internal/config"
+	"github.com/adamakhtar/wing_commander/internal/parser"
+	"github.com/adamakhtar/wing_commander/internal/projectfs"
+	"github.com/adamakhtar/wing_commander/internal/testresult"
+	"github.com/adamakhtar/wing_commander/internal/testrun"
+	"github.com/adamakhtar/wing_commander/internal/types"
+)
+
+// PytestAdapter runs pytest suites and reads the JUnit XML (--junitxml) or
+// pytest-reportlog (--report-log) file written to the results path.
+type PytestAdapter struct{}
+
+func (PytestAdapter) Framework() config.TestFramework {
+	return config.FrameworkPytest
+}
+
+// BuildCommand appends the run's paths to the command. Re-runs pass node ids
+// ("tests/test_cart.py::TestCart::test_total"), rebuilt from the pattern when
+// the result didn't come with one.
+func (PytestAdapter) BuildCommand(command string, testRun testrun.TestRun) (string, error) {
+	if command == "" {
+		return "", fmt.Errorf("command is required")
+	}
+
+	switch testRun.Mode {
+	case string(testrun.ModeRunWholeSuite):
+		return command, nil
+
+	case string(testrun.ModeRunSelectedPatterns):
+		escapedPaths := shellEscapeList(testRun.PatternsToFilePaths())
+		return command + " " + strings.Join(escapedPaths, " "), nil
+
+	case string(testrun.ModeReRunSingleFailure), string(testrun.ModeReRunAllFailures):
+		nodeIds := make([]string, len(testRun.Patterns))
+		for i, pattern := range testRun.Patterns {
+			nodeIds[i] = ShellEscape(pytestNodeId(pattern))
+		}
+		return command + " " + strings.Join(nodeIds, " "), nil
+
+	default:
+		return "", fmt.Errorf("invalid mode: %s", testRun.Mode)
+	}
+}
+
+func (a PytestAdapter) ParseResults(output Output) (*parser.ParseResult, error) {
+	return parser.ParsePytestFile(output.ResultsPath, ParseOptions(a))
+}
+
+func (PytestAdapter) ParseStackFrame(frameStr string) types.StackFrame {
+	return backtrace.ParsePythonStackFrame(frameStr)
+}
+
+// ClassifyFailure treats bare `assert` failures as assertions and errors raised
+// from test modules or conftest.py as test definition errors.
+func (PytestAdapter) ClassifyFailure(message string, topFrame *types.StackFrame) testresult.FailureCause {
+	if strings.HasPrefix(message, "assert ") || strings.HasPrefix(message, "AssertionError") {
+		return testresult.FailureCauseAssertion
+	}
+	if topFrame != nil && isPythonTestFile(topFrame.FilePath.String()) {
+		return testresult.FailureCauseTestDefinition
+	}
+	return parser.ClassifyFailure(message, topFrame)
+}
+
+// pytestNodeId returns the pattern's node id, building "path::Group::name" from
+// its parts when no TestId is set.
+func pytestNodeId(pattern testrun.TestPattern) string {
+	if pattern.TestId != nil && *pattern.TestId != "" {
+		return *pattern.TestId
+	}
+
+	path := pattern.Path
+	if filepath.IsAbs(path) {
+		if rel, err := projectfs.GetProjectFS().Rel(types.AbsPath(path)); err == nil {
+			path = filepath.ToSlash(rel.String())
+		}
+	}
+	if pattern.TestCaseName == nil || *pattern.TestCaseName == "" {
+		return path
+	}
+
+	parts := []string{path}
+	if pattern.TestGroupName != nil && *pattern.TestGroupName != "" && *pattern.TestGroupName != path {
+		parts = append(parts, *pattern.TestGroupName)
+	}
+	return strings.Join(append(parts, *pattern.TestCaseName), "::")
+}
+
+// isPythonTestFile reports whether path follows pytest's test module naming.
+func isPythonTestFile(path string) bool {
+	base := filepath.Base(path)
+	return base == "conftest.py" ||
+		(strings.HasPrefix(base, "test_") && strings.HasSuffix(base, ".py")) ||
+		strings.HasSuffix(base, "_test.py")
+}
@@ -0,0 +1,94 @@
+package framework
+
+import (
+	"testing"
+
+	"github.com/adamakhtar/wing_commander/internal/projectfs"
+	"github.com/adamakhtar/wing_commander/internal/testresult"
+	"github.com/adamakhtar/wing_commander/internal/testrun"
+	"github.com/adamakhtar/wing_commander/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPytestAdapter_BuildCommand(t *testing.T) {
+	rootPath, _ := types.NewAbsPath("/path/to/project")
+	require.NoError(t, projectfs.InitProjectFS(rootPath, ""))
+
+	nodeId := "tests/test_cart.py::TestCart::test_total[empty]"
+	testCaseName := "test_checkout"
+	groupName := "TestCheckout"
+	moduleGroupName := "tests/test_orders.py"
+
+	tests := []struct {
+		name    string
+		testRun testrun.TestRun
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "whole suite",
+			testRun: testrun.TestRun{Mode: string(testrun.ModeRunWholeSuite)},
+			want:    "pytest",
+		},
+		{
+			name: "selected patterns",
+			testRun: testrun.TestRun{
+				Mode:     string(testrun.ModeRunSelectedPatterns),
+				Patterns: []testrun.TestPattern{{Path: "tests/test_cart.py"}, {Path: "tests/api"}},
+			},
+			want: "pytest 'tests/test_cart.py' 'tests/api'",
+		},
+		{
+			name: "re-run single failure by node id",
+			testRun: testrun.TestRun{
+				Mode:     string(testrun.ModeReRunSingleFailure),
+				Patterns: []testrun.TestPattern{{Path: "/path/to/project/tests/test_cart.py", TestId: &nodeId}},
+			},
+			want: "pytest 'tests/test_cart.py::TestCart::test_total[empty]'",
+		},
+		{
+			name: "re-run failures without node ids",
+			testRun: testrun.TestRun{
+				Mode: string(testrun.ModeReRunAllFailures),
+				Patterns: []testrun.TestPattern{
+					{Path: "/path/to/project/tests/test_checkout.py", TestCaseName: &testCaseName, TestGroupName: &groupName},
+					{Path: "/path/to/project/tests/test_orders.py", TestCaseName: &testCaseName, TestGroupName: &moduleGroupName},
+				},
+			},
+			want: "pytest 'tests/test_checkout.py::TestCheckout::test_checkout' 'tests/test_orders.py::test_checkout'",
+		},
+		{
+			name:    "invalid mode",
+			testRun: testrun.TestRun{Mode: "bogus"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := PytestAdapter{}.BuildCommand("pytest", tt.testRun)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPytestAdapter_ClassifyFailure(t *testing.T) {
+	rootPath, _ := types.NewAbsPath("/path/to/project")
+	require.NoError(t, projectfs.InitProjectFS(rootPath, ""))
+
+	testFrame := &types.StackFrame{FilePath: "/path/to/project/tests/test_cart.py", Line: 9}
+	conftestFrame := &types.StackFrame{FilePath: "/path/to/project/tests/conftest.py", Line: 3}
+	codeFrame := &types.StackFrame{FilePath: "/path/to/project/shop/cart.py", Line: 8}
+
+	adapter := PytestAdapter{}
+	assert.Equal(t, testresult.FailureCauseAssertion, adapter.ClassifyFailure("assert 90 == 85", testFrame))
+	assert.Equal(t, testresult.FailureCauseTestDefinition, adapter.ClassifyFailure("NameError: name 'cart' is not defined", testFrame))
+	assert.Equal(t, testresult.FailureCauseTestDefinition, adapter.ClassifyFailure("ValueError: bad fixture", conftestFrame))
+	assert.Equal(t, testresult.FailureCauseProductionCode, adapter.ClassifyFailure("ZeroDivisionError: division by zero", codeFrame))
+}
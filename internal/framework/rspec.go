@@ -0,0 +1,90 @@
+package framework
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/adamakhtar/wing_commander/internal/backtrace"
+	"github.com/adamakhtar/wing_commander/internal/config"
+	"github.com/adamakhtar/wing_commander/internal/parser"
+	"github.com/adamakhtar/wing_commander/internal/testresult"
+	"github.com/adamakhtar/wing_commander/internal/testrun"
+	"github.com/adamakhtar/wing_commander/internal/types"
+)
+
+// RSpecAdapter runs RSpec suites and reads the YAML summary written by
+// WingCommanderFormatter, which emits the WingCommanderReporter schema.
+type RSpecAdapter struct{}
+
+func (RSpecAdapter) Framework() config.TestFramework {
+	return config.FrameworkRSpec
+}
+
+// BuildCommand appends the run's locations to the command. Re-runs prefer the
+// example id ("./spec/user_spec.rb[1:2]"), then "path:line", then --example.
+func (RSpecAdapter) BuildCommand(command string, testRun testrun.TestRun) (string, error) {
+	if command == "" {
+		return "", fmt.Errorf("command is required")
+	}
+
+	switch testRun.Mode {
+	case string(testrun.ModeRunWholeSuite):
+		return command, nil
+
+	case string(testrun.ModeRunSelectedPatterns):
+		args := make([]string, len(testRun.Patterns))
+		for i, pattern := range testRun.Patterns {
+			args[i] = ShellEscape(rspecLocation(pattern))
+		}
+		return command + " " + strings.Join(args, " "), nil
+
+	case string(testrun.ModeReRunSingleFailure), string(testrun.ModeReRunAllFailures):
+		var args []string
+		for _, pattern := range testRun.Patterns {
+			switch {
+			case pattern.TestId != nil && *pattern.TestId != "":
+				args = append(args, ShellEscape(*pattern.TestId))
+			case pattern.LineNumber != nil && *pattern.LineNumber > 0:
+				args = append(args, ShellEscape(rspecLocation(pattern)))
+			case pattern.TestCaseName != nil:
+				args = append(args, ShellEscape(pattern.Path), "--example", ShellEscape(rspecFullDescription(pattern)))
+			default:
+				args = append(args, ShellEscape(pattern.Path))
+			}
+		}
+		return command + " " + strings.Join(args, " "), nil
+
+	default:
+		return "", fmt.Errorf("invalid mode: %s", testRun.Mode)
+	}
+}
+
+func (a RSpecAdapter) ParseResults(output Output) (*parser.ParseResult, error) {
+	return parser.ParseFile(output.ResultsPath, ParseOptions(a))
+}
+
+func (RSpecAdapter) ParseStackFrame(frameStr string) types.StackFrame {
+	return backtrace.ParseStackFrame(frameStr)
+}
+
+func (RSpecAdapter) ClassifyFailure(message string, topFrame *types.StackFrame) testresult.FailureCause {
+	return parser.ClassifyFailure(message, topFrame)
+}
+
+// rspecLocation returns "path:line" when the pattern has a line number, otherwise the path.
+func rspecLocation(pattern testrun.TestPattern) string {
+	if pattern.LineNumber == nil || *pattern.LineNumber <= 0 {
+		return pattern.Path
+	}
+	return pattern.Path + ":" + strconv.Itoa(*pattern.LineNumber)
+}
+
+// rspecFullDescription rebuilds the example's full description ("User#save persists")
+// from the group and test case names reported by WingCommanderFormatter.
+func rspecFullDescription(pattern testrun.TestPattern) string {
+	if pattern.TestGroupName == nil || *pattern.TestGroupName == "" {
+		return *pattern.TestCaseName
+	}
+	return *pattern.TestGroupName + " " + *pattern.TestCaseName
+}
@@ -0,0 +1,84 @@
+package framework
+
+import (
+	"testing"
+
+	"github.com/adamakhtar/wing_commander/internal/testrun"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRSpecAdapter_BuildCommand(t *testing.T) {
+	line := 12
+	zeroLine := 0
+	exampleId := "./spec/models/user_spec.rb[1:2]"
+	testCaseName := "persists the record"
+	groupName := "User#save"
+
+	tests := []struct {
+		name    string
+		testRun testrun.TestRun
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "whole suite",
+			testRun: testrun.TestRun{Mode: string(testrun.ModeRunWholeSuite)},
+			want:    "bundle exec rspec",
+		},
+		{
+			name: "selected patterns with and without line",
+			testRun: testrun.TestRun{
+				Mode: string(testrun.ModeRunSelectedPatterns),
+				Patterns: []testrun.TestPattern{
+					{Path: "spec/models"},
+					{Path: "spec/models/user_spec.rb", LineNumber: &line},
+				},
+			},
+			want: "bundle exec rspec 'spec/models' 'spec/models/user_spec.rb:12'",
+		},
+		{
+			name: "re-run single failure by example id",
+			testRun: testrun.TestRun{
+				Mode: string(testrun.ModeReRunSingleFailure),
+				Patterns: []testrun.TestPattern{
+					{Path: "/abs/spec/models/user_spec.rb", LineNumber: &line, TestId: &exampleId},
+				},
+			},
+			want: "bundle exec rspec './spec/models/user_spec.rb[1:2]'",
+		},
+		{
+			name: "re-run failures by location and example name",
+			testRun: testrun.TestRun{
+				Mode: string(testrun.ModeReRunAllFailures),
+				Patterns: []testrun.TestPattern{
+					{Path: "/abs/spec/models/user_spec.rb", LineNumber: &line},
+					{
+						Path:          "/abs/spec/models/post_spec.rb",
+						LineNumber:    &zeroLine,
+						TestCaseName:  &testCaseName,
+						TestGroupName: &groupName,
+					},
+				},
+			},
+			want: "bundle exec rspec '/abs/spec/models/user_spec.rb:12' '/abs/spec/models/post_spec.rb' --example 'User#save persists the record'",
+		},
+		{
+			name:    "invalid mode",
+			testRun: testrun.TestRun{Mode: "bogus"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RSpecAdapter{}.BuildCommand("bundle exec rspec", tt.testRun)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package framework
+
+import "strings"
+
+// ShellEscape escapes a string for safe use in shell commands.
+// Wraps the string in single quotes and escapes any single quotes within it.
+func ShellEscape(s string) string {
+	if s == "" {
+		return "''"
+	}
+	// Replace single quotes with: '\'' (end quote, escaped quote, start quote)
+	return "'" + strings.ReplaceAll(s, "'", "'\\''") + "'"
+}
+
+func shellEscapeList(strings []string) []string {
+	escapedStrings := make([]string, len(strings))
+	for i, s := range strings {
+		escapedStrings[i] = ShellEscape(s)
+	}
+	return escapedStrings
+}
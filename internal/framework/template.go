@@ -0,0 +1,215 @@
+package framework
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/adamakhtar/wing_commander/internal/testrun"
+)
+
+// placeholderRegex matches "%{name}" and "%{name:modifier}" placeholders.
+var placeholderRegex = regexp.MustCompile(`%\{([^}]*)\}`)
+
+// legacyPathsPlaceholder is the text/template style placeholder earlier
+// versions documented. It renders like %{paths}.
+const legacyPathsPlaceholder = "{{.Paths}}"
+
+// rawModifier renders a placeholder without shell escaping, e.g. %{test_case_name:raw}.
+const rawModifier = "raw"
+
+// placeholder renders the values of a single placeholder for a test run. Each
+// value is shell escaped on its own unless the raw modifier is used, then the
+// values are joined with spaces.
+type placeholder struct {
+	values  func(testRun testrun.TestRun) []string
+	numeric bool // Values are digits and never need escaping
+}
+
+var placeholders = map[string]placeholder{
+	// Selected test files, each once
+	"paths": {values: uniquePaths},
+	// "path:line" of every pattern, or just the path when its line is unknown
+	"locations": {values: locations},
+	// File, line, name and group of the first pattern, for single test commands
+	"test_file_path":  {values: first(uniquePaths)},
+	"line_number":     {values: first(lineNumbers), numeric: true},
+	"test_case_name":  {values: first(testCaseNames)},
+	"test_group_name": {values: first(testGroupNames)},
+	// Every test case and group name, each once
+	"test_case_names":  {values: testCaseNames},
+	"test_group_names": {values: testGroupNames},
+	"line_numbers":     {values: lineNumbers, numeric: true},
+	// "Group#name,Group#name" as WingCommanderRunner's --test-cases expects
+	"test_cases": {values: joined(testCaseIdentifiers, ",")},
+	// Anchored regex matching every test case name, e.g. for minitest -n or jest -t
+	"test_case_pattern": {values: testCasePattern},
+	"seed":              {values: seed, numeric: true},
+}
+
+// HasPlaceholders reports whether command is a template rather than a plain
+// command the adapter appends arguments to.
+func HasPlaceholders(command string) bool {
+	return placeholderRegex.MatchString(command) || strings.Contains(command, legacyPathsPlaceholder)
+}
+
+// RenderCommand fills in the placeholders of a command template for a test run.
+// Placeholders without a value, like %{test_case_name} in a whole suite run,
+// render as nothing.
+func RenderCommand(template string, testRun testrun.TestRun) (string, error) {
+	template = strings.ReplaceAll(template, legacyPathsPlaceholder, "%{paths}")
+
+	var renderErr error
+	rendered := placeholderRegex.ReplaceAllStringFunc(template, func(match string) string {
+		name, modifier, _ := strings.Cut(placeholderRegex.FindStringSubmatch(match)[1], ":")
+		p, ok := placeholders[name]
+		if !ok {
+			renderErr = fmt.Errorf("unknown placeholder %s in command template (supported: %s)", match, strings.Join(PlaceholderNames(), ", "))
+			return match
+		}
+		if modifier != "" && modifier != rawModifier {
+			renderErr = fmt.Errorf("unknown modifier %q in placeholder %s (supported: %s)", modifier, match, rawModifier)
+			return match
+		}
+
+		values := p.values(testRun)
+		if modifier != rawModifier && !p.numeric {
+			values = shellEscapeList(values)
+		}
+		return strings.Join(values, " ")
+	})
+	if renderErr != nil {
+		return "", renderErr
+	}
+	return rendered, nil
+}
+
+// PlaceholderNames returns the supported placeholders in alphabetical order.
+func PlaceholderNames() []string {
+	names := make([]string, 0, len(placeholders))
+	for name := range placeholders {
+		names = append(names, "%{"+name+"}")
+	}
+	slices.Sort(names)
+	return names
+}
+
+// BuildTestRunCommand renders command when it is a template, otherwise the
+// adapter appends the test run's arguments to it.
+func BuildTestRunCommand(adapter Adapter, command string, testRun testrun.TestRun) (string, error) {
+	if !HasPlaceholders(command) {
+		return adapter.BuildCommand(command, testRun)
+	}
+	return RenderCommand(command, testRun)
+}
+
+func uniquePaths(testRun testrun.TestRun) []string {
+	var paths []string
+	for _, pattern := range testRun.Patterns {
+		if !slices.Contains(paths, pattern.Path) {
+			paths = append(paths, pattern.Path)
+		}
+	}
+	return paths
+}
+
+func locations(testRun testrun.TestRun) []string {
+	locations := make([]string, 0, len(testRun.Patterns))
+	for _, pattern := range testRun.Patterns {
+		location := pattern.Path
+		if pattern.LineNumber != nil && *pattern.LineNumber > 0 {
+			location += ":" + strconv.Itoa(*pattern.LineNumber)
+		}
+		if !slices.Contains(locations, location) {
+			locations = append(locations, location)
+		}
+	}
+	return locations
+}
+
+func lineNumbers(testRun testrun.TestRun) []string {
+	var lines []string
+	for _, pattern := range testRun.Patterns {
+		if pattern.LineNumber != nil && *pattern.LineNumber > 0 {
+			lines = append(lines, strconv.Itoa(*pattern.LineNumber))
+		}
+	}
+	return lines
+}
+
+func testCaseNames(testRun testrun.TestRun) []string {
+	var names []string
+	for _, pattern := range testRun.Patterns {
+		if pattern.TestCaseName != nil && *pattern.TestCaseName != "" && !slices.Contains(names, *pattern.TestCaseName) {
+			names = append(names, *pattern.TestCaseName)
+		}
+	}
+	return names
+}
+
+func testGroupNames(testRun testrun.TestRun) []string {
+	var names []string
+	for _, pattern := range testRun.Patterns {
+		if pattern.TestGroupName != nil && *pattern.TestGroupName != "" && !slices.Contains(names, *pattern.TestGroupName) {
+			names = append(names, *pattern.TestGroupName)
+		}
+	}
+	return names
+}
+
+func testCaseIdentifiers(testRun testrun.TestRun) []string {
+	var identifiers []string
+	for _, pattern := range testRun.Patterns {
+		if pattern.TestCaseName == nil || *pattern.TestCaseName == "" {
+			continue
+		}
+		identifier := *pattern.TestCaseName
+		if pattern.TestGroupName != nil && *pattern.TestGroupName != "" {
+			identifier = *pattern.TestGroupName + "#" + identifier
+		}
+		identifiers = append(identifiers, identifier)
+	}
+	return identifiers
+}
+
+func testCasePattern(testRun testrun.TestRun) []string {
+	names := testCaseNames(testRun)
+	if len(names) == 0 {
+		return nil
+	}
+	for i, name := range names {
+		names[i] = regexp.QuoteMeta(name)
+	}
+	return []string{"^(" + strings.Join(names, "|") + ")$"}
+}
+
+func seed(testRun testrun.TestRun) []string {
+	if testRun.Seed == 0 {
+		return nil
+	}
+	return []string{strconv.Itoa(testRun.Seed)}
+}
+
+// first narrows a placeholder to its first value.
+func first(values func(testrun.TestRun) []string) func(testrun.TestRun) []string {
+	return func(testRun testrun.TestRun) []string {
+		all := values(testRun)
+		if len(all) == 0 {
+			return nil
+		}
+		return all[:1]
+	}
+}
+
+// joined combines a placeholder's values into a single value.
+func joined(values func(testrun.TestRun) []string, sep string) func(testrun.TestRun) []string {
+	return func(testRun testrun.TestRun) []string {
+		all := values(testRun)
+		if len(all) == 0 {
+			return nil
+		}
+		return []string{strings.Join(all, sep)}
+	}
+}
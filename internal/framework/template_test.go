@@ -0,0 +1,128 @@
+package framework
+
+import (
+	"testing"
+
+	"github.com/adamakhtar/wing_commander/internal/testrun"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderCommand(t *testing.T) {
+	testCaseOne := "test_one"
+	testCaseTwo := "test it's two"
+	groupName := "UserTest"
+	lineOne := 12
+	lineTwo := 30
+
+	reRun := testrun.TestRun{
+		Mode: string(testrun.ModeReRunAllFailures),
+		Seed: 4242,
+		Patterns: []testrun.TestPattern{
+			{Path: "test/user_test.rb", LineNumber: &lineOne, TestCaseName: &testCaseOne, TestGroupName: &groupName},
+			{Path: "test/user_test.rb", LineNumber: &lineTwo, TestCaseName: &testCaseTwo, TestGroupName: &groupName},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		testRun  testrun.TestRun
+		want     string
+		wantErr  string
+	}{
+		{
+			name:     "rails test locations",
+			template: "bin/rails test %{locations}",
+			testRun:  reRun,
+			want:     "bin/rails test 'test/user_test.rb:12' 'test/user_test.rb:30'",
+		},
+		{
+			name:     "single test case",
+			template: "ruby -Itest %{test_file_path} -n %{test_case_name} --seed %{seed}",
+			testRun:  reRun,
+			want:     "ruby -Itest 'test/user_test.rb' -n 'test_one' --seed 4242",
+		},
+		{
+			name:     "test case pattern",
+			template: "ruby -Itest %{paths} -n %{test_case_pattern}",
+			testRun:  reRun,
+			want:     `ruby -Itest 'test/user_test.rb' -n '^(test_one|test it'\''s two)$'`,
+		},
+		{
+			name:     "lists",
+			template: "run %{test_case_names} %{test_group_names} %{line_numbers} %{line_number}",
+			testRun:  reRun,
+			want:     `run 'test_one' 'test it'\''s two' 'UserTest' 12 30 12`,
+		},
+		{
+			name:     "wing commander runner test cases",
+			template: "bin/test --test-cases %{test_cases}",
+			testRun:  reRun,
+			want:     `bin/test --test-cases 'UserTest#test_one,UserTest#test it'\''s two'`,
+		},
+		{
+			name:     "raw modifier",
+			template: `ruby -Itest %{test_file_path} -n "/%{test_case_name:raw}/"`,
+			testRun:  reRun,
+			want:     `ruby -Itest 'test/user_test.rb' -n "/test_one/"`,
+		},
+		{
+			name:     "legacy paths placeholder",
+			template: "bundle exec rake test {{.Paths}}",
+			testRun: testrun.TestRun{
+				Mode:     string(testrun.ModeRunSelectedPatterns),
+				Patterns: []testrun.TestPattern{{Path: "test/a_test.rb"}, {Path: "test/b_test.rb"}},
+			},
+			want: "bundle exec rake test 'test/a_test.rb' 'test/b_test.rb'",
+		},
+		{
+			name:     "whole suite renders placeholders as nothing",
+			template: "bundle exec rake test %{paths}",
+			testRun:  testrun.TestRun{Mode: string(testrun.ModeRunWholeSuite)},
+			want:     "bundle exec rake test ",
+		},
+		{
+			name:     "unknown placeholder",
+			template: "bin/test %{file}",
+			testRun:  reRun,
+			wantErr:  "unknown placeholder %{file}",
+		},
+		{
+			name:     "unknown modifier",
+			template: "bin/test %{paths:upper}",
+			testRun:  reRun,
+			wantErr:  `unknown modifier "upper"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderCommand(tt.template, tt.testRun)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestBuildTestRunCommand(t *testing.T) {
+	testRun := testrun.TestRun{
+		Mode:     string(testrun.ModeRunSelectedPatterns),
+		Patterns: []testrun.TestPattern{{Path: "test/a_test.rb"}},
+	}
+
+	// Plain commands are left to the adapter
+	got, err := BuildTestRunCommand(MinitestAdapter{}, "bin/test", testRun)
+	require.NoError(t, err)
+	assert.Equal(t, "bin/test 'test/a_test.rb'", got)
+
+	// Templates are rendered as is
+	got, err = BuildTestRunCommand(MinitestAdapter{}, "bin/rails test %{paths} -v", testRun)
+	require.NoError(t, err)
+	assert.Equal(t, "bin/rails test 'test/a_test.rb' -v", got)
+}
@@ -0,0 +1,87 @@
+package git
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Revision returns the HEAD commit of the repository dir is in and whether its
+// work tree has uncommitted changes. It fails outside a git repository and in
+// repositories without commits.
+func Revision(dir string) (string, bool, error) {
+	sha, err := exec.Command("git", "-C", dir, "rev-parse", "HEAD").Output()
+	if err != nil {
+		return "", false, err
+	}
+
+	status, err := exec.Command("git", "-C", dir, "status", "--porcelain", "--untracked-files=no").Output()
+	if err != nil {
+		return "", false, err
+	}
+
+	return strings.TrimSpace(string(sha)), len(strings.TrimSpace(string(status))) > 0, nil
+}
+
+// DirtyFiles returns the files with uncommitted changes in dir, including
+// untracked files that aren't ignored, by path relative to dir. Each maps to a
+// hash of its content, or "deleted", so two work trees can be compared.
+func DirtyFiles(dir string) (map[string]string, error) {
+	changed, err := exec.Command("git", "-C", dir, "diff", "--name-only", "--relative", "HEAD").Output()
+	if err != nil {
+		return nil, err
+	}
+	untracked, err := exec.Command("git", "-C", dir, "ls-files", "--others", "--exclude-standard").Output()
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string]string)
+	for _, path := range append(lines(changed), lines(untracked)...) {
+		hash, err := hashFile(filepath.Join(dir, path))
+		if err != nil {
+			return nil, err
+		}
+		files[path] = hash
+	}
+	return files, nil
+}
+
+// FilesChangedBetween returns the files in dir changed from one commit to
+// another, by path relative to dir.
+func FilesChangedBetween(dir string, from string, to string) ([]string, error) {
+	if from == to {
+		return nil, nil
+	}
+	changed, err := exec.Command("git", "-C", dir, "diff", "--name-only", "--relative", from, to).Output()
+	if err != nil {
+		return nil, err
+	}
+	return lines(changed), nil
+}
+
+func hashFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "deleted", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func lines(output []byte) []string {
+	var result []string
+	for _, line := range strings.Split(string(output), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			result = append(result, line)
+		}
+	}
+	return result
+}
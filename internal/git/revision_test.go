@@ -0,0 +1,86 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRevision(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+
+	_, _, err := Revision(dir)
+	assert.Error(t, err, "not a repository")
+
+	gitCmd := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-C", dir, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		require.NoError(t, cmd.Run(), args)
+	}
+	gitCmd("init", "-q")
+	file := filepath.Join(dir, "user.rb")
+	require.NoError(t, os.WriteFile(file, []byte("class User; end\n"), 0o644))
+	gitCmd("add", "user.rb")
+	gitCmd("commit", "-q", "-m", "Add user")
+
+	sha, dirty, err := Revision(dir)
+	require.NoError(t, err)
+	assert.Len(t, sha, 40)
+	assert.False(t, dirty)
+
+	require.NoError(t, os.WriteFile(file, []byte("class User < Base; end\n"), 0o644))
+	_, dirty, err = Revision(dir)
+	require.NoError(t, err)
+	assert.True(t, dirty)
+}
+
+func TestDirtyFilesAndFilesChangedBetween(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	gitCmd := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-C", dir, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		require.NoError(t, cmd.Run(), args)
+	}
+	gitCmd("init", "-q")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "user.rb"), []byte("class User; end\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "cart.rb"), []byte("class Cart; end\n"), 0o644))
+	gitCmd("add", ".")
+	gitCmd("commit", "-q", "-m", "Add models")
+	first, _, err := Revision(dir)
+	require.NoError(t, err)
+
+	files, err := DirtyFiles(dir)
+	require.NoError(t, err)
+	assert.Empty(t, files)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "user.rb"), []byte("class User < Base; end\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "order.rb"), []byte("class Order; end\n"), 0o644))
+	require.NoError(t, os.Remove(filepath.Join(dir, "cart.rb")))
+	files, err = DirtyFiles(dir)
+	require.NoError(t, err)
+	assert.Len(t, files, 3)
+	assert.Len(t, files["user.rb"], 64)
+	assert.NotEqual(t, files["user.rb"], files["order.rb"])
+	assert.Equal(t, "deleted", files["cart.rb"])
+
+	gitCmd("add", "user.rb")
+	gitCmd("commit", "-q", "-m", "Change user")
+	second, _, err := Revision(dir)
+	require.NoError(t, err)
+
+	changed, err := FilesChangedBetween(dir, first, second)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"user.rb"}, changed)
+
+	changed, err = FilesChangedBetween(dir, second, second)
+	require.NoError(t, err)
+	assert.Empty(t, changed)
+}
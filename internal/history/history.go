@@ -0,0 +1,237 @@
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/adamakhtar/wing_commander/internal/runner"
+	"github.com/adamakhtar/wing_commander/internal/testresult"
+	"github.com/adamakhtar/wing_commander/internal/testrun"
+	"github.com/charmbracelet/log"
+)
+
+const (
+	recordPrefix = "run-"
+	recordSuffix = ".json"
+	// lastIdFile keeps the highest test run ID ever saved, so IDs stay
+	// monotonic after the newest runs are deleted.
+	lastIdFile = "last_id"
+)
+
+// Record is a finished test run and its results as stored in the history.
+type Record struct {
+	TestRun       testrun.TestRun         `json:"test_run"`
+	Results       []testresult.TestResult `json:"results"`
+	ExecutionTime time.Time               `json:"execution_time"`
+	CommandOutput string                  `json:"command_output,omitempty"`
+	Cancelled     bool                    `json:"cancelled,omitempty"`
+	Crashed       bool                    `json:"crashed,omitempty"`
+	CrashReason   string                  `json:"crash_reason,omitempty"`
+	Error         string                  `json:"error,omitempty"` // Why the run produced no results, e.g. the command couldn't be started
+}
+
+// NewRecord builds the record of a finished test run. result is nil when the
+// run failed with runErr before producing results.
+func NewRecord(testRun testrun.TestRun, result *runner.TestExecutionResult, runErr error) Record {
+	record := Record{TestRun: testRun}
+	if runErr != nil {
+		record.Error = runErr.Error()
+	}
+	if result == nil {
+		return record
+	}
+
+	record.Results = result.TestResults
+	record.ExecutionTime = result.ExecutionTime
+	record.CommandOutput = result.CommandOutput
+	record.Cancelled = result.Cancelled
+	record.Crashed = result.Crashed
+	record.CrashReason = result.CrashReason
+	return record
+}
+
+// TestExecutionResult rebuilds the run's results, nil when it has none.
+func (r Record) TestExecutionResult() *runner.TestExecutionResult {
+	if r.Error != "" && r.Results == nil {
+		return nil
+	}
+
+	result := runner.NewTestExecutionResult(r.TestRun.Id, r.Results)
+	result.ExecutionTime = r.ExecutionTime
+	result.CommandOutput = r.CommandOutput
+	result.Cancelled = r.Cancelled
+	result.Crashed = r.Crashed
+	result.CrashReason = r.CrashReason
+	return result
+}
+
+// Store keeps finished test runs as one JSON file per run in a directory,
+// pruning the oldest once there are more than its limit.
+type Store struct {
+	dir   string
+	limit int
+	mu    sync.Mutex
+}
+
+// NewStore returns a store keeping up to limit runs in dir. The directory is
+// created on the first save.
+func NewStore(dir string, limit int) *Store {
+	return &Store{dir: dir, limit: limit}
+}
+
+// Save writes the record, replacing an earlier record of the same run, and
+// prunes the oldest runs beyond the limit.
+func (s *Store) Save(record Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create history directory %s: %w", s.dir, err)
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode test run %d: %w", record.TestRun.Id, err)
+	}
+	if err := writeFileAtomic(s.recordPath(record.TestRun.Id), data); err != nil {
+		return fmt.Errorf("failed to save test run %d: %w", record.TestRun.Id, err)
+	}
+
+	if record.TestRun.Id > s.lastId() {
+		if err := writeFileAtomic(filepath.Join(s.dir, lastIdFile), []byte(strconv.Itoa(record.TestRun.Id)+"\n")); err != nil {
+			return fmt.Errorf("failed to save the last test run id: %w", err)
+		}
+	}
+
+	return s.prune()
+}
+
+// Delete removes the record of a test run. The last test run ID is kept, so
+// the ID isn't reused.
+func (s *Store) Delete(id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.Remove(s.recordPath(id)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete test run %d: %w", id, err)
+	}
+	return nil
+}
+
+// Load returns the stored runs, oldest first, and the highest test run ID
+// ever saved. Records that can't be read are skipped.
+func (s *Store) Load() ([]Record, int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids, err := s.ids()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	lastId := s.lastId()
+	records := make([]Record, 0, len(ids))
+	for _, id := range ids {
+		data, err := os.ReadFile(s.recordPath(id))
+		if err != nil {
+			log.Warn("failed to read test run from history", "id", id, "error", err)
+			continue
+		}
+		var record Record
+		if err := json.Unmarshal(data, &record); err != nil {
+			log.Warn("failed to decode test run from history", "id", id, "error", err)
+			continue
+		}
+		records = append(records, record)
+		lastId = max(lastId, id)
+	}
+	return records, lastId, nil
+}
+
+// prune removes the oldest runs beyond the limit.
+func (s *Store) prune() error {
+	ids, err := s.ids()
+	if err != nil {
+		return err
+	}
+	if s.limit <= 0 || len(ids) <= s.limit {
+		return nil
+	}
+
+	for _, id := range ids[:len(ids)-s.limit] {
+		if err := os.Remove(s.recordPath(id)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to prune test run %d: %w", id, err)
+		}
+	}
+	return nil
+}
+
+// ids returns the IDs of the stored runs in ascending order.
+func (s *Store) ids() ([]int, error) {
+	entries, err := os.ReadDir(s.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history directory %s: %w", s.dir, err)
+	}
+
+	var ids []int
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, recordPrefix) || !strings.HasSuffix(name, recordSuffix) {
+			continue
+		}
+		id, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(name, recordPrefix), recordSuffix))
+		if err != nil {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids, nil
+}
+
+// lastId returns the highest test run ID saved, 0 when unknown.
+func (s *Store) lastId() int {
+	data, err := os.ReadFile(filepath.Join(s.dir, lastIdFile))
+	if err != nil {
+		return 0
+	}
+	id, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return 0
+	}
+	return id
+}
+
+func (s *Store) recordPath(id int) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%s%d%s", recordPrefix, id, recordSuffix))
+}
+
+// writeFileAtomic writes data to a temporary file next to path and renames it
+// into place, so a crash never leaves a half written record behind.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,149 @@
+package history
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/adamakhtar/wing_commander/internal/backtrace"
+	"github.com/adamakhtar/wing_commander/internal/runner"
+	"github.com/adamakhtar/wing_commander/internal/testresult"
+	"github.com/adamakhtar/wing_commander/internal/testrun"
+	"github.com/adamakhtar/wing_commander/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func finishedRun(id int) testrun.TestRun {
+	startedAt := time.Date(2025, 11, 14, 21, 7, 0, 0, time.UTC)
+	return testrun.TestRun{
+		Id:         id,
+		Mode:       string(testrun.ModeRunWholeSuite),
+		Status:     testrun.StatusCompleted,
+		Seed:       1234,
+		StartedAt:  startedAt,
+		FinishedAt: startedAt.Add(3 * time.Second),
+		GitSHA:     "4ec337b0c2f5d8a1e9b7c6d5e4f3a2b1c0d9e8f7",
+		GitDirty:   true,
+	}
+}
+
+func failedResult() testresult.TestResult {
+	result := testresult.NewTestResult("UserTest", "test_name", testresult.StatusFail)
+	result.Id = 1
+	result.FailureCause = testresult.FailureCauseAssertion
+	result.FailureDetails = "Expected: 1\n  Actual: 2"
+	result.TestFilePath = types.AbsPath("/project/test/user_test.rb")
+	result.TestLineNumber = 3
+	result.FullBacktrace = backtrace.Backtrace{Frames: []types.StackFrame{
+		{FilePath: "/project/test/user_test.rb", Line: 4, Function: "test_name"},
+	}}
+	result.FilteredBacktrace = result.FullBacktrace
+	result.Duration = 0.25
+	return result
+}
+
+func TestStore_SaveAndLoad(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "history"), 10)
+	passed := testresult.NewTestResult("UserTest", "test_ok", testresult.StatusPass)
+	passed.Id = 2
+	result := runner.NewTestExecutionResult(7, []testresult.TestResult{failedResult(), passed})
+	result.CommandOutput = "2 runs, 1 failure"
+	pattern, _ := testrun.NewTestPattern("test/user_test.rb", nil, nil, nil)
+	testRun := finishedRun(7)
+	testRun.Mode = string(testrun.ModeRunSelectedPatterns)
+	testRun.Patterns = []testrun.TestPattern{pattern}
+
+	require.NoError(t, store.Save(NewRecord(testRun, result, nil)))
+
+	records, lastId, err := store.Load()
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, 7, lastId)
+	assert.Equal(t, testRun, records[0].TestRun)
+
+	loaded := records[0].TestExecutionResult()
+	require.NotNil(t, loaded)
+	assert.Equal(t, 7, loaded.TestRunId)
+	assert.Equal(t, result.TestResults, loaded.TestResults)
+	assert.Equal(t, result.Metrics, loaded.Metrics)
+	assert.Len(t, loaded.FailedTests, 1)
+	assert.Equal(t, "2 runs, 1 failure", loaded.CommandOutput)
+	assert.True(t, result.ExecutionTime.Equal(loaded.ExecutionTime))
+}
+
+func TestStore_LoadWithoutHistory(t *testing.T) {
+	records, lastId, err := NewStore(filepath.Join(t.TempDir(), "missing"), 10).Load()
+
+	require.NoError(t, err)
+	assert.Empty(t, records)
+	assert.Zero(t, lastId)
+}
+
+func TestStore_PrunesOldestRuns(t *testing.T) {
+	store := NewStore(t.TempDir(), 2)
+	for _, id := range []int{1, 2, 3} {
+		require.NoError(t, store.Save(NewRecord(finishedRun(id), runner.NewTestExecutionResult(id, nil), nil)))
+	}
+
+	records, lastId, err := store.Load()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, 2, records[0].TestRun.Id)
+	assert.Equal(t, 3, records[1].TestRun.Id)
+	assert.Equal(t, 3, lastId)
+}
+
+func TestStore_LastIdOutlivesDeletedRuns(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(dir, 10)
+	require.NoError(t, store.Save(NewRecord(finishedRun(4), nil, nil)))
+	require.NoError(t, store.Save(NewRecord(finishedRun(9), nil, nil)))
+	require.NoError(t, os.Remove(filepath.Join(dir, "run-9.json")))
+
+	records, lastId, err := store.Load()
+	require.NoError(t, err)
+	assert.Len(t, records, 1)
+	assert.Equal(t, 9, lastId)
+}
+
+func TestStore_Delete(t *testing.T) {
+	store := NewStore(t.TempDir(), 10)
+	require.NoError(t, store.Save(NewRecord(finishedRun(1), nil, nil)))
+	require.NoError(t, store.Save(NewRecord(finishedRun(2), nil, nil)))
+
+	require.NoError(t, store.Delete(2))
+	require.NoError(t, store.Delete(5), "deleting a run that isn't stored is fine")
+
+	records, lastId, err := store.Load()
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, 1, records[0].TestRun.Id)
+	assert.Equal(t, 2, lastId)
+}
+
+func TestStore_SkipsUnreadableRecords(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(dir, 10)
+	require.NoError(t, store.Save(NewRecord(finishedRun(1), nil, nil)))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "run-2.json"), []byte("{not json"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("ignored"), 0o644))
+
+	records, lastId, err := store.Load()
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, 1, records[0].TestRun.Id)
+	assert.Equal(t, 1, lastId)
+}
+
+func TestRecord_FailedRunHasNoResults(t *testing.T) {
+	testRun := finishedRun(3)
+	testRun.Status = testrun.StatusFailed
+
+	record := NewRecord(testRun, nil, errors.New("failed to build test command"))
+
+	assert.Equal(t, "failed to build test command", record.Error)
+	assert.Nil(t, record.TestExecutionResult())
+}
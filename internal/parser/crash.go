@@ -0,0 +1,79 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/adamakhtar/wing_commander/internal/testresult"
+)
+
+// Names of the result standing in for a suite that crashed before reporting.
+const (
+	SuiteCrashedGroupName    = "Test suite"
+	SuiteCrashedTestCaseName = "crashed"
+)
+
+// rubyErrorLineRegex matches the first line of an uncaught Ruby exception, e.g.
+// "test/test_helper.rb:5:in `require': cannot load such file -- foo (LoadError)"
+// or "test/user_test.rb:3: syntax error, unexpected end".
+var rubyErrorLineRegex = regexp.MustCompile("^(\\S+?\\.rb:\\d+(?::in [`'][^']*')?): (.+)$")
+
+// rubyFromLineRegex matches the remaining frames of an uncaught Ruby exception.
+var rubyFromLineRegex = regexp.MustCompile(`^\s+from (.+)$`)
+
+// NewSuiteCrashedResult builds the result shown for a test suite that crashed
+// before reporting, e.g. on a load error in test_helper.rb. reason says how the
+// crash was detected. The first uncaught Ruby exception in the command output
+// becomes its failure message and backtrace.
+func NewSuiteCrashedResult(id int, reason string, output string, opts *ParseOptions) testresult.TestResult {
+	ctx, _ := newParseContext(opts)
+
+	result := testresult.NewTestResult(SuiteCrashedGroupName, SuiteCrashedTestCaseName, testresult.StatusFail)
+	result.Id = id
+	result.Output = output
+	result.FailureDetails = reason
+
+	message, frames := parseRubyUncaughtError(output)
+	if message != "" {
+		result.FailureDetails = reason + "\n\n" + message
+	}
+	for _, frameStr := range frames {
+		ctx.appendFrame(&result.FullBacktrace, frameStr)
+	}
+
+	topFrame := firstFrameWithFile(result.FullBacktrace.AllStackFrames())
+	if topFrame != nil {
+		result.FailureFilePath = topFrame.FilePath
+		result.FailureLineNumber = topFrame.Line
+		result.TestFilePath = topFrame.FilePath
+		result.TestLineNumber = topFrame.Line
+	}
+	result.FailureCause = ctx.classifyFailure(message, topFrame)
+
+	return result
+}
+
+// parseRubyUncaughtError returns the message and frames of the first uncaught
+// Ruby exception printed to output.
+func parseRubyUncaughtError(output string) (string, []string) {
+	lines := strings.Split(output, "\n")
+	for i, line := range lines {
+		match := rubyErrorLineRegex.FindStringSubmatch(strings.TrimRight(line, "\r"))
+		if match == nil {
+			continue
+		}
+
+		frames := []string{match[1]}
+		for _, next := range lines[i+1:] {
+			from := rubyFromLineRegex.FindStringSubmatch(strings.TrimRight(next, "\r"))
+			if from == nil {
+				break
+			}
+			if len(frames) < maxBacktraceFrames {
+				frames = append(frames, from[1])
+			}
+		}
+		return match[2], frames
+	}
+	return "", nil
+}
@@ -0,0 +1,67 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/adamakhtar/wing_commander/internal/projectfs"
+	"github.com/adamakhtar/wing_commander/internal/testresult"
+	"github.com/adamakhtar/wing_commander/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSuiteCrashedResult(t *testing.T) {
+	rootPath, _ := types.NewAbsPath("/path/to/project")
+	require.NoError(t, projectfs.InitProjectFS(rootPath, ""))
+
+	tests := []struct {
+		name        string
+		output      string
+		wantDetails string
+		wantFile    types.AbsPath
+		wantLine    int
+		wantFrames  int
+	}{
+		{
+			name: "load error",
+			output: "Run options: --seed 1234\n" +
+				"/path/to/project/test/test_helper.rb:3:in `require': cannot load such file -- missing (LoadError)\n" +
+				"\tfrom /path/to/project/test/test_helper.rb:3:in `<top (required)>'\n" +
+				"\tfrom test/user_test.rb:1:in `require_relative'\n" +
+				"rake aborted!\n",
+			wantDetails: "exit 1\n\ncannot load such file -- missing (LoadError)",
+			wantFile:    "/path/to/project/test/test_helper.rb",
+			wantLine:    3,
+			wantFrames:  3,
+		},
+		{
+			name:        "syntax error",
+			output:      "test/user_test.rb:12: syntax error, unexpected `end' (SyntaxError)\n",
+			wantDetails: "exit 1\n\nsyntax error, unexpected `end' (SyntaxError)",
+			wantFile:    "/path/to/project/test/user_test.rb",
+			wantLine:    12,
+			wantFrames:  1,
+		},
+		{
+			name:        "no ruby error",
+			output:      "Segmentation fault\n",
+			wantDetails: "exit 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := NewSuiteCrashedResult(4, "exit 1", tt.output, nil)
+
+			assert.Equal(t, 4, result.Id)
+			assert.Equal(t, SuiteCrashedGroupName, result.GroupName)
+			assert.Equal(t, SuiteCrashedTestCaseName, result.TestCaseName)
+			assert.Equal(t, testresult.StatusFail, result.Status)
+			assert.Equal(t, tt.output, result.Output)
+			assert.Equal(t, tt.wantDetails, result.FailureDetails)
+			assert.Equal(t, tt.wantFile, result.TestFilePath)
+			assert.Equal(t, tt.wantLine, result.TestLineNumber)
+			assert.Len(t, result.FullBacktrace.Frames, tt.wantFrames)
+		})
+	}
+}
@@ -0,0 +1,431 @@
+package parser
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/adamakhtar/wing_commander/internal/backtrace"
+	"github.com/adamakhtar/wing_commander/internal/projectfs"
+	"github.com/adamakhtar/wing_commander/internal/testresult"
+	"github.com/adamakhtar/wing_commander/internal/types"
+)
+
+var (
+	// goErrorLineRegex matches t.Error/t.Fatal output: "    worker_test.go:12: expected 2, got 3".
+	goErrorLineRegex = regexp.MustCompile(`^\s+(\S+\.go):(\d+): ?(.*)$`)
+	// goDumpLocationRegex matches the location line of a goroutine dump frame: "\t/src/app/worker.go:42 +0x1d".
+	goDumpLocationRegex = regexp.MustCompile(`^\t(\S+\.go):(\d+)(?: \+0x[0-9a-f]+)?$`)
+	// goRecoveredSuffixRegex matches the " [recovered]" marker of a panic re-raised by the testing package.
+	goRecoveredSuffixRegex = regexp.MustCompile(`\s*\[recovered[^\]]*\]$`)
+	// goTestFuncRegex matches a top-level test function declaration.
+	goTestFuncRegex = regexp.MustCompile(`^func (Test\w*)\(`)
+)
+
+// goTestEvent is a single line of `go test -json` output (see `go doc test2json`).
+type goTestEvent struct {
+	Action     string
+	Package    string
+	ImportPath string
+	Test       string
+	Elapsed    float64
+	Output     string
+	OutputType string // "error", "error-continue" or "frame" since Go 1.25
+}
+
+// goOutputLine is a line of test output along with the OutputType of its event.
+type goOutputLine struct {
+	text       string
+	outputType string
+}
+
+// goTestCase accumulates the events of one test (or of a package when name is empty).
+type goTestCase struct {
+	pkg     string
+	name    string
+	action  string
+	elapsed float64
+	output  []goOutputLine
+}
+
+// ParseGoTestJSON folds a `go test -json` event stream into test results.
+// Subtests become results of their own ("TestX/sub_case"); lines that are not
+// JSON events (e.g. build errors on older Go versions) are ignored.
+func ParseGoTestJSON(data []byte, opts *ParseOptions) (*ParseResult, error) {
+	ctx, err := newParseContext(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var cases []*goTestCase
+	byKey := map[string]*goTestCase{}
+	lookup := func(pkg string, name string) *goTestCase {
+		key := pkg + "\x00" + name
+		if c, ok := byKey[key]; ok {
+			return c
+		}
+		c := &goTestCase{pkg: pkg, name: name}
+		byKey[key] = c
+		cases = append(cases, c)
+		return c
+	}
+
+	events := 0
+	typedOutput := false
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 || line[0] != '{' {
+			continue
+		}
+
+		var event goTestEvent
+		if err := json.Unmarshal(line, &event); err != nil {
+			continue
+		}
+		events++
+
+		pkg := event.Package
+		if pkg == "" && event.ImportPath != "" {
+			// build-output/build-fail events carry "pkg [pkg.test]" instead of Package
+			pkg = strings.Fields(event.ImportPath)[0]
+		}
+		if pkg == "" {
+			continue
+		}
+
+		c := lookup(pkg, event.Test)
+		switch event.Action {
+		case "output", "build-output":
+			c.output = append(c.output, goOutputLine{text: event.Output, outputType: event.OutputType})
+			typedOutput = typedOutput || event.OutputType != ""
+		case "pass", "fail", "skip":
+			c.action = event.Action
+			c.elapsed = event.Elapsed
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read go test output: %w", err)
+	}
+	if events == 0 && len(bytes.TrimSpace(data)) > 0 {
+		return nil, fmt.Errorf("no go test events found in output")
+	}
+
+	packages := newGoPackages()
+	failedTestInPackage := map[string]bool{}
+	failedSubtests := map[*goTestCase]bool{}
+	for _, c := range cases {
+		if c.name == "" || c.action == "pass" || c.action == "skip" {
+			continue
+		}
+		failedTestInPackage[c.pkg] = true
+		if parent, _, ok := cutLast(c.name, "/"); ok {
+			if p, found := byKey[c.pkg+"\x00"+parent]; found {
+				failedSubtests[p] = true
+			}
+		}
+	}
+
+	result := &ParseResult{
+		Summary: TestSummary{},
+	}
+
+	testID := 0
+	for _, c := range cases {
+		// A failing package without failing tests means the build, TestMain or init blew up
+		if c.name == "" && (c.action != "fail" || failedTestInPackage[c.pkg]) {
+			continue
+		}
+		testID++
+		result.addTest(convertGoTestCaseToTestResult(testID, c, failedSubtests[c], typedOutput, packages, ctx))
+	}
+
+	return result, nil
+}
+
+// convertGoTestCaseToTestResult converts the folded events of one test into a TestResult.
+func convertGoTestCaseToTestResult(id int, c *goTestCase, hasFailedSubtests bool, typedOutput bool, packages *goPackages, ctx *parseContext) testresult.TestResult {
+	var status testresult.TestStatus
+	switch c.action {
+	case "pass":
+		status = testresult.StatusPass
+	case "skip":
+		status = testresult.StatusSkip
+	default:
+		// No terminal event means the test binary died mid-test (panic, os.Exit, timeout)
+		status = testresult.StatusFail
+	}
+
+	pkgDir := packages.dir(c.pkg)
+
+	var testFilePath types.AbsPath
+	var testLineNumber int
+	if c.name != "" {
+		topLevel, _, _ := strings.Cut(c.name, "/")
+		if def, ok := packages.testDefinitions(pkgDir)[topLevel]; ok {
+			testFilePath = def.path
+			testLineNumber = def.line
+		}
+	}
+	if testFilePath == "" && pkgDir != "" {
+		testFilePath = types.AbsPath(pkgDir)
+	}
+
+	output := goTestOutput(c.output)
+
+	fullBacktrace := backtrace.NewBacktrace()
+	var failureDetails string
+	var failureFilePath types.AbsPath
+	var failureLineNumber int
+	var failureCause testresult.FailureCause
+
+	if status == testresult.StatusFail {
+		var frames []string
+		failureDetails, frames = goFailureDetails(output, typedOutput, pkgDir)
+		if failureDetails == "" && hasFailedSubtests {
+			failureDetails = "subtest failed"
+		}
+		for _, frameStr := range frames {
+			if len(fullBacktrace.Frames) >= maxBacktraceFrames {
+				break
+			}
+			ctx.appendFrame(&fullBacktrace, frameStr)
+		}
+
+		topFrame := firstFrameWithFile(fullBacktrace.AllStackFrames())
+		if topFrame != nil {
+			failureFilePath = topFrame.FilePath
+			failureLineNumber = topFrame.Line
+		}
+		failureCause = ctx.classifyFailure(failureDetails, topFrame)
+	}
+
+	return testresult.TestResult{
+		Id:                id,
+		GroupName:         c.pkg,
+		TestCaseName:      c.name,
+		Status:            status,
+		FailureCause:      failureCause,
+		FailureDetails:    failureDetails,
+		FailureFilePath:   failureFilePath,
+		FailureLineNumber: failureLineNumber,
+		TestFilePath:      testFilePath,
+		TestLineNumber:    testLineNumber,
+		FullBacktrace:     fullBacktrace,
+		FilteredBacktrace: backtrace.NewBacktrace(),
+		Duration:          c.elapsed,
+		Output:            goOutputText(output),
+	}
+}
+
+// goTestOutput drops the "=== RUN", "--- FAIL" and "PASS"/"ok" framing lines go test adds around output.
+func goTestOutput(raw []goOutputLine) []goOutputLine {
+	var lines []goOutputLine
+	for _, chunk := range raw {
+		if chunk.outputType == "frame" {
+			continue
+		}
+		for _, line := range strings.Split(strings.TrimRight(chunk.text, "\n"), "\n") {
+			trimmed := strings.TrimSpace(line)
+			switch {
+			case strings.HasPrefix(trimmed, "=== "),
+				strings.HasPrefix(trimmed, "--- PASS"),
+				strings.HasPrefix(trimmed, "--- FAIL"),
+				strings.HasPrefix(trimmed, "--- SKIP"),
+				trimmed == "PASS", trimmed == "FAIL",
+				strings.HasPrefix(trimmed, "ok  \t"), strings.HasPrefix(trimmed, "FAIL\t"):
+				continue
+			}
+			lines = append(lines, goOutputLine{text: line, outputType: chunk.outputType})
+		}
+	}
+	return lines
+}
+
+func goOutputText(lines []goOutputLine) string {
+	texts := make([]string, len(lines))
+	for i, line := range lines {
+		texts[i] = line.text
+	}
+	return strings.TrimSpace(strings.Join(texts, "\n"))
+}
+
+// goFailureDetails extracts the failure message and frame strings from a test's output.
+// Panic frames come first (innermost project frame on top), followed by t.Error locations.
+// Runtime and testing package frames are dropped as they never point at the cause.
+// With typed output t.Log lines are told apart from t.Error lines; older Go
+// versions don't tag output so every "file.go:N: msg" line counts as an error.
+func goFailureDetails(lines []goOutputLine, typedOutput bool, pkgDir string) (string, []string) {
+	var messages []string
+	var panicFrames []string
+	var errorFrames []string
+	inErrorMessage := false
+
+	for i, outputLine := range lines {
+		line := outputLine.text
+		if matches := goDumpLocationRegex.FindStringSubmatch(line); matches != nil && i > 0 {
+			inErrorMessage = false
+			function := backtrace.GoFunctionName(lines[i-1].text)
+			if isGoInternalFunction(function) {
+				continue
+			}
+			panicFrames = append(panicFrames, function+"\n\t"+matches[1]+":"+matches[2])
+			continue
+		}
+
+		isError := !typedOutput || outputLine.outputType == "error"
+		if matches := goErrorLineRegex.FindStringSubmatch(line); matches != nil && isError {
+			inErrorMessage = true
+			messages = append(messages, matches[3])
+			errorFrames = append(errorFrames, goSourcePath(matches[1], pkgDir)+":"+matches[2])
+			continue
+		}
+
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "panic: "):
+			inErrorMessage = false
+			if !strings.HasPrefix(line, "\t") { // the indented copy repeats the recovered panic
+				messages = append(messages, goRecoveredSuffixRegex.ReplaceAllString(trimmed, ""))
+			}
+		case inErrorMessage && len(messages) > 0 &&
+			(outputLine.outputType == "error-continue" || (!typedOutput && strings.HasPrefix(line, "        "))):
+			// continuation line of a multi-line t.Error message
+			messages[len(messages)-1] += "\n" + trimmed
+		default:
+			inErrorMessage = false
+		}
+	}
+
+	// The panic message is the most useful headline when a test panics
+	for i, message := range messages {
+		if strings.HasPrefix(message, "panic: ") && i > 0 {
+			messages = append([]string{message}, append(messages[:i:i], messages[i+1:]...)...)
+			break
+		}
+	}
+
+	if len(messages) == 0 {
+		// build failures and crashes have no t.Error lines, show the raw output instead
+		var raw []string
+		for _, line := range lines {
+			if trimmed := strings.TrimSpace(line.text); trimmed != "" {
+				raw = append(raw, trimmed)
+			}
+		}
+		if len(raw) > 10 {
+			raw = raw[:10]
+		}
+		messages = raw
+	}
+
+	return strings.Join(messages, "\n"), append(panicFrames, errorFrames...)
+}
+
+// cutLast slices s around the last instance of sep.
+func cutLast(s string, sep string) (before string, after string, found bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
+
+func isGoInternalFunction(function string) bool {
+	return function == "panic" ||
+		strings.HasPrefix(function, "runtime.") ||
+		strings.HasPrefix(function, "testing.") ||
+		strings.HasPrefix(function, "created by ")
+}
+
+// goSourcePath resolves a file name printed by t.Error, which is relative to
+// the package directory, against the package's directory when known.
+func goSourcePath(file string, pkgDir string) string {
+	if filepath.IsAbs(file) || pkgDir == "" {
+		return file
+	}
+	return filepath.Join(pkgDir, file)
+}
+
+type goTestDefinition struct {
+	path types.AbsPath
+	line int
+}
+
+// goPackages maps import paths to directories inside the project's main module
+// and caches where each package's Test functions are declared.
+type goPackages struct {
+	rootPath    string
+	modulePath  string
+	definitions map[string]map[string]goTestDefinition
+}
+
+func newGoPackages() *goPackages {
+	rootPath := projectfs.GetProjectFS().RootPath.String()
+	packages := &goPackages{
+		rootPath:    rootPath,
+		definitions: map[string]map[string]goTestDefinition{},
+	}
+
+	if data, err := os.ReadFile(filepath.Join(rootPath, "go.mod")); err == nil {
+		packages.modulePath = goModulePath(data)
+	}
+
+	return packages
+}
+
+// dir returns the absolute directory of pkg, or "" when it is not part of the project's module.
+func (p *goPackages) dir(pkg string) string {
+	if p.modulePath == "" {
+		return ""
+	}
+	if pkg == p.modulePath {
+		return p.rootPath
+	}
+	if rel, ok := strings.CutPrefix(pkg, p.modulePath+"/"); ok {
+		return filepath.Join(p.rootPath, filepath.FromSlash(rel))
+	}
+	return ""
+}
+
+// testDefinitions returns the Test functions declared in the _test.go files of dir.
+func (p *goPackages) testDefinitions(dir string) map[string]goTestDefinition {
+	if dir == "" {
+		return nil
+	}
+	if defs, ok := p.definitions[dir]; ok {
+		return defs
+	}
+
+	defs := map[string]goTestDefinition{}
+	files, _ := filepath.Glob(filepath.Join(dir, "*_test.go"))
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		for i, line := range strings.Split(string(data), "\n") {
+			if matches := goTestFuncRegex.FindStringSubmatch(line); matches != nil {
+				defs[matches[1]] = goTestDefinition{path: types.AbsPath(file), line: i + 1}
+			}
+		}
+	}
+
+	p.definitions[dir] = defs
+	return defs
+}
+
+// goModulePath returns the module path declared in go.mod data.
+func goModulePath(data []byte) string {
+	for _, line := range strings.Split(string(data), "\n") {
+		if rest, ok := strings.CutPrefix(strings.TrimSpace(line), "module"); ok && (rest == "" || rest[0] == ' ' || rest[0] == '\t') {
+			return strings.Trim(strings.TrimSpace(rest), `"`)
+		}
+	}
+	return ""
+}
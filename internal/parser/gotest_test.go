@@ -0,0 +1,134 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/adamakhtar/wing_commander/internal/backtrace"
+	"github.com/adamakhtar/wing_commander/internal/projectfs"
+	"github.com/adamakhtar/wing_commander/internal/testresult"
+	"github.com/adamakhtar/wing_commander/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func initGoTestProject(t *testing.T) string {
+	t.Helper()
+	rootPath, err := filepath.Abs("../../testdata/fixtures/gotest_project")
+	require.NoError(t, err)
+	require.NoError(t, projectfs.InitProjectFS(types.AbsPath(rootPath), ""))
+	return rootPath
+}
+
+func goTestOptions() *ParseOptions {
+	return &ParseOptions{FrameParser: backtrace.ParseGoStackFrame}
+}
+
+func TestParseGoTestJSON(t *testing.T) {
+	rootPath := initGoTestProject(t)
+	data, err := os.ReadFile("../../testdata/fixtures/gotest_output.jsonl")
+	require.NoError(t, err)
+	data = []byte(strings.ReplaceAll(string(data), "{{ROOT}}", rootPath))
+
+	result, err := ParseGoTestJSON(data, goTestOptions())
+	require.NoError(t, err)
+	require.Len(t, result.Tests, 6)
+	assert.Equal(t, TestSummary{Total: 6, Passed: 2, Failed: 3, Skipped: 1}, result.Summary)
+
+	testFile := types.AbsPath(filepath.Join(rootPath, "worker/worker_test.go"))
+
+	passed := result.Tests[0]
+	assert.Equal(t, "example.com/shop/worker", passed.GroupName)
+	assert.Equal(t, "TestDiscount", passed.TestCaseName)
+	assert.Equal(t, testresult.StatusPass, passed.Status)
+	assert.Equal(t, testFile, passed.TestFilePath)
+	assert.Equal(t, 5, passed.TestLineNumber)
+
+	parent := result.Tests[1]
+	assert.Equal(t, "TestDiscountTable", parent.TestCaseName)
+	assert.Equal(t, testresult.StatusFail, parent.Status)
+	assert.Equal(t, "subtest failed", parent.FailureDetails)
+
+	subtest := result.Tests[3]
+	assert.Equal(t, "TestDiscountTable/odd_price", subtest.TestCaseName)
+	assert.Equal(t, testresult.StatusFail, subtest.Status)
+	assert.Equal(t, testFile, subtest.TestFilePath)
+	assert.Equal(t, 11, subtest.TestLineNumber)
+	assert.Equal(t, "Discount(15) = 14\nwant 13", subtest.FailureDetails)
+	assert.Equal(t, testFile, subtest.FailureFilePath)
+	assert.Equal(t, 23, subtest.FailureLineNumber)
+
+	assert.Equal(t, testresult.StatusSkip, result.Tests[4].Status)
+
+	panicked := result.Tests[5]
+	assert.Equal(t, "TestTotal", panicked.TestCaseName)
+	assert.Equal(t, testresult.StatusFail, panicked.Status)
+	assert.Equal(t, "panic: runtime error: index out of range [2] with length 2", panicked.FailureDetails)
+	require.Len(t, panicked.FullBacktrace.Frames, 2)
+	assert.Equal(t, types.AbsPath(filepath.Join(rootPath, "worker/worker.go")), panicked.FailureFilePath)
+	assert.Equal(t, 7, panicked.FailureLineNumber)
+	assert.Equal(t, "example.com/shop/worker.Total", panicked.FullBacktrace.Frames[0].Function)
+	assert.Equal(t, testFile, panicked.FullBacktrace.Frames[1].FilePath)
+	assert.Equal(t, 35, panicked.FullBacktrace.Frames[1].Line)
+	assert.Equal(t, testresult.FailureCauseProductionCode, panicked.FailureCause)
+	assert.Contains(t, panicked.Output, "summing")
+	assert.Contains(t, panicked.Output, "goroutine 11 [running]:")
+}
+
+func TestParseGoTestJSON_UntypedOutput(t *testing.T) {
+	rootPath := initGoTestProject(t)
+
+	// Go versions before 1.25 don't set OutputType
+	data := `{"Action":"run","Package":"example.com/shop/worker","Test":"TestDiscount"}
+{"Action":"output","Package":"example.com/shop/worker","Test":"TestDiscount","Output":"=== RUN   TestDiscount\n"}
+{"Action":"output","Package":"example.com/shop/worker","Test":"TestDiscount","Output":"    worker_test.go:7: expected 90, got 89\n"}
+{"Action":"output","Package":"example.com/shop/worker","Test":"TestDiscount","Output":"--- FAIL: TestDiscount (0.01s)\n"}
+{"Action":"fail","Package":"example.com/shop/worker","Test":"TestDiscount","Elapsed":0.01}
+{"Action":"fail","Package":"example.com/shop/worker","Elapsed":0.02}
+`
+
+	result, err := ParseGoTestJSON([]byte(data), goTestOptions())
+	require.NoError(t, err)
+	require.Len(t, result.Tests, 1)
+
+	failed := result.Tests[0]
+	assert.Equal(t, "expected 90, got 89", failed.FailureDetails)
+	assert.Equal(t, types.AbsPath(filepath.Join(rootPath, "worker/worker_test.go")), failed.FailureFilePath)
+	assert.Equal(t, 7, failed.FailureLineNumber)
+	assert.Equal(t, 0.01, failed.Duration)
+	assert.Equal(t, "worker_test.go:7: expected 90, got 89", failed.Output)
+}
+
+func TestParseGoTestJSON_PackageFailureWithoutTests(t *testing.T) {
+	rootPath := initGoTestProject(t)
+
+	data := `{"ImportPath":"example.com/shop/worker [example.com/shop/worker.test]","Action":"build-output","Output":"# example.com/shop/worker\n"}
+{"ImportPath":"example.com/shop/worker [example.com/shop/worker.test]","Action":"build-output","Output":"worker/worker.go:7:3: undefined: totl\n"}
+{"ImportPath":"example.com/shop/worker [example.com/shop/worker.test]","Action":"build-fail"}
+{"Action":"start","Package":"example.com/shop/worker"}
+{"Action":"output","Package":"example.com/shop/worker","Output":"FAIL\texample.com/shop/worker [build failed]\n"}
+{"Action":"fail","Package":"example.com/shop/worker","Elapsed":0}
+`
+
+	result, err := ParseGoTestJSON([]byte(data), goTestOptions())
+	require.NoError(t, err)
+	require.Len(t, result.Tests, 1)
+
+	failed := result.Tests[0]
+	assert.Equal(t, "example.com/shop/worker", failed.GroupName)
+	assert.Empty(t, failed.TestCaseName)
+	assert.Equal(t, testresult.StatusFail, failed.Status)
+	assert.Equal(t, "# example.com/shop/worker\nworker/worker.go:7:3: undefined: totl", failed.FailureDetails)
+	assert.Equal(t, types.AbsPath(filepath.Join(rootPath, "worker")), failed.TestFilePath)
+}
+
+func TestParseGoTestJSON_NoEvents(t *testing.T) {
+	initGoTestProject(t)
+
+	_, err := ParseGoTestJSON([]byte("go: cannot find main module\n"), goTestOptions())
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "no go test events found")
+}
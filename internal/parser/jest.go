@@ -0,0 +1,230 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/adamakhtar/wing_commander/internal/backtrace"
+	"github.com/adamakhtar/wing_commander/internal/testresult"
+	"github.com/adamakhtar/wing_commander/internal/types"
+)
+
+var (
+	// jsFrameLineRegex matches the V8 frame lines of a failure message: "    at fn (/abs/file.js:10:5)".
+	jsFrameLineRegex = regexp.MustCompile(`^\s+at \S`)
+	// jsFrameLocationRegex matches frames that carry a file position, unlike "at new Promise (<anonymous>)".
+	jsFrameLocationRegex = regexp.MustCompile(`:\d+:\d+\)?$`)
+	// ansiEscapeRegex matches the color codes Jest leaves in failure messages.
+	ansiEscapeRegex = regexp.MustCompile("\x1b\\[[0-9;]*m")
+)
+
+// jestReport is the JSON written by `jest --json --outputFile` and Vitest's
+// json reporter, which uses the same shape.
+type jestReport struct {
+	TestResults []jestTestFileResult `json:"testResults"`
+}
+
+type jestTestFileResult struct {
+	Name             string                `json:"name"`
+	Status           string                `json:"status"`
+	Message          string                `json:"message"`
+	AssertionResults []jestAssertionResult `json:"assertionResults"`
+}
+
+type jestAssertionResult struct {
+	AncestorTitles  []string `json:"ancestorTitles"`
+	Title           string   `json:"title"`
+	Status          string   `json:"status"`
+	Duration        *float64 `json:"duration"` // milliseconds, null for skipped tests
+	FailureMessages []string `json:"failureMessages"`
+	Location        *struct {
+		Line   int `json:"line"`
+		Column int `json:"column"`
+	} `json:"location"` // only with --testLocationInResults (Jest) / includeTaskLocation (Vitest)
+}
+
+// ParseJestFile parses a Jest or Vitest JSON report file.
+func ParseJestFile(filePath string, opts *ParseOptions) (*ParseResult, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s: %w", filePath, err)
+	}
+
+	return ParseJest(data, opts)
+}
+
+// ParseJest parses Jest/Vitest JSON report data. Each assertion result becomes a
+// test result grouped by its ancestor titles ("Cart total"). A test file that
+// failed to run becomes a single failed result without a test case name.
+func ParseJest(data []byte, opts *ParseOptions) (*ParseResult, error) {
+	ctx, err := newParseContext(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var report jestReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse jest JSON report: %w", err)
+	}
+
+	result := &ParseResult{
+		Summary: TestSummary{},
+	}
+
+	testID := 0
+	for _, file := range report.TestResults {
+		if file.Status == "failed" && len(file.AssertionResults) == 0 {
+			testID++
+			result.addTest(convertJestSuiteFailureToTestResult(testID, file, ctx))
+			continue
+		}
+		for _, assertion := range file.AssertionResults {
+			testID++
+			result.addTest(convertJestAssertionToTestResult(testID, file, assertion, ctx))
+		}
+	}
+
+	return result, nil
+}
+
+// convertJestAssertionToTestResult converts a Jest assertion result into a TestResult.
+func convertJestAssertionToTestResult(id int, file jestTestFileResult, assertion jestAssertionResult, ctx *parseContext) testresult.TestResult {
+	var status testresult.TestStatus
+	switch assertion.Status {
+	case "passed":
+		status = testresult.StatusPass
+	case "failed":
+		status = testresult.StatusFail
+	default:
+		// pending, skipped, todo and disabled
+		status = testresult.StatusSkip
+	}
+
+	var testFilePath types.AbsPath
+	if abs, err := parseFilePath(file.Name); err == nil {
+		testFilePath = abs
+	}
+
+	var testLineNumber int
+	if assertion.Location != nil {
+		testLineNumber = assertion.Location.Line
+	}
+
+	var duration float64
+	if assertion.Duration != nil {
+		duration = *assertion.Duration / 1000
+	}
+
+	fullBacktrace := backtrace.NewBacktrace()
+	var failureDetails string
+	var failureFilePath types.AbsPath
+	var failureLineNumber int
+	var failureCause testresult.FailureCause
+
+	if status == testresult.StatusFail {
+		var frames []string
+		failureDetails, frames = jestFailureDetails(assertion.FailureMessages)
+		for _, frameStr := range frames {
+			if len(fullBacktrace.Frames) >= maxBacktraceFrames {
+				break
+			}
+			ctx.appendFrame(&fullBacktrace, frameStr)
+		}
+
+		topFrame := firstFrameWithFile(fullBacktrace.AllStackFrames())
+		if topFrame != nil {
+			failureFilePath = topFrame.FilePath
+			failureLineNumber = topFrame.Line
+		}
+		failureCause = ctx.classifyFailure(failureDetails, topFrame)
+
+		if testLineNumber == 0 {
+			// without locations in the report, the deepest frame in the test file is the best guess
+			for _, frame := range fullBacktrace.Frames {
+				if frame.FilePath == testFilePath {
+					testLineNumber = frame.Line
+				}
+			}
+		}
+	}
+
+	return testresult.TestResult{
+		Id:                id,
+		GroupName:         strings.Join(assertion.AncestorTitles, " "),
+		TestCaseName:      assertion.Title,
+		Status:            status,
+		FailureCause:      failureCause,
+		FailureDetails:    failureDetails,
+		FailureFilePath:   failureFilePath,
+		FailureLineNumber: failureLineNumber,
+		TestFilePath:      testFilePath,
+		TestLineNumber:    testLineNumber,
+		FullBacktrace:     fullBacktrace,
+		FilteredBacktrace: backtrace.NewBacktrace(),
+		Duration:          duration,
+	}
+}
+
+// convertJestSuiteFailureToTestResult converts a test file that failed to run
+// (syntax error, failing import) into a failed TestResult.
+func convertJestSuiteFailureToTestResult(id int, file jestTestFileResult, ctx *parseContext) testresult.TestResult {
+	testFilePath, _ := parseFilePath(file.Name)
+
+	failureDetails, frames := jestFailureDetails([]string{file.Message})
+	fullBacktrace := backtrace.NewBacktrace()
+	for _, frameStr := range frames {
+		if len(fullBacktrace.Frames) >= maxBacktraceFrames {
+			break
+		}
+		ctx.appendFrame(&fullBacktrace, frameStr)
+	}
+
+	var failureFilePath types.AbsPath
+	var failureLineNumber int
+	topFrame := firstFrameWithFile(fullBacktrace.AllStackFrames())
+	if topFrame != nil {
+		failureFilePath = topFrame.FilePath
+		failureLineNumber = topFrame.Line
+	}
+
+	return testresult.TestResult{
+		Id:                id,
+		GroupName:         file.Name,
+		Status:            testresult.StatusFail,
+		FailureCause:      ctx.classifyFailure(failureDetails, topFrame),
+		FailureDetails:    failureDetails,
+		FailureFilePath:   failureFilePath,
+		FailureLineNumber: failureLineNumber,
+		TestFilePath:      testFilePath,
+		FullBacktrace:     fullBacktrace,
+		FilteredBacktrace: backtrace.NewBacktrace(),
+	}
+}
+
+// jestFailureDetails splits failure messages into the message text and the
+// V8 frames of the first message. Node internals, node_modules and frames
+// without a file position are dropped as they never point at the cause.
+func jestFailureDetails(failureMessages []string) (string, []string) {
+	var messages []string
+	var frames []string
+	for i, failureMessage := range failureMessages {
+		var lines []string
+		for _, line := range strings.Split(ansiEscapeRegex.ReplaceAllString(failureMessage, ""), "\n") {
+			if !jsFrameLineRegex.MatchString(line) {
+				lines = append(lines, line)
+				continue
+			}
+			if i > 0 || !jsFrameLocationRegex.MatchString(line) || strings.Contains(line, "node:") || strings.Contains(line, "node_modules/") {
+				continue
+			}
+			frames = append(frames, strings.TrimSpace(line))
+		}
+		if message := strings.TrimSpace(strings.Join(lines, "\n")); message != "" {
+			messages = append(messages, strings.TrimSpace(strings.TrimPrefix(message, "● Test suite failed to run")))
+		}
+	}
+	return strings.Join(messages, "\n\n"), frames
+}
@@ -0,0 +1,75 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/adamakhtar/wing_commander/internal/backtrace"
+	"github.com/adamakhtar/wing_commander/internal/projectfs"
+	"github.com/adamakhtar/wing_commander/internal/testresult"
+	"github.com/adamakhtar/wing_commander/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseJest(t *testing.T) {
+	rootPath, err := filepath.Abs("../../testdata/fixtures/jest_project")
+	require.NoError(t, err)
+	require.NoError(t, projectfs.InitProjectFS(types.AbsPath(rootPath), ""))
+
+	data, err := os.ReadFile("../../testdata/fixtures/jest_report.json")
+	require.NoError(t, err)
+	data = []byte(strings.ReplaceAll(string(data), "{{ROOT}}", rootPath))
+
+	result, err := ParseJest(data, &ParseOptions{FrameParser: backtrace.ParseJSStackFrame})
+	require.NoError(t, err)
+	require.Len(t, result.Tests, 5)
+	assert.Equal(t, TestSummary{Total: 5, Passed: 1, Failed: 3, Skipped: 1}, result.Summary)
+
+	testFile := types.AbsPath(filepath.Join(rootPath, "src/cart.test.js"))
+
+	passed := result.Tests[0]
+	assert.Equal(t, "Cart discount", passed.GroupName)
+	assert.Equal(t, "takes ten percent off", passed.TestCaseName)
+	assert.Equal(t, testresult.StatusPass, passed.Status)
+	assert.Equal(t, testFile, passed.TestFilePath)
+	assert.InDelta(t, 0.002, passed.Duration, 0.00001)
+
+	assertion := result.Tests[1]
+	assert.Equal(t, testresult.StatusFail, assertion.Status)
+	assert.Equal(t, "Error: expect(received).toBe(expected) // Object.is equality\n\nExpected: 85\nReceived: 90", assertion.FailureDetails)
+	assert.Equal(t, 9, assertion.TestLineNumber)
+	require.Len(t, assertion.FullBacktrace.Frames, 1, "node internals and node_modules frames are dropped")
+	assert.Equal(t, testFile, assertion.FailureFilePath)
+	assert.Equal(t, 10, assertion.FailureLineNumber)
+	assert.Equal(t, 29, assertion.FullBacktrace.Frames[0].Column)
+
+	crashed := result.Tests[2]
+	assert.Equal(t, "Cart", crashed.GroupName)
+	assert.Equal(t, "TypeError: Cannot read properties of null (reading 'reduce')", crashed.FailureDetails)
+	require.Len(t, crashed.FullBacktrace.Frames, 2)
+	assert.Equal(t, types.AbsPath(filepath.Join(rootPath, "src/cart.js")), crashed.FailureFilePath)
+	assert.Equal(t, 6, crashed.FailureLineNumber)
+	assert.Equal(t, 15, crashed.TestLineNumber, "falls back to the test file frame without a location")
+	assert.Equal(t, testresult.FailureCauseProductionCode, crashed.FailureCause)
+
+	skipped := result.Tests[3]
+	assert.Equal(t, testresult.StatusSkip, skipped.Status)
+	assert.Zero(t, skipped.Duration)
+
+	suiteFailure := result.Tests[4]
+	assert.Equal(t, testresult.StatusFail, suiteFailure.Status)
+	assert.Empty(t, suiteFailure.TestCaseName)
+	assert.Equal(t, types.AbsPath(filepath.Join(rootPath, "src/broken.test.js")), suiteFailure.TestFilePath)
+	assert.True(t, strings.HasPrefix(suiteFailure.FailureDetails, "Cannot find module './payments' from 'src/broken.test.js'"))
+	assert.Equal(t, 1, suiteFailure.FailureLineNumber)
+}
+
+func TestParseJest_InvalidJSON(t *testing.T) {
+	_, err := ParseJest([]byte("not json"), nil)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to parse jest JSON report")
+}
@@ -0,0 +1,206 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/adamakhtar/wing_commander/internal/backtrace"
+	"github.com/adamakhtar/wing_commander/internal/testresult"
+	"github.com/adamakhtar/wing_commander/internal/types"
+	"github.com/joshdk/go-junit"
+)
+
+// junitFrameRegex matches backtrace-looking lines inside a failure body, e.g.
+// "app/models/user.rb:42:in `save'", "# ./spec/user_spec.rb:10:in ..." (RSpec)
+// or "from lib/thing.rb:3:in ..." (Ruby load errors).
+var junitFrameRegex = regexp.MustCompile(`^(?:#\s*|from\s+)?(\S+\.\w+:\d+(?::.*)?)$`)
+
+// junitPythonFrameRegex matches Python traceback lines, e.g. `File "shop/cart.py", line 8, in total`.
+var junitPythonFrameRegex = regexp.MustCompile(`^File "[^"]+", line \d+`)
+
+// ParseJUnitFile parses a JUnit XML report file.
+func ParseJUnitFile(filePath string, opts *ParseOptions) (*ParseResult, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s: %w", filePath, err)
+	}
+
+	return ParseJUnit(data, opts)
+}
+
+// ParseJUnit parses JUnit XML report data (as produced by rspec_junit_formatter,
+// pytest, Gradle, etc.) into the same results as Parse.
+func ParseJUnit(data []byte, opts *ParseOptions) (*ParseResult, error) {
+	ctx, err := newParseContext(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseJUnit(data, ctx)
+}
+
+func parseJUnit(data []byte, ctx *parseContext) (*ParseResult, error) {
+	suites, err := junit.Ingest(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse JUnit XML: %w", err)
+	}
+
+	result := &ParseResult{
+		Summary: TestSummary{},
+	}
+
+	testID := 0
+	var walk func(suite junit.Suite)
+	walk = func(suite junit.Suite) {
+		for _, test := range suite.Tests {
+			testID++
+			result.addTest(convertJUnitTestToTestResult(testID, suite, test, ctx))
+		}
+		for _, nested := range suite.Suites {
+			walk(nested)
+		}
+	}
+
+	for _, suite := range suites {
+		walk(suite)
+	}
+
+	return result, nil
+}
+
+// convertJUnitTestToTestResult converts a JUnit testcase into a TestResult.
+func convertJUnitTestToTestResult(id int, suite junit.Suite, test junit.Test, ctx *parseContext) testresult.TestResult {
+	groupName := test.Classname
+	if groupName == "" {
+		groupName = suite.Name
+	}
+
+	var status testresult.TestStatus
+	switch test.Status {
+	case junit.StatusPassed:
+		status = testresult.StatusPass
+	case junit.StatusSkipped:
+		status = testresult.StatusSkip
+	default:
+		status = testresult.StatusFail
+	}
+
+	// rspec_junit_formatter and pytest put file/line on the testcase, some tools only on the suite
+	testFilePath := test.Properties["file"]
+	if testFilePath == "" {
+		testFilePath = suite.Properties["file"]
+	}
+	testLineNumber, _ := strconv.Atoi(test.Properties["line"])
+
+	var testFilePathAbs types.AbsPath
+	if testFilePath != "" {
+		if abs, err := parseFilePath(testFilePath); err == nil {
+			testFilePathAbs = abs
+		}
+	}
+
+	fullBacktrace := backtrace.NewBacktrace()
+	var failureDetails string
+	var failureFilePath types.AbsPath
+	var failureLineNumber int
+	var failureCause testresult.FailureCause
+
+	if status == testresult.StatusFail {
+		junitErr, _ := test.Error.(junit.Error)
+		failureDetails = junitFailureDetails(test, junitErr)
+
+		frameStrs := extractJUnitFrames(junitErr.Body)
+		if ctx.outermostFrameFirst {
+			slices.Reverse(frameStrs)
+		}
+		for _, frameStr := range frameStrs {
+			if len(fullBacktrace.Frames) >= maxBacktraceFrames {
+				break
+			}
+			ctx.appendFrame(&fullBacktrace, frameStr)
+		}
+
+		topFrame := firstFrameWithFile(fullBacktrace.AllStackFrames())
+		if topFrame != nil {
+			failureFilePath = topFrame.FilePath
+			failureLineNumber = topFrame.Line
+		}
+		failureCause = ctx.classifyFailure(failureDetails, topFrame)
+	}
+
+	return testresult.TestResult{
+		Id:                id,
+		GroupName:         groupName,
+		TestCaseName:      test.Name,
+		Status:            status,
+		FailureCause:      failureCause,
+		FailureDetails:    failureDetails,
+		FailureFilePath:   failureFilePath,
+		FailureLineNumber: failureLineNumber,
+		TestFilePath:      testFilePathAbs,
+		TestLineNumber:    testLineNumber,
+		FullBacktrace:     fullBacktrace,
+		FilteredBacktrace: backtrace.NewBacktrace(),
+		Duration:          test.Duration.Seconds(),
+		Output:            joinOutput(test.SystemOut, test.SystemErr),
+	}
+}
+
+// junitFailureDetails builds the failure message from the failure/error element.
+// Falls back to the first non-frame line of the body when no message attribute is set.
+// Errors are prefixed with their exception type, mirroring Ruby's "NameError: ..." output.
+func junitFailureDetails(test junit.Test, junitErr junit.Error) string {
+	message := strings.TrimSpace(test.Message)
+	if message == "" {
+		message = strings.TrimSpace(junitErr.Message)
+	}
+	if message == "" {
+		for _, line := range strings.Split(junitErr.Body, "\n") {
+			line = strings.TrimSpace(line)
+			if line != "" && !junitFrameRegex.MatchString(line) {
+				message = line
+				break
+			}
+		}
+	}
+
+	if test.Status == junit.StatusError && junitErr.Type != "" && !strings.HasPrefix(message, junitErr.Type) {
+		if message == "" {
+			return junitErr.Type
+		}
+		return junitErr.Type + ": " + message
+	}
+
+	return message
+}
+
+// extractJUnitFrames returns the backtrace frame strings found in a failure body.
+func extractJUnitFrames(body string) []string {
+	var frames []string
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimSpace(line)
+		if matches := junitFrameRegex.FindStringSubmatch(line); matches != nil {
+			frames = append(frames, matches[1])
+		} else if junitPythonFrameRegex.MatchString(line) {
+			frames = append(frames, line)
+		}
+	}
+	return frames
+}
+
+func joinOutput(stdout string, stderr string) string {
+	stdout = strings.TrimSpace(stdout)
+	stderr = strings.TrimSpace(stderr)
+	switch {
+	case stdout == "":
+		return stderr
+	case stderr == "":
+		return stdout
+	default:
+		return stdout + "\n" + stderr
+	}
+}
@@ -0,0 +1,122 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/adamakhtar/wing_commander/internal/projectfs"
+	"github.com/adamakhtar/wing_commander/internal/testresult"
+	"github.com/adamakhtar/wing_commander/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseJUnit_StatusesAndNames(t *testing.T) {
+	rootPath, _ := types.NewAbsPath("/path/to/project")
+	require.NoError(t, projectfs.InitProjectFS(rootPath, ""))
+
+	xmlData := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites>
+  <testsuite name="Minitest" tests="3" failures="1" skipped="1" time="0.3">
+    <testcase classname="TestClass" name="test_pass" time="0.1">
+    </testcase>
+    <testcase classname="TestClass" name="test_fail" time="0.2">
+      <failure message="Expected true to be false">
+        test/test_class.rb:10:in 'test_fail'
+      </failure>
+    </testcase>
+    <testcase classname="TestClass" name="test_skip" time="0">
+      <skipped/>
+    </testcase>
+  </testsuite>
+</testsuites>`
+
+	result, err := ParseJUnit([]byte(xmlData), nil)
+	require.NoError(t, err)
+	require.Len(t, result.Tests, 3)
+
+	assert.Equal(t, 1, result.Tests[0].Id)
+	assert.Equal(t, "TestClass", result.Tests[0].GroupName)
+	assert.Equal(t, "test_pass", result.Tests[0].TestCaseName)
+	assert.Equal(t, testresult.StatusPass, result.Tests[0].Status)
+	assert.Equal(t, 0.1, result.Tests[0].Duration)
+
+	failed := result.Tests[1]
+	assert.Equal(t, "test_fail", failed.TestCaseName)
+	assert.Equal(t, testresult.StatusFail, failed.Status)
+	assert.Equal(t, "Expected true to be false", failed.FailureDetails)
+	assert.Equal(t, testresult.FailureCauseAssertion, failed.FailureCause)
+	require.Len(t, failed.FullBacktrace.Frames, 1)
+	assert.Equal(t, types.AbsPath("/path/to/project/test/test_class.rb"), failed.FailureFilePath)
+	assert.Equal(t, 10, failed.FailureLineNumber)
+
+	assert.Equal(t, testresult.StatusSkip, result.Tests[2].Status)
+
+	assert.Equal(t, TestSummary{Total: 3, Passed: 1, Failed: 1, Skipped: 1}, result.Summary)
+}
+
+func TestParseJUnit_InvalidXML(t *testing.T) {
+	_, err := ParseJUnit([]byte(`<?xml version="1.0"?><invalid>xml`), nil)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to parse JUnit XML")
+}
+
+func TestParseJUnitFile(t *testing.T) {
+	rootPath, _ := types.NewAbsPath("/path/to/project")
+	require.NoError(t, projectfs.InitProjectFS(rootPath, ""))
+
+	result, err := ParseJUnitFile("../../testdata/fixtures/junit_report.xml", nil)
+	require.NoError(t, err)
+	require.Len(t, result.Tests, 4)
+	assert.Equal(t, TestSummary{Total: 4, Passed: 1, Failed: 2, Skipped: 1}, result.Summary)
+
+	passed := result.Tests[0]
+	assert.Equal(t, "spec.models.user_spec", passed.GroupName)
+	assert.Equal(t, "User is valid", passed.TestCaseName)
+	assert.Equal(t, types.AbsPath("/path/to/project/spec/models/user_spec.rb"), passed.TestFilePath)
+	assert.Equal(t, 5, passed.TestLineNumber)
+
+	assertion := result.Tests[1]
+	assert.Equal(t, testresult.StatusFail, assertion.Status)
+	assert.Equal(t, testresult.FailureCauseAssertion, assertion.FailureCause)
+	assert.Equal(t, "expected: \"Bob\"\n     got: nil", assertion.FailureDetails)
+	assert.Equal(t, types.AbsPath("/path/to/project/spec/models/user_spec.rb"), assertion.FailureFilePath)
+	assert.Equal(t, 10, assertion.FailureLineNumber)
+	assert.Equal(t, "creating user", assertion.Output)
+
+	errored := result.Tests[2]
+	assert.Equal(t, testresult.StatusFail, errored.Status)
+	assert.Equal(t, "RuntimeError: boom", errored.FailureDetails)
+	assert.Equal(t, testresult.FailureCauseProductionCode, errored.FailureCause)
+	require.Len(t, errored.FullBacktrace.Frames, 2)
+	assert.Equal(t, types.AbsPath("/path/to/project/app/models/user.rb"), errored.FullBacktrace.Frames[0].FilePath)
+	assert.Equal(t, 22, errored.FullBacktrace.Frames[0].Line)
+	assert.Equal(t, "save", errored.FullBacktrace.Frames[0].Function)
+	assert.Equal(t, "warning: deprecated", errored.Output)
+
+	skipped := result.Tests[3]
+	assert.Equal(t, testresult.StatusSkip, skipped.Status)
+	assert.Empty(t, skipped.FailureDetails)
+}
+
+func TestParseJUnit_FailureWithoutMessageUsesBody(t *testing.T) {
+	rootPath, _ := types.NewAbsPath("/path/to/project")
+	require.NoError(t, projectfs.InitProjectFS(rootPath, ""))
+
+	xmlData := `<testsuite name="suite">
+  <testcase classname="WorkerTest" name="test_boom" file="test/worker_test.rb" line="3">
+    <error type="NameError">undefined local variable or method 'x'
+    test/worker_test.rb:4:in 'test_boom'</error>
+  </testcase>
+</testsuite>`
+
+	result, err := ParseJUnit([]byte(xmlData), nil)
+	require.NoError(t, err)
+	require.Len(t, result.Tests, 1)
+
+	test := result.Tests[0]
+	assert.Equal(t, "NameError: undefined local variable or method 'x'", test.FailureDetails)
+	assert.Equal(t, testresult.FailureCauseTestDefinition, test.FailureCause)
+	assert.Equal(t, 4, test.FailureLineNumber)
+	assert.Equal(t, 3, test.TestLineNumber)
+}
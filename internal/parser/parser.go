@@ -22,6 +22,9 @@ var testPathIndicators = []string{
 	"_spec.rb",
 }
 
+// maxBacktraceFrames caps the number of frames kept per test result.
+const maxBacktraceFrames = 50
+
 // frameworkPathIndicators lists substrings that indicate frames from test frameworks/runners.
 var frameworkPathIndicators = []string{
 	"/rspec/",
@@ -31,15 +34,46 @@ var frameworkPathIndicators = []string{
 	"/gems/",
 }
 
+// ClassifyFunc decides the FailureCause of a failed test from its message and top frame.
+type ClassifyFunc func(message string, topFrame *types.StackFrame) testresult.FailureCause
+
 // ParseOptions controls optional parsing behaviour.
 type ParseOptions struct {
+	// FrameParser parses backtrace lines. Defaults to backtrace.ParseStackFrame (Ruby frames).
+	FrameParser backtrace.FrameParser
+	// ClassifyFailure decides failure causes. Defaults to ClassifyFailure.
+	ClassifyFailure ClassifyFunc
 }
 
 type parseContext struct {
+	frameParser     backtrace.FrameParser
+	classifyFailure ClassifyFunc
+	// outermostFrameFirst is set for reports whose tracebacks end with the failure
+	// origin (Python), so their frames are reversed to put the origin on top.
+	outermostFrameFirst bool
 }
 
 func newParseContext(opts *ParseOptions) (*parseContext, error) {
-	return &parseContext{}, nil
+	ctx := &parseContext{
+		frameParser:     backtrace.ParseStackFrame,
+		classifyFailure: ClassifyFailure,
+	}
+	if opts == nil {
+		return ctx, nil
+	}
+
+	if opts.FrameParser != nil {
+		ctx.frameParser = opts.FrameParser
+	}
+	if opts.ClassifyFailure != nil {
+		ctx.classifyFailure = opts.ClassifyFailure
+	}
+	return ctx, nil
+}
+
+// appendFrame parses frameStr with the configured frame parser and adds it to bt.
+func (ctx *parseContext) appendFrame(bt *backtrace.Backtrace, frameStr string) {
+	bt.AppendFrame(ctx.frameParser(frameStr))
 }
 
 // ParseResult contains parsed test results and metadata.
@@ -85,23 +119,27 @@ func Parse(data []byte, opts *ParseOptions) (*ParseResult, error) {
 	testID := 0
 	for _, testMap := range tests {
 		testID++
-		testResult := convertMapToTestResult(testID, testMap, ctx)
-		result.Tests = append(result.Tests, testResult)
-
-		result.Summary.Total++
-		switch testResult.Status {
-		case testresult.StatusPass:
-			result.Summary.Passed++
-		case testresult.StatusFail:
-			result.Summary.Failed++
-		case testresult.StatusSkip:
-			result.Summary.Skipped++
-		}
+		result.addTest(convertMapToTestResult(testID, testMap, ctx))
 	}
 
 	return result, nil
 }
 
+// addTest appends a test result and updates the summary counts.
+func (r *ParseResult) addTest(testResult testresult.TestResult) {
+	r.Tests = append(r.Tests, testResult)
+
+	r.Summary.Total++
+	switch testResult.Status {
+	case testresult.StatusPass:
+		r.Summary.Passed++
+	case testresult.StatusFail:
+		r.Summary.Failed++
+	case testresult.StatusSkip:
+		r.Summary.Skipped++
+	}
+}
+
 func firstFrameWithFile(frames []types.StackFrame) *types.StackFrame {
 	for _, frame := range frames {
 		if frame.FilePath.String() != "" {
@@ -112,6 +150,11 @@ func firstFrameWithFile(frames []types.StackFrame) *types.StackFrame {
 	return nil
 }
 
+// ClassifyFailure decides the FailureCause using the default heuristics shared by all frameworks.
+func ClassifyFailure(message string, topFrame *types.StackFrame) testresult.FailureCause {
+	return classifyFailure(message, topFrame, &parseContext{})
+}
+
 // classifyFailure decides the FailureCause from parsed failure fields using simple heuristics.
 // Inputs: error message and top stack frame (project/app and test paths if present)
 // 1) Assertion-like messages -> AssertionFailure
@@ -173,6 +216,9 @@ func extractInt(m map[string]interface{}, key string) int {
 		return v
 	case int64:
 		return int(v)
+	case float64:
+		// JSON numbers
+		return int(v)
 	case string:
 		if parsed, err := strconv.Atoi(v); err == nil {
 			return parsed
@@ -203,7 +249,7 @@ func extractStringSlice(m map[string]interface{}, key string) []string {
 }
 
 // parseFilePath converts a file path string to AbsPath.
-// If the path is absolute, it uses NewAbsPath directly.
+// If the path is absolute, it is rewritten by ProjectFS path mappings.
 // If the path is relative, it assumes it's relative to ProjectFS root and converts it using ProjectFS.Abs().
 func parseFilePath(path string) (types.AbsPath, error) {
 	if path == "" {
@@ -211,13 +257,13 @@ func parseFilePath(path string) (types.AbsPath, error) {
 	}
 
 	cleaned := filepath.Clean(path)
+	fs := projectfs.GetProjectFS()
 
 	if filepath.IsAbs(cleaned) {
-		return types.NewAbsPath(cleaned)
+		return types.NewAbsPath(fs.MapPath(cleaned))
 	}
 
 	// Relative path - convert using ProjectFS
-	fs := projectfs.GetProjectFS()
 	relPath, err := types.NewRelPath(cleaned)
 	if err != nil {
 		return types.AbsPath(""), fmt.Errorf("failed to create RelPath: %w", err)
@@ -229,6 +275,7 @@ func parseFilePath(path string) (types.AbsPath, error) {
 // convertMapToTestResult converts a summary map into a TestResult.
 func convertMapToTestResult(id int, summary map[string]interface{}, ctx *parseContext) testresult.TestResult {
 	// Extract basic fields
+	testId := extractString(summary, "test_id")
 	groupName := extractString(summary, "test_group_name")
 	testCaseName := extractString(summary, "test_case_name")
 	testFilePath := extractString(summary, "test_file_path")
@@ -268,13 +315,12 @@ func convertMapToTestResult(id int, summary map[string]interface{}, ctx *parseCo
 	fullBacktrace := backtrace.NewBacktrace()
 	frameCount := 0
 	for _, frameStr := range backtraceStrings {
-		// Cap at 50 frames
-		if frameCount >= 50 {
+		if frameCount >= maxBacktraceFrames {
 			break
 		}
 		// Only add frames that have a valid file:line format (check for colon separator)
 		if strings.Contains(frameStr, ":") {
-			fullBacktrace.Append(frameStr)
+			ctx.appendFrame(&fullBacktrace, frameStr)
 			frameCount++
 		}
 	}
@@ -291,7 +337,7 @@ func convertMapToTestResult(id int, summary map[string]interface{}, ctx *parseCo
 				}
 			}
 		}
-		failureCause = classifyFailure(failureDetails, topFrame, ctx)
+		failureCause = ctx.classifyFailure(failureDetails, topFrame)
 	}
 
 	// Convert file paths to AbsPath
@@ -311,6 +357,7 @@ func convertMapToTestResult(id int, summary map[string]interface{}, ctx *parseCo
 
 	return testresult.TestResult{
 		Id:                id,
+		TestId:            testId,
 		GroupName:         groupName,
 		TestCaseName:      testCaseName,
 		Status:            status,
@@ -461,6 +461,43 @@ func TestParse_BacktraceParsing(t *testing.T) {
 	assert.Equal(t, 5, test.FullBacktrace.Frames[2].Line)
 }
 
+func TestParse_MapsContainerPaths(t *testing.T) {
+	rootPath, _ := types.NewAbsPath("/home/dev/app")
+	require.NoError(t, projectfs.InitProjectFS(rootPath, "test/**/*_test.rb"))
+	fs := projectfs.GetProjectFS()
+	fs.AddPathMapping(projectfs.PathMapping{From: "/app", To: rootPath})
+	fs.SetGemsPath("/home/dev/.gem/ruby/3.3.0/gems")
+
+	yamlData := `---
+- test_group_name: UserTest
+  test_case_name: test_name
+  test_status: failed
+  test_file_path: "/app/test/user_test.rb"
+  test_line_number: 4
+  failure_details: "NoMethodError: undefined method 'name'"
+  failure_file_path: "/app/app/models/user.rb"
+  failure_line_number: 2
+  full_backtrace:
+    - "/app/app/models/user.rb:2:in 'full_name'"
+    - "/usr/local/bundle/gems/minitest-5.20.0/lib/minitest/test.rb:94:in 'run'"
+`
+
+	result, err := Parse([]byte(yamlData), nil)
+	require.NoError(t, err)
+	require.Len(t, result.Tests, 1)
+
+	test := result.Tests[0]
+	assert.Equal(t, types.AbsPath("/home/dev/app/test/user_test.rb"), test.TestFilePath)
+	assert.Equal(t, types.AbsPath("/home/dev/app/app/models/user.rb"), test.FailureFilePath)
+	assert.Equal(t, testresult.FailureCauseProductionCode, test.FailureCause)
+	assert.Equal(t, types.AbsPath("/home/dev/app/app/models/user.rb"), test.FullBacktrace.Frames[0].FilePath)
+	assert.Equal(t, types.AbsPath("/home/dev/.gem/ruby/3.3.0/gems/minitest-5.20.0/lib/minitest/test.rb"), test.FullBacktrace.Frames[1].FilePath)
+
+	filtered := test.FullBacktrace.FilterProjectStackFramesOnly()
+	require.Len(t, filtered.Frames, 1)
+	assert.Equal(t, 2, filtered.Frames[0].Line)
+}
+
 func TestParse_EmptyArray(t *testing.T) {
 	yamlData := `--- []`
 
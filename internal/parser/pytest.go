@@ -0,0 +1,412 @@
+package parser
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/adamakhtar/wing_commander/internal/backtrace"
+	"github.com/adamakhtar/wing_commander/internal/projectfs"
+	"github.com/adamakhtar/wing_commander/internal/testresult"
+	"github.com/adamakhtar/wing_commander/internal/types"
+)
+
+// pytestReport is a single line of a pytest-reportlog file (`pytest --report-log`).
+type pytestReport struct {
+	ReportType string          `json:"$report_type"`
+	NodeId     string          `json:"nodeid"`
+	Location   []interface{}   `json:"location"` // [file, 0-based line, domain]
+	Outcome    string          `json:"outcome"`
+	When       string          `json:"when"`
+	Duration   float64         `json:"duration"`
+	Longrepr   json.RawMessage `json:"longrepr"`
+	Sections   [][]string      `json:"sections"`
+}
+
+// pytestLongrepr is the structured failure representation of a failed report.
+type pytestLongrepr struct {
+	Reprcrash *struct {
+		Path    string `json:"path"`
+		Lineno  int    `json:"lineno"`
+		Message string `json:"message"`
+	} `json:"reprcrash"`
+	Reprtraceback struct {
+		Reprentries []struct {
+			Type string `json:"type"`
+			Data struct {
+				Lines       []string `json:"lines"`
+				Reprfileloc *struct {
+					Path   string `json:"path"`
+					Lineno int    `json:"lineno"`
+				} `json:"reprfileloc"`
+			} `json:"data"`
+		} `json:"reprentries"`
+	} `json:"reprtraceback"`
+}
+
+// pytestTestCase accumulates the setup/call/teardown reports of one node id.
+type pytestTestCase struct {
+	nodeId     string
+	file       string
+	line       int
+	outcome    string
+	failedWhen string
+	duration   float64
+	longrepr   json.RawMessage
+	sections   [][]string
+}
+
+// ParsePytestFile parses a pytest report file, either JUnit XML (--junitxml)
+// or pytest-reportlog JSON lines (--report-log).
+func ParsePytestFile(filePath string, opts *ParseOptions) (*ParseResult, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s: %w", filePath, err)
+	}
+
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("<")) {
+		return ParsePytestJUnit(data, opts)
+	}
+	return ParsePytestReportLog(data, opts)
+}
+
+// ParsePytestJUnit parses pytest's JUnit XML report. Each result gets its node id
+// ("tests/test_cart.py::TestCart::test_total") as TestId, resolved from the
+// dotted classname pytest writes ("tests.test_cart.TestCart").
+func ParsePytestJUnit(data []byte, opts *ParseOptions) (*ParseResult, error) {
+	ctx, err := newParseContext(opts)
+	if err != nil {
+		return nil, err
+	}
+	ctx.outermostFrameFirst = true
+
+	result, err := parseJUnit(data, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range result.Tests {
+		applyPytestNodeId(&result.Tests[i])
+	}
+	return result, nil
+}
+
+// ParsePytestReportLog parses a pytest-reportlog JSON lines file, folding the
+// setup, call and teardown reports of each node id into one result.
+// Collection errors become failed results without a test case name.
+func ParsePytestReportLog(data []byte, opts *ParseOptions) (*ParseResult, error) {
+	ctx, err := newParseContext(opts)
+	if err != nil {
+		return nil, err
+	}
+	ctx.outermostFrameFirst = true
+
+	var cases []*pytestTestCase
+	byNodeId := map[string]*pytestTestCase{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var report pytestReport
+		if err := json.Unmarshal(line, &report); err != nil {
+			return nil, fmt.Errorf("failed to parse pytest report log line: %w", err)
+		}
+
+		switch {
+		case report.ReportType == "TestReport":
+		case report.ReportType == "CollectReport" && report.Outcome == "failed":
+			report.When = "collect"
+		default:
+			continue
+		}
+
+		c, ok := byNodeId[report.NodeId]
+		if !ok {
+			c = &pytestTestCase{nodeId: report.NodeId, file: report.NodeId}
+			if len(report.Location) >= 2 {
+				if file, ok := report.Location[0].(string); ok {
+					c.file = file
+				}
+				if line, ok := report.Location[1].(float64); ok {
+					c.line = int(line) + 1
+				}
+			}
+			byNodeId[report.NodeId] = c
+			cases = append(cases, c)
+		}
+		c.addReport(report)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read pytest report log: %w", err)
+	}
+
+	result := &ParseResult{
+		Summary: TestSummary{},
+	}
+
+	for i, c := range cases {
+		result.addTest(convertPytestTestCaseToTestResult(i+1, c, ctx))
+	}
+
+	return result, nil
+}
+
+// addReport folds a phase report into the test case. A failure in any phase
+// fails the test; otherwise a skip wins over the call passing.
+func (c *pytestTestCase) addReport(report pytestReport) {
+	c.duration += report.Duration
+	c.sections = report.Sections
+
+	switch report.Outcome {
+	case "failed":
+		if c.outcome != "failed" {
+			c.outcome = "failed"
+			c.failedWhen = report.When
+			c.longrepr = report.Longrepr
+		}
+	case "skipped":
+		if c.outcome != "failed" {
+			c.outcome = "skipped"
+		}
+	case "passed":
+		if c.outcome == "" && report.When == "call" {
+			c.outcome = "passed"
+		}
+	}
+}
+
+// convertPytestTestCaseToTestResult converts the folded reports of one node id into a TestResult.
+func convertPytestTestCaseToTestResult(id int, c *pytestTestCase, ctx *parseContext) testresult.TestResult {
+	var status testresult.TestStatus
+	switch c.outcome {
+	case "passed":
+		status = testresult.StatusPass
+	case "skipped":
+		status = testresult.StatusSkip
+	default:
+		status = testresult.StatusFail
+	}
+
+	file, classes, name := splitPytestNodeId(c.nodeId)
+	testId := c.nodeId
+	if c.failedWhen == "collect" {
+		testId = ""
+	}
+
+	var testFilePath types.AbsPath
+	if abs, err := parseFilePath(c.file); err == nil {
+		testFilePath = abs
+	}
+
+	fullBacktrace := backtrace.NewBacktrace()
+	var failureDetails string
+	var failureFilePath types.AbsPath
+	var failureLineNumber int
+	var failureCause testresult.FailureCause
+
+	if status == testresult.StatusFail {
+		var frames []string
+		var crashPath string
+		var crashLine int
+		failureDetails, frames, crashPath, crashLine = pytestFailureDetails(c.longrepr)
+		if c.failedWhen == "setup" || c.failedWhen == "teardown" {
+			failureDetails = "ERROR at " + c.failedWhen + ": " + failureDetails
+		}
+
+		if ctx.outermostFrameFirst {
+			slices.Reverse(frames)
+		}
+		for _, frameStr := range frames {
+			if len(fullBacktrace.Frames) >= maxBacktraceFrames {
+				break
+			}
+			ctx.appendFrame(&fullBacktrace, frameStr)
+		}
+
+		topFrame := firstFrameWithFile(fullBacktrace.AllStackFrames())
+		if topFrame == nil && crashPath != "" {
+			if abs, err := parseFilePath(crashPath); err == nil {
+				topFrame = &types.StackFrame{FilePath: abs, Line: crashLine}
+			}
+		}
+		if topFrame != nil {
+			failureFilePath = topFrame.FilePath
+			failureLineNumber = topFrame.Line
+		}
+		failureCause = ctx.classifyFailure(failureDetails, topFrame)
+	}
+
+	return testresult.TestResult{
+		Id:                id,
+		TestId:            testId,
+		GroupName:         pytestGroupName(file, classes),
+		TestCaseName:      name,
+		Status:            status,
+		FailureCause:      failureCause,
+		FailureDetails:    failureDetails,
+		FailureFilePath:   failureFilePath,
+		FailureLineNumber: failureLineNumber,
+		TestFilePath:      testFilePath,
+		TestLineNumber:    c.line,
+		FullBacktrace:     fullBacktrace,
+		FilteredBacktrace: backtrace.NewBacktrace(),
+		Duration:          c.duration,
+		Output:            pytestSectionsOutput(c.sections),
+	}
+}
+
+// pytestFailureDetails extracts the failure message, frame strings (outermost
+// first) and crash location from a report's longrepr, which is either a
+// structured traceback or, for collection errors, the formatted error text.
+func pytestFailureDetails(raw json.RawMessage) (string, []string, string, int) {
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		message, frames := pytestTextFailureDetails(text)
+		return message, frames, "", 0
+	}
+
+	var longrepr pytestLongrepr
+	if err := json.Unmarshal(raw, &longrepr); err != nil {
+		return strings.TrimSpace(string(raw)), nil, "", 0
+	}
+
+	var frames []string
+	for _, entry := range longrepr.Reprtraceback.Reprentries {
+		if entry.Type == "ReprEntryNative" {
+			_, entryFrames := pytestTextFailureDetails(strings.Join(entry.Data.Lines, ""))
+			frames = append(frames, entryFrames...)
+			continue
+		}
+		if loc := entry.Data.Reprfileloc; loc != nil {
+			frames = append(frames, fmt.Sprintf("%s:%d", loc.Path, loc.Lineno))
+		}
+	}
+
+	if longrepr.Reprcrash == nil {
+		return "", frames, "", 0
+	}
+	return strings.TrimSpace(longrepr.Reprcrash.Message), frames, longrepr.Reprcrash.Path, longrepr.Reprcrash.Lineno
+}
+
+// pytestTextFailureDetails extracts the message ("E   ..." lines, or the last
+// line) and traceback frames from pytest's formatted error text.
+func pytestTextFailureDetails(text string) (string, []string) {
+	var messages []string
+	var frames []string
+	var lastLine string
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			continue
+		case strings.HasPrefix(trimmed, "E "):
+			messages = append(messages, strings.TrimSpace(trimmed[2:]))
+		case junitPythonFrameRegex.MatchString(trimmed):
+			frames = append(frames, trimmed)
+		}
+		lastLine = trimmed
+	}
+
+	if len(messages) == 0 && lastLine != "" {
+		messages = []string{lastLine}
+	}
+	return strings.Join(messages, "\n"), frames
+}
+
+// applyPytestNodeId rewrites a JUnit result so it is identified the way pytest
+// identifies it: node id as TestId, class path as group and module file as test file.
+func applyPytestNodeId(test *testresult.TestResult) {
+	classname := test.GroupName
+
+	var file string
+	var classes []string
+	if test.TestFilePath != "" {
+		// junit_family=xunit1 reports the file and a 0-based line
+		rel, err := projectfs.GetProjectFS().Rel(test.TestFilePath)
+		if err != nil {
+			return
+		}
+		file = filepath.ToSlash(rel.String())
+		module := strings.ReplaceAll(strings.TrimSuffix(file, ".py"), "/", ".")
+		if rest, ok := strings.CutPrefix(classname, module+"."); ok {
+			classes = strings.Split(rest, ".")
+		}
+		test.TestLineNumber++
+	} else {
+		file, classes = resolvePytestClassname(classname)
+		if file == "" {
+			return
+		}
+		if abs, err := parseFilePath(file); err == nil {
+			test.TestFilePath = abs
+		}
+	}
+
+	test.TestId = strings.Join(append(append([]string{file}, classes...), test.TestCaseName), "::")
+	test.GroupName = pytestGroupName(file, classes)
+}
+
+// resolvePytestClassname finds the module file of a dotted classname by looking
+// for the longest prefix that exists as a .py file under the project root, e.g.
+// "tests.test_cart.TestCart" resolves to "tests/test_cart.py" and ["TestCart"].
+func resolvePytestClassname(classname string) (string, []string) {
+	fs := projectfs.GetProjectFS()
+	parts := strings.Split(classname, ".")
+	for i := len(parts); i > 0; i-- {
+		candidate := strings.Join(parts[:i], "/") + ".py"
+		relPath, err := types.NewRelPath(candidate)
+		if err != nil {
+			continue
+		}
+		if info, err := os.Stat(fs.Abs(relPath).String()); err == nil && !info.IsDir() {
+			return candidate, parts[i:]
+		}
+	}
+	return "", nil
+}
+
+// splitPytestNodeId splits "tests/test_cart.py::TestCart::test_total[a-b]" into
+// its file, enclosing classes and (parametrized) test name.
+func splitPytestNodeId(nodeId string) (string, []string, string) {
+	base, params := nodeId, ""
+	if i := strings.Index(nodeId, "["); i >= 0 {
+		base, params = nodeId[:i], nodeId[i:]
+	}
+
+	parts := strings.Split(base, "::")
+	if len(parts) == 1 {
+		return parts[0], nil, ""
+	}
+	return parts[0], parts[1 : len(parts)-1], parts[len(parts)-1] + params
+}
+
+// pytestGroupName returns the enclosing classes ("TestCart::TestTotals"), or the
+// module file for module level tests.
+func pytestGroupName(file string, classes []string) string {
+	if len(classes) == 0 {
+		return file
+	}
+	return strings.Join(classes, "::")
+}
+
+// pytestSectionsOutput joins captured output sections ("Captured stdout call") into one string.
+func pytestSectionsOutput(sections [][]string) string {
+	var parts []string
+	for _, section := range sections {
+		if len(section) != 2 || strings.TrimSpace(section[1]) == "" {
+			continue
+		}
+		parts = append(parts, section[0]+"\n"+strings.TrimSpace(section[1]))
+	}
+	return strings.Join(parts, "\n")
+}
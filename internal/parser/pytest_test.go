@@ -0,0 +1,162 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/adamakhtar/wing_commander/internal/backtrace"
+	"github.com/adamakhtar/wing_commander/internal/projectfs"
+	"github.com/adamakhtar/wing_commander/internal/testresult"
+	"github.com/adamakhtar/wing_commander/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func initPytestProject(t *testing.T) string {
+	t.Helper()
+	rootPath, err := filepath.Abs("../../testdata/fixtures/pytest_project")
+	require.NoError(t, err)
+	require.NoError(t, projectfs.InitProjectFS(types.AbsPath(rootPath), ""))
+	return rootPath
+}
+
+func pytestOptions() *ParseOptions {
+	return &ParseOptions{FrameParser: backtrace.ParsePythonStackFrame}
+}
+
+func TestParsePytestJUnit(t *testing.T) {
+	rootPath := initPytestProject(t)
+
+	result, err := ParsePytestFile("../../testdata/fixtures/pytest_junit.xml", pytestOptions())
+	require.NoError(t, err)
+	require.Len(t, result.Tests, 4)
+	assert.Equal(t, TestSummary{Total: 4, Passed: 1, Failed: 2, Skipped: 1}, result.Summary)
+
+	testFile := types.AbsPath(filepath.Join(rootPath, "tests/test_cart.py"))
+
+	passed := result.Tests[0]
+	assert.Equal(t, "tests/test_cart.py::TestCart::test_total", passed.TestId)
+	assert.Equal(t, "TestCart", passed.GroupName)
+	assert.Equal(t, "test_total", passed.TestCaseName)
+	assert.Equal(t, testFile, passed.TestFilePath)
+
+	assertion := result.Tests[1]
+	assert.Equal(t, testresult.StatusFail, assertion.Status)
+	assert.Equal(t, "assert 90 == 85\n +  where 90 = discount(100)", assertion.FailureDetails)
+	assert.Equal(t, testFile, assertion.FailureFilePath)
+	assert.Equal(t, 11, assertion.FailureLineNumber)
+
+	crashed := result.Tests[2]
+	assert.Equal(t, "tests/test_cart.py::test_checkout", crashed.TestId)
+	assert.Equal(t, "tests/test_cart.py", crashed.GroupName)
+	require.Len(t, crashed.FullBacktrace.Frames, 2)
+	assert.Equal(t, types.AbsPath(filepath.Join(rootPath, "shop/cart.py")), crashed.FailureFilePath)
+	assert.Equal(t, 8, crashed.FailureLineNumber)
+	assert.Equal(t, testFile, crashed.FullBacktrace.Frames[1].FilePath)
+	assert.Equal(t, 16, crashed.FullBacktrace.Frames[1].Line)
+	assert.Equal(t, testresult.FailureCauseProductionCode, crashed.FailureCause)
+	assert.Equal(t, "checking out", crashed.Output)
+
+	assert.Equal(t, testresult.StatusSkip, result.Tests[3].Status)
+}
+
+func TestParsePytestJUnit_FileAttributes(t *testing.T) {
+	rootPath := initPytestProject(t)
+
+	// junit_family=xunit1 adds file and 0-based line attributes
+	xmlData := `<?xml version="1.0" encoding="utf-8"?>
+<testsuites>
+  <testsuite name="pytest" tests="1">
+    <testcase classname="tests.test_cart.TestCart" name="test_total" file="tests/test_cart.py" line="6" time="0.001"/>
+  </testsuite>
+</testsuites>`
+
+	result, err := ParsePytestJUnit([]byte(xmlData), pytestOptions())
+	require.NoError(t, err)
+	require.Len(t, result.Tests, 1)
+
+	test := result.Tests[0]
+	assert.Equal(t, "tests/test_cart.py::TestCart::test_total", test.TestId)
+	assert.Equal(t, "TestCart", test.GroupName)
+	assert.Equal(t, types.AbsPath(filepath.Join(rootPath, "tests/test_cart.py")), test.TestFilePath)
+	assert.Equal(t, 7, test.TestLineNumber)
+}
+
+func TestParsePytestReportLog(t *testing.T) {
+	rootPath := initPytestProject(t)
+	data, err := os.ReadFile("../../testdata/fixtures/pytest_report_log.jsonl")
+	require.NoError(t, err)
+	data = []byte(strings.ReplaceAll(string(data), "{{ROOT}}", rootPath))
+
+	result, err := ParsePytestReportLog(data, pytestOptions())
+	require.NoError(t, err)
+	require.Len(t, result.Tests, 5)
+	assert.Equal(t, TestSummary{Total: 5, Passed: 1, Failed: 3, Skipped: 1}, result.Summary)
+
+	testFile := types.AbsPath(filepath.Join(rootPath, "tests/test_cart.py"))
+
+	collectError := result.Tests[0]
+	assert.Equal(t, testresult.StatusFail, collectError.Status)
+	assert.Equal(t, "tests/test_broken.py", collectError.GroupName)
+	assert.Empty(t, collectError.TestCaseName)
+	assert.Empty(t, collectError.TestId)
+	assert.Equal(t, "ModuleNotFoundError: No module named 'shop.payments'", collectError.FailureDetails)
+	assert.Equal(t, 1, collectError.FailureLineNumber)
+
+	passed := result.Tests[1]
+	assert.Equal(t, "tests/test_cart.py::TestCart::test_total", passed.TestId)
+	assert.Equal(t, "TestCart", passed.GroupName)
+	assert.Equal(t, "test_total", passed.TestCaseName)
+	assert.Equal(t, testresult.StatusPass, passed.Status)
+	assert.Equal(t, testFile, passed.TestFilePath)
+	assert.Equal(t, 7, passed.TestLineNumber)
+	assert.InDelta(t, 0.0015, passed.Duration, 0.00001)
+
+	assertion := result.Tests[2]
+	assert.Equal(t, testresult.StatusFail, assertion.Status)
+	assert.Equal(t, "assert 90 == 85\n +  where 90 = discount(100)", assertion.FailureDetails)
+	assert.Equal(t, testFile, assertion.FailureFilePath)
+	assert.Equal(t, 11, assertion.FailureLineNumber)
+
+	crashed := result.Tests[3]
+	assert.Equal(t, "tests/test_cart.py::test_checkout", crashed.TestId)
+	assert.Equal(t, "ZeroDivisionError: division by zero", crashed.FailureDetails)
+	require.Len(t, crashed.FullBacktrace.Frames, 2)
+	assert.Equal(t, types.AbsPath(filepath.Join(rootPath, "shop/cart.py")), crashed.FailureFilePath)
+	assert.Equal(t, 8, crashed.FailureLineNumber)
+	assert.Equal(t, 16, crashed.FullBacktrace.Frames[1].Line)
+	assert.Equal(t, testresult.FailureCauseProductionCode, crashed.FailureCause)
+	assert.Equal(t, "Captured stdout call\nchecking out", crashed.Output)
+
+	skipped := result.Tests[4]
+	assert.Equal(t, testresult.StatusSkip, skipped.Status)
+	assert.Equal(t, 19, skipped.TestLineNumber)
+}
+
+func TestParsePytestReportLog_SetupError(t *testing.T) {
+	initPytestProject(t)
+
+	data := `{"nodeid": "tests/test_cart.py::test_checkout", "location": ["tests/test_cart.py", 13, "test_checkout"], "outcome": "failed", "when": "setup", "duration": 0.001, "longrepr": {"reprcrash": {"path": "tests/conftest.py", "lineno": 4, "message": "RuntimeError: database unavailable"}, "reprtraceback": {"reprentries": []}}, "sections": [], "$report_type": "TestReport"}
+{"nodeid": "tests/test_cart.py::test_checkout", "location": ["tests/test_cart.py", 13, "test_checkout"], "outcome": "passed", "when": "teardown", "duration": 0.001, "longrepr": null, "sections": [], "$report_type": "TestReport"}
+`
+
+	result, err := ParsePytestReportLog([]byte(data), pytestOptions())
+	require.NoError(t, err)
+	require.Len(t, result.Tests, 1)
+
+	failed := result.Tests[0]
+	assert.Equal(t, testresult.StatusFail, failed.Status)
+	assert.Equal(t, "ERROR at setup: RuntimeError: database unavailable", failed.FailureDetails)
+	assert.Equal(t, 4, failed.FailureLineNumber)
+}
+
+func TestParsePytestReportLog_InvalidLine(t *testing.T) {
+	initPytestProject(t)
+
+	_, err := ParsePytestReportLog([]byte("not json\n"), pytestOptions())
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to parse pytest report log line")
+}
@@ -1,6 +1,15 @@
 package parser
 
-// This file documents the expected WingCommanderReporter YAML summary schema.
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// This file documents the expected WingCommanderReporter YAML summary schema
+// and validates summaries against it.
 /*
 WingCommanderReporter Summary Schema:
 
@@ -29,10 +38,90 @@ Field Definitions:
 - failure_file_path: Absolute path where the failure originated.
 - failure_line_number: Line number associated with the failure.
 - full_backtrace: Array of strings representing the captured backtrace.
+- test_id: Optional framework specific identifier used for re-runs (e.g. RSpec's
+  "./spec/models/user_spec.rb[1:2]"). Omitted by the Minitest reporter.
 
 Additional Notes:
 
 - WingCommanderReporter always emits an array (possibly empty).
 - Paths are expanded to absolute paths before serialization.
 - Backtrace entries are limited to the configured `backtrace_depth`.
+
+Results Stream:
+
+When WING_COMMANDER_STREAM_PATH is set, reporters also append every test to that
+file as a single JSON line, using the same fields as above, the moment the test
+finishes. The file is truncated when the run starts. Wing Commander tails it to
+show results while the run is in flight and falls back to it when the run
+crashes before the summary is written.
+
+Before a test runs, reporters append a started line holding only its group,
+name and location. Tests that started but never finished are reported as
+possibly hung once the stream has been quiet for hung_test_quiet_period.
+
+{"event":"started","test_group_name":"WorkerTest","test_case_name":"test_ok","test_file_path":"test/worker_test.rb","test_line_number":4}
+{"test_group_name":"WorkerTest","test_case_name":"test_ok","test_status":"passed","duration":"0.00"}
 */
+
+// requiredSummaryFields are the fields every test in a summary must have.
+var requiredSummaryFields = []string{"test_group_name", "test_case_name", "test_status"}
+
+// ValidateSummary checks that data is a summary following the schema above,
+// returning every problem found joined into one error.
+func ValidateSummary(data []byte) error {
+	var tests []map[string]interface{}
+	if err := yaml.Unmarshal(data, &tests); err != nil {
+		return fmt.Errorf("summary is not a YAML array of tests: %w", err)
+	}
+
+	var errs []error
+	for i, test := range tests {
+		for _, problem := range validateSummaryTest(test) {
+			errs = append(errs, fmt.Errorf("test %d (%s#%s): %s", i+1, extractString(test, "test_group_name"), extractString(test, "test_case_name"), problem))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func validateSummaryTest(test map[string]interface{}) []string {
+	var problems []string
+	for _, field := range requiredSummaryFields {
+		if extractString(test, field) == "" {
+			problems = append(problems, fmt.Sprintf("%s is missing", field))
+		}
+	}
+
+	switch status := extractString(test, "test_status"); status {
+	case "", "passed", "failed", "skipped":
+	default:
+		problems = append(problems, fmt.Sprintf("test_status %q is not passed, failed or skipped", status))
+	}
+
+	if duration, ok := test["duration"]; ok {
+		if _, err := strconv.ParseFloat(fmt.Sprint(duration), 64); err != nil {
+			problems = append(problems, fmt.Sprintf("duration %q is not a number", fmt.Sprint(duration)))
+		}
+	}
+
+	for _, field := range []string{"test_line_number", "failure_line_number"} {
+		if value, ok := test[field]; ok && value != nil {
+			if _, isInt := value.(int); !isInt {
+				problems = append(problems, fmt.Sprintf("%s %v is not an integer", field, value))
+			}
+		}
+	}
+
+	if backtrace, ok := test["full_backtrace"]; ok && backtrace != nil {
+		frames, isList := backtrace.([]interface{})
+		if !isList {
+			problems = append(problems, "full_backtrace is not a list")
+		}
+		for _, frame := range frames {
+			if _, isString := frame.(string); !isString {
+				problems = append(problems, fmt.Sprintf("full_backtrace entry %v is not a string", frame))
+				break
+			}
+		}
+	}
+	return problems
+}
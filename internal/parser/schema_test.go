@@ -0,0 +1,46 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateSummary(t *testing.T) {
+	valid := `- test_group_name: WorkerTest
+  test_case_name: test_assertion_failure
+  test_status: failed
+  duration: "0.00"
+  test_file_path: "/abs/path/to/test/worker_test.rb"
+  test_line_number: 18
+  failure_details: "Expected: 10"
+  failure_file_path: "/abs/path/to/test/worker_test.rb"
+  failure_line_number: 21
+  full_backtrace:
+    - "/abs/path/to/test/worker_test.rb:21:in 'test_assertion_failure'"
+`
+	assert.NoError(t, ValidateSummary([]byte(valid)))
+	assert.NoError(t, ValidateSummary([]byte("[]\n")), "a run without tests is valid")
+}
+
+func TestValidateSummaryReportsEveryProblem(t *testing.T) {
+	invalid := `- test_group_name: WorkerTest
+  test_status: broken
+  duration: fast
+  test_line_number: "eighteen"
+  full_backtrace: "worker_test.rb:21"
+`
+	err := ValidateSummary([]byte(invalid))
+
+	assert.ErrorContains(t, err, "test 1 (WorkerTest#): test_case_name is missing")
+	assert.ErrorContains(t, err, `test_status "broken" is not passed, failed or skipped`)
+	assert.ErrorContains(t, err, `duration "fast" is not a number`)
+	assert.ErrorContains(t, err, "test_line_number eighteen is not an integer")
+	assert.ErrorContains(t, err, "full_backtrace is not a list")
+}
+
+func TestValidateSummaryRejectsNonArray(t *testing.T) {
+	err := ValidateSummary([]byte("status: ok\n"))
+
+	assert.ErrorContains(t, err, "summary is not a YAML array of tests")
+}
@@ -0,0 +1,62 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/adamakhtar/wing_commander/internal/testresult"
+	"github.com/adamakhtar/wing_commander/internal/types"
+)
+
+// streamEventStarted marks a results stream line announcing that a test began.
+const streamEventStarted = "started"
+
+// StreamEvent is a single line of a reporter's results stream. Exactly one of
+// Started and Result is set.
+type StreamEvent struct {
+	Started *TestStarted
+	Result  *testresult.TestResult
+}
+
+// TestStarted announces a test the reporter has begun running.
+type TestStarted struct {
+	TestId         string
+	GroupName      string
+	TestCaseName   string
+	TestFilePath   types.AbsPath
+	TestLineNumber int
+}
+
+// ParseStreamLine parses one line of a reporter's JSONL results stream. Lines
+// with "event": "started" announce a test before it runs; all other lines are a
+// finished test in the summary schema (see schema.go), appended by the reporter
+// as soon as the test finishes. id is only used for finished tests.
+func ParseStreamLine(id int, line []byte, opts *ParseOptions) (StreamEvent, error) {
+	ctx, err := newParseContext(opts)
+	if err != nil {
+		return StreamEvent{}, err
+	}
+
+	var testMap map[string]interface{}
+	if err := json.Unmarshal(line, &testMap); err != nil {
+		return StreamEvent{}, fmt.Errorf("failed to parse results stream line: %w", err)
+	}
+
+	if extractString(testMap, "event") == streamEventStarted {
+		started := &TestStarted{
+			TestId:         extractString(testMap, "test_id"),
+			GroupName:      extractString(testMap, "test_group_name"),
+			TestCaseName:   extractString(testMap, "test_case_name"),
+			TestLineNumber: extractInt(testMap, "test_line_number"),
+		}
+		if testFilePath := extractString(testMap, "test_file_path"); testFilePath != "" {
+			if abs, err := parseFilePath(testFilePath); err == nil {
+				started.TestFilePath = abs
+			}
+		}
+		return StreamEvent{Started: started}, nil
+	}
+
+	result := convertMapToTestResult(id, testMap, ctx)
+	return StreamEvent{Result: &result}, nil
+}
@@ -0,0 +1,60 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/adamakhtar/wing_commander/internal/projectfs"
+	"github.com/adamakhtar/wing_commander/internal/testresult"
+	"github.com/adamakhtar/wing_commander/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStreamLine(t *testing.T) {
+	rootPath, _ := types.NewAbsPath("/path/to/project")
+	require.NoError(t, projectfs.InitProjectFS(rootPath, ""))
+
+	line := `{"test_group_name":"WorkerTest","test_case_name":"test_assertion_failure","test_status":"failed","duration":"0.25","test_file_path":"/path/to/project/test/worker_test.rb","test_line_number":18,"failure_details":"Expected: 10\n  Actual: 8","failure_file_path":"/path/to/project/test/worker_test.rb","failure_line_number":21,"full_backtrace":["/path/to/project/test/worker_test.rb:21:in 'test_assertion_failure'"]}`
+
+	event, err := ParseStreamLine(3, []byte(line), nil)
+	require.NoError(t, err)
+	assert.Nil(t, event.Started)
+	require.NotNil(t, event.Result)
+
+	result := event.Result
+
+	assert.Equal(t, 3, result.Id)
+	assert.Equal(t, "WorkerTest", result.GroupName)
+	assert.Equal(t, "test_assertion_failure", result.TestCaseName)
+	assert.Equal(t, testresult.StatusFail, result.Status)
+	assert.Equal(t, testresult.FailureCauseAssertion, result.FailureCause)
+	assert.Equal(t, 18, result.TestLineNumber)
+	assert.Equal(t, 21, result.FailureLineNumber)
+	assert.InDelta(t, 0.25, result.Duration, 0.0001)
+	require.Len(t, result.FullBacktrace.Frames, 1)
+}
+
+func TestParseStreamLine_Started(t *testing.T) {
+	rootPath, _ := types.NewAbsPath("/path/to/project")
+	require.NoError(t, projectfs.InitProjectFS(rootPath, ""))
+
+	line := `{"event":"started","test_id":"./test/worker_test.rb[1:1]","test_group_name":"WorkerTest","test_case_name":"test_slow","test_file_path":"test/worker_test.rb","test_line_number":30}`
+
+	event, err := ParseStreamLine(1, []byte(line), nil)
+	require.NoError(t, err)
+	assert.Nil(t, event.Result)
+	assert.Equal(t, &TestStarted{
+		TestId:         "./test/worker_test.rb[1:1]",
+		GroupName:      "WorkerTest",
+		TestCaseName:   "test_slow",
+		TestFilePath:   "/path/to/project/test/worker_test.rb",
+		TestLineNumber: 30,
+	}, event.Started)
+}
+
+func TestParseStreamLine_InvalidJSON(t *testing.T) {
+	_, err := ParseStreamLine(1, []byte(`{"test_case_name":`), nil)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to parse results stream line")
+}
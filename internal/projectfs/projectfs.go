@@ -2,7 +2,9 @@ package projectfs
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/adamakhtar/wing_commander/internal/types"
@@ -15,8 +17,18 @@ var (
 
 // ProjectFS manages the project root path and provides path conversions
 type ProjectFS struct {
-	RootPath       types.AbsPath
-	testFileGlob glob.Glob
+	RootPath        types.AbsPath
+	testFileGlob    glob.Glob
+	pathMappings    []PathMapping
+	gemsPath        types.AbsPath
+	excludePatterns []string
+}
+
+// PathMapping rewrites absolute paths under From to the same path under To,
+// e.g. from the CI checkout a summary was written in to the local checkout.
+type PathMapping struct {
+	From types.AbsPath
+	To   types.AbsPath
 }
 
 // InitProjectFS initializes the singleton with the project root path and optional test file pattern
@@ -85,6 +97,30 @@ func (fs *ProjectFS) IsProjectFile(absPath types.AbsPath) bool {
 	return err == nil
 }
 
+// SetExcludePatterns sets the path substrings, e.g. "/vendor/bundle/", that mark
+// files inside the project as not being project code.
+func (fs *ProjectFS) SetExcludePatterns(patterns []string) {
+	fs.excludePatterns = patterns
+}
+
+// IsExcludedFile checks if the given absolute path is inside the project but
+// matches an exclude pattern. Patterns are matched against the path relative to
+// the root with a leading slash, so the root's own location never matches.
+func (fs *ProjectFS) IsExcludedFile(absPath types.AbsPath) bool {
+	rel, err := fs.Rel(absPath)
+	if err != nil {
+		return false
+	}
+
+	path := "/" + filepath.ToSlash(rel.String())
+	for _, pattern := range fs.excludePatterns {
+		if pattern != "" && strings.Contains(path, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
 // IsTestFile checks if the given absolute path matches the test file pattern.
 // Converts the absolute path to a relative path and matches against the compiled glob pattern.
 func (fs *ProjectFS) IsTestFile(absPath types.AbsPath) bool {
@@ -99,3 +135,97 @@ func (fs *ProjectFS) IsTestFile(absPath types.AbsPath) bool {
 
 	return rel.MatchGlob(fs.testFileGlob)
 }
+
+// AddPathMapping registers a mapping applied by MapPath. Registering the same
+// mapping again has no effect.
+func (fs *ProjectFS) AddPathMapping(mapping PathMapping) {
+	if slices.Contains(fs.pathMappings, mapping) {
+		return
+	}
+	fs.pathMappings = append(fs.pathMappings, mapping)
+}
+
+// SetGemsPath sets the local directory gems are installed in, e.g.
+// vendor/bundle/ruby/3.3.0/gems, which MapPath moves gem files from elsewhere to.
+func (fs *ProjectFS) SetGemsPath(gemsPath types.AbsPath) {
+	fs.gemsPath = gemsPath
+}
+
+// MapPath rewrites an absolute path with the mapping whose From is the longest
+// prefix of it. Without a matching mapping, files of installed gems that don't
+// exist here are moved to the gems path. Other paths are returned unchanged.
+func (fs *ProjectFS) MapPath(path string) string {
+	if mapped, ok := fs.mapPathPrefix(path); ok {
+		return mapped
+	}
+	if mapped, ok := fs.mapGemPath(path); ok {
+		return mapped
+	}
+	return path
+}
+
+func (fs *ProjectFS) mapPathPrefix(path string) (string, bool) {
+	var match *PathMapping
+	for i, mapping := range fs.pathMappings {
+		if !hasPathPrefix(path, mapping.From.String()) {
+			continue
+		}
+		if match == nil || len(mapping.From) > len(match.From) {
+			match = &fs.pathMappings[i]
+		}
+	}
+	if match == nil {
+		return "", false
+	}
+	return filepath.Join(match.To.String(), strings.TrimPrefix(path, match.From.String())), true
+}
+
+// gemsDir separates a gem installation directory from the gems in it, e.g.
+// /usr/local/bundle/gems/rails-7.1.0/lib/rails.rb.
+var gemsDir = string(filepath.Separator) + "gems" + string(filepath.Separator)
+
+// mapGemPath moves a file of an installed gem, e.g. in a container's bundle,
+// to the same gem version in the local gems path.
+func (fs *ProjectFS) mapGemPath(path string) (string, bool) {
+	if fs.gemsPath == "" || hasPathPrefix(path, fs.gemsPath.String()) {
+		return "", false
+	}
+	i := strings.LastIndex(path, gemsDir)
+	if i == -1 {
+		return "", false
+	}
+	if _, err := os.Stat(path); err == nil {
+		return "", false
+	}
+	return filepath.Join(fs.gemsPath.String(), path[i+len(gemsDir):]), true
+}
+
+// DetectRootMapping works out where the project root was on the machine that
+// produced paths, by finding the longest suffix of a path outside the root that
+// names a file inside it. For /home/runner/work/app/test/user_test.rb and a
+// local test/user_test.rb it maps /home/runner/work/app to RootPath.
+func (fs *ProjectFS) DetectRootMapping(paths []types.AbsPath) (PathMapping, bool) {
+	for _, path := range paths {
+		if path == "" || fs.IsProjectFile(path) {
+			continue
+		}
+
+		parts := strings.Split(strings.TrimPrefix(filepath.ToSlash(path.String()), "/"), "/")
+		for i := 1; i < len(parts); i++ {
+			candidate := filepath.Join(append([]string{fs.RootPath.String()}, parts[i:]...)...)
+			if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+				from := types.AbsPath(filepath.FromSlash("/" + strings.Join(parts[:i], "/")))
+				return PathMapping{From: from, To: fs.RootPath}, true
+			}
+		}
+	}
+	return PathMapping{}, false
+}
+
+// hasPathPrefix reports whether path is prefix or a path below it.
+func hasPathPrefix(path string, prefix string) bool {
+	if !strings.HasPrefix(path, prefix) {
+		return false
+	}
+	return len(path) == len(prefix) || path[len(prefix)] == filepath.Separator || strings.HasSuffix(prefix, string(filepath.Separator))
+}
@@ -381,3 +381,95 @@ func TestProjectFS_IsTestFile(t *testing.T) {
 		})
 	}
 }
+
+func TestProjectFS_MapPath(t *testing.T) {
+	defer teardownTestProjectFS(t)
+
+	rootPath, err := types.NewAbsPath("/local/app")
+	require.NoError(t, err)
+	setupTestProjectFS(t, rootPath)
+
+	fs := GetProjectFS()
+	assert.Equal(t, "/ci/app/test/user_test.rb", fs.MapPath("/ci/app/test/user_test.rb"), "no mappings")
+
+	fs.AddPathMapping(PathMapping{From: "/ci", To: "/other"})
+	fs.AddPathMapping(PathMapping{From: "/ci/app", To: "/local/app"})
+
+	assert.Equal(t, "/local/app/test/user_test.rb", fs.MapPath("/ci/app/test/user_test.rb"), "longest prefix wins")
+	assert.Equal(t, "/other/lib/tool.rb", fs.MapPath("/ci/lib/tool.rb"))
+	assert.Equal(t, "/other/application/x.rb", fs.MapPath("/ci/application/x.rb"), "matches whole path components only")
+	assert.Equal(t, "/cider/x.rb", fs.MapPath("/cider/x.rb"))
+}
+
+func TestProjectFS_DetectRootMapping(t *testing.T) {
+	defer teardownTestProjectFS(t)
+
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "test", "models"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "test", "models", "user_test.rb"), []byte(""), 0o644))
+
+	rootPath, err := types.NewAbsPath(root)
+	require.NoError(t, err)
+	setupTestProjectFS(t, rootPath)
+	fs := GetProjectFS()
+
+	t.Run("maps the foreign checkout onto the root", func(t *testing.T) {
+		mapping, ok := fs.DetectRootMapping([]types.AbsPath{
+			"",
+			"/usr/local/bundle/gems/minitest-5.0/lib/minitest.rb",
+			"/home/runner/work/app/app/test/models/user_test.rb",
+		})
+		require.True(t, ok)
+		assert.Equal(t, PathMapping{From: "/home/runner/work/app/app", To: rootPath}, mapping)
+	})
+
+	t.Run("paths already inside the root need no mapping", func(t *testing.T) {
+		_, ok := fs.DetectRootMapping([]types.AbsPath{types.AbsPath(filepath.Join(root, "test", "models", "user_test.rb"))})
+		assert.False(t, ok)
+	})
+
+	t.Run("unknown files", func(t *testing.T) {
+		_, ok := fs.DetectRootMapping([]types.AbsPath{"/app/test/models/missing_test.rb"})
+		assert.False(t, ok)
+	})
+}
+
+func TestProjectFS_MapPathGems(t *testing.T) {
+	defer teardownTestProjectFS(t)
+
+	root := t.TempDir()
+	rootPath, err := types.NewAbsPath(root)
+	require.NoError(t, err)
+	setupTestProjectFS(t, rootPath)
+
+	fs := GetProjectFS()
+	assert.Equal(t, "/usr/local/bundle/gems/rails-7.1.0/lib/rails.rb", fs.MapPath("/usr/local/bundle/gems/rails-7.1.0/lib/rails.rb"), "no gems path")
+
+	gemsPath := filepath.Join(root, "vendor", "bundle", "ruby", "3.3.0", "gems")
+	fs.SetGemsPath(types.AbsPath(gemsPath))
+
+	assert.Equal(t, filepath.Join(gemsPath, "rails-7.1.0", "lib", "rails.rb"), fs.MapPath("/usr/local/bundle/gems/rails-7.1.0/lib/rails.rb"))
+	assert.Equal(t, filepath.Join(gemsPath, "devise-4.9.0", "lib", "devise.rb"), fs.MapPath("/usr/local/bundle/bundler/gems/devise-4.9.0/lib/devise.rb"))
+	assert.Equal(t, "/usr/local/lib/ruby/3.3.0/set.rb", fs.MapPath("/usr/local/lib/ruby/3.3.0/set.rb"), "not a gem")
+
+	// Files that exist locally are left alone
+	localGem := filepath.Join(root, "gems", "local-1.0", "lib", "local.rb")
+	require.NoError(t, os.MkdirAll(filepath.Dir(localGem), 0o755))
+	require.NoError(t, os.WriteFile(localGem, []byte(""), 0o644))
+	assert.Equal(t, localGem, fs.MapPath(localGem))
+
+	// Prefix mappings win over the gems path
+	fs.AddPathMapping(PathMapping{From: "/usr/local/bundle", To: "/opt/bundle"})
+	assert.Equal(t, "/opt/bundle/gems/rails-7.1.0/lib/rails.rb", fs.MapPath("/usr/local/bundle/gems/rails-7.1.0/lib/rails.rb"))
+}
+
+func TestProjectFS_IsExcludedFile(t *testing.T) {
+	rootPath, _ := types.NewAbsPath("/home/me/gems/project")
+	require.NoError(t, InitProjectFS(rootPath, ""))
+	fs := GetProjectFS()
+	fs.SetExcludePatterns([]string{"/vendor/bundle/", "/gems/"})
+
+	assert.True(t, fs.IsExcludedFile("/home/me/gems/project/vendor/bundle/ruby/3.3.0/gems/rails-7.1.0/lib/rails.rb"))
+	assert.False(t, fs.IsExcludedFile("/home/me/gems/project/lib/project.rb"), "the root's own location doesn't match")
+	assert.False(t, fs.IsExcludedFile("/usr/local/bundle/gems/rails-7.1.0/lib/rails.rb"), "files outside the project aren't excluded")
+}
@@ -0,0 +1,100 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/adamakhtar/wing_commander/internal/runner"
+	"github.com/adamakhtar/wing_commander/internal/testresult"
+)
+
+// jsonReport is the document written by WriteJSON. Test fields use the same
+// names as the summary schema reporters write.
+type jsonReport struct {
+	Summary jsonSummary `json:"summary"`
+	Tests   []jsonTest  `json:"tests"`
+}
+
+type jsonSummary struct {
+	Total       int    `json:"total"`
+	Passed      int    `json:"passed"`
+	Failed      int    `json:"failed"`
+	Skipped     int    `json:"skipped"`
+	Flaky       int    `json:"flaky"`
+	Crashed     bool   `json:"crashed"`
+	CrashReason string `json:"crash_reason,omitempty"`
+	Cancelled   bool   `json:"cancelled"`
+}
+
+type jsonTest struct {
+	TestId            string   `json:"test_id,omitempty"`
+	TestGroupName     string   `json:"test_group_name"`
+	TestCaseName      string   `json:"test_case_name"`
+	TestStatus        string   `json:"test_status"`
+	Duration          float64  `json:"duration"`
+	TestFilePath      string   `json:"test_file_path,omitempty"`
+	TestLineNumber    int      `json:"test_line_number,omitempty"`
+	FailureCause      string   `json:"failure_cause,omitempty"`
+	FailureDetails    string   `json:"failure_details,omitempty"`
+	FailureFilePath   string   `json:"failure_file_path,omitempty"`
+	FailureLineNumber int      `json:"failure_line_number,omitempty"`
+	Backtrace         []string `json:"backtrace,omitempty"`
+	Output            string   `json:"output,omitempty"`
+	Flaky             bool     `json:"flaky,omitempty"`
+}
+
+// WriteJSON writes the run's summary and every test, failures first in the
+// order the text report lists them, with project relative paths and filtered
+// backtraces.
+func WriteJSON(w io.Writer, result *runner.TestExecutionResult) error {
+	report := jsonReport{
+		Summary: jsonSummary{
+			Total:       result.Metrics.TotalTests,
+			Passed:      result.Metrics.PassedTests,
+			Failed:      result.Metrics.FailedTests,
+			Skipped:     result.Metrics.SkippedTests,
+			Flaky:       result.Metrics.FlakyTests,
+			Crashed:     result.Crashed,
+			CrashReason: result.CrashReason,
+			Cancelled:   result.Cancelled,
+		},
+		Tests: []jsonTest{},
+	}
+
+	for _, group := range groupFailures(result.FailedTests) {
+		for _, test := range group.Tests {
+			report.Tests = append(report.Tests, newJSONTest(test))
+		}
+	}
+	for _, test := range result.TestResults {
+		if !test.IsFailed() {
+			report.Tests = append(report.Tests, newJSONTest(test))
+		}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+func newJSONTest(test testresult.TestResult) jsonTest {
+	jt := jsonTest{
+		TestId:            test.TestId,
+		TestGroupName:     test.GroupName,
+		TestCaseName:      test.TestCaseName,
+		TestStatus:        statusName(test.Status),
+		Duration:          test.Duration,
+		TestFilePath:      relPath(test.TestFilePath),
+		TestLineNumber:    test.TestLineNumber,
+		FailureCause:      string(test.FailureCause),
+		FailureDetails:    test.FailureDetails,
+		FailureFilePath:   relPath(test.FailureFilePath),
+		FailureLineNumber: test.FailureLineNumber,
+		Output:            test.Output,
+		Flaky:             test.Flaky,
+	}
+	for _, frame := range test.FilteredBacktrace.Frames {
+		jt.Backtrace = append(jt.Backtrace, formatFrame(frame))
+	}
+	return jt
+}
@@ -0,0 +1,45 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteJSON(t *testing.T) {
+	result := newTestExecutionResult(t)
+
+	var out bytes.Buffer
+	require.NoError(t, WriteJSON(&out, result))
+
+	var got jsonReport
+	require.NoError(t, json.Unmarshal(out.Bytes(), &got))
+
+	assert.Equal(t, jsonSummary{Total: 4, Passed: 1, Failed: 2, Skipped: 1}, got.Summary)
+
+	require.Len(t, got.Tests, 4)
+	names := []string{}
+	for _, test := range got.Tests {
+		names = append(names, test.TestCaseName)
+	}
+	assert.Equal(t, []string{"test_call", "test_sum", "test_total", "test_later"}, names)
+
+	assert.Equal(t, jsonTest{
+		TestGroupName:     "WorkerTest",
+		TestCaseName:      "test_call",
+		TestStatus:        "failed",
+		Duration:          0.25,
+		TestFilePath:      "test/worker_test.rb",
+		TestLineNumber:    8,
+		FailureCause:      "production_code_error",
+		FailureDetails:    "RuntimeError: boom",
+		FailureFilePath:   "app/worker.rb",
+		FailureLineNumber: 3,
+		Backtrace:         []string{"app/worker.rb:3:in `call'"},
+		Output:            "debug output",
+	}, got.Tests[0])
+	assert.Equal(t, "skipped", got.Tests[3].TestStatus)
+}
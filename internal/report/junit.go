@@ -0,0 +1,132 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/adamakhtar/wing_commander/internal/runner"
+	"github.com/adamakhtar/wing_commander/internal/testresult"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+
+	duration float64
+}
+
+type junitTestCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	File      string        `xml:"file,attr,omitempty"`
+	Line      int           `xml:"line,attr,omitempty"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *struct{}     `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Body    string `xml:",chardata"`
+}
+
+// WriteJUnit writes the run as JUnit XML, one testsuite per test group in the
+// order the groups were first reported. Failures carry their cause as type and
+// their details and filtered backtrace as body.
+func WriteJUnit(w io.Writer, result *runner.TestExecutionResult) error {
+	report := junitTestSuites{
+		Name:     "wing_commander",
+		Tests:    result.Metrics.TotalTests,
+		Failures: result.Metrics.FailedTests,
+		Skipped:  result.Metrics.SkippedTests,
+	}
+
+	suiteIndex := map[string]int{}
+	var total float64
+	for _, test := range result.TestResults {
+		idx, ok := suiteIndex[test.GroupName]
+		if !ok {
+			idx = len(report.Suites)
+			suiteIndex[test.GroupName] = idx
+			report.Suites = append(report.Suites, junitTestSuite{Name: test.GroupName})
+		}
+
+		suite := &report.Suites[idx]
+		suite.Tests++
+		suite.duration += test.Duration
+		total += test.Duration
+
+		testCase := junitTestCase{
+			ClassName: test.GroupName,
+			Name:      test.TestCaseName,
+			File:      relPath(test.TestFilePath),
+			Line:      test.TestLineNumber,
+			Time:      junitTime(test.Duration),
+			SystemOut: test.Output,
+		}
+		switch test.Status {
+		case testresult.StatusFail:
+			suite.Failures++
+			testCase.Failure = newJUnitFailure(test)
+		case testresult.StatusSkip:
+			suite.Skipped++
+			testCase.Skipped = &struct{}{}
+		}
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+
+	for i := range report.Suites {
+		report.Suites[i].Time = junitTime(report.Suites[i].duration)
+	}
+	report.Time = junitTime(total)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func newJUnitFailure(test testresult.TestResult) *junitFailure {
+	body := test.FailureDetails
+	var frames []string
+	for _, frame := range test.FilteredBacktrace.Frames {
+		frames = append(frames, formatFrame(frame))
+	}
+	if len(frames) > 0 {
+		body = strings.TrimRight(body, "\n") + "\n\n" + strings.Join(frames, "\n")
+	}
+
+	return &junitFailure{
+		Message: firstLine(test.FailureDetails),
+		Type:    string(test.FailureCause),
+		Body:    strings.TrimLeft(body, "\n"),
+	}
+}
+
+func junitTime(seconds float64) string {
+	return fmt.Sprintf("%.3f", seconds)
+}
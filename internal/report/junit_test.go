@@ -0,0 +1,51 @@
+package report
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteJUnit(t *testing.T) {
+	result := newTestExecutionResult(t)
+
+	var out bytes.Buffer
+	require.NoError(t, WriteJUnit(&out, result))
+	assert.True(t, strings.HasPrefix(out.String(), xml.Header))
+
+	var got junitTestSuites
+	require.NoError(t, xml.Unmarshal(out.Bytes(), &got))
+
+	assert.Equal(t, 4, got.Tests)
+	assert.Equal(t, 2, got.Failures)
+	assert.Equal(t, 1, got.Skipped)
+	assert.Equal(t, "0.875", got.Time)
+
+	// One suite per group, in the order the groups were first reported
+	require.Len(t, got.Suites, 2)
+	worker, cart := got.Suites[0], got.Suites[1]
+	assert.Equal(t, "WorkerTest", worker.Name)
+	assert.Equal(t, 3, worker.Tests)
+	assert.Equal(t, 2, worker.Failures)
+	assert.Equal(t, 1, worker.Skipped)
+	assert.Equal(t, "CartTest", cart.Name)
+	assert.Equal(t, 1, cart.Tests)
+	assert.Nil(t, cart.TestCases[0].Failure)
+
+	call := worker.TestCases[1]
+	assert.Equal(t, "test_call", call.Name)
+	assert.Equal(t, "test/worker_test.rb", call.File)
+	assert.Equal(t, 8, call.Line)
+	assert.Equal(t, "debug output", call.SystemOut)
+	require.NotNil(t, call.Failure)
+	assert.Equal(t, "RuntimeError: boom", call.Failure.Message)
+	assert.Equal(t, "production_code_error", call.Failure.Type)
+	assert.Equal(t, "RuntimeError: boom\n\napp/worker.rb:3:in `call'", call.Failure.Body)
+
+	assert.Equal(t, "Expected: 1", worker.TestCases[0].Failure.Message)
+	assert.NotNil(t, worker.TestCases[2].Skipped)
+}
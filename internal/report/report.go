@@ -0,0 +1,125 @@
+// Package report writes the results of a test run for terminals and CI, in the
+// same order and with the same classification the TUI shows.
+package report
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+
+	"github.com/adamakhtar/wing_commander/internal/projectfs"
+	"github.com/adamakhtar/wing_commander/internal/runner"
+	"github.com/adamakhtar/wing_commander/internal/testresult"
+	"github.com/adamakhtar/wing_commander/internal/types"
+)
+
+// Format selects how a report is written.
+type Format string
+
+const (
+	FormatText  Format = "text"
+	FormatJSON  Format = "json"
+	FormatJUnit Format = "junit"
+)
+
+var supportedFormats = []Format{FormatText, FormatJSON, FormatJUnit}
+
+// ValidateFormat ensures that the provided format is supported.
+func ValidateFormat(value string) (Format, error) {
+	format := Format(value)
+	if value == "" {
+		return FormatText, nil
+	}
+	if !slices.Contains(supportedFormats, format) {
+		return "", fmt.Errorf("unsupported format: %s (supported: %v)", value, supportedFormats)
+	}
+	return format, nil
+}
+
+// Write writes the report of a test run in the given format.
+func Write(w io.Writer, format Format, result *runner.TestExecutionResult) error {
+	switch format {
+	case FormatText:
+		return WriteText(w, result)
+	case FormatJSON:
+		return WriteJSON(w, result)
+	case FormatJUnit:
+		return WriteJUnit(w, result)
+	default:
+		return fmt.Errorf("unsupported format: %s (supported: %v)", format, supportedFormats)
+	}
+}
+
+// failureCauseOrder lists failure causes in the order the TUI sorts them.
+var failureCauseOrder = []testresult.FailureCause{
+	testresult.FailureCauseProductionCode,
+	testresult.FailureCauseTestDefinition,
+	testresult.FailureCauseAssertion,
+	"",
+}
+
+// failureGroup holds the failed tests sharing a failure cause.
+type failureGroup struct {
+	Cause testresult.FailureCause
+	Tests []testresult.TestResult
+}
+
+// groupFailures groups failed tests by cause, skipping empty groups.
+func groupFailures(failed []testresult.TestResult) []failureGroup {
+	var groups []failureGroup
+	for _, cause := range failureCauseOrder {
+		group := failureGroup{Cause: cause}
+		for _, test := range failed {
+			if test.FailureCause == cause || (cause == "" && !slices.Contains(failureCauseOrder, test.FailureCause)) {
+				group.Tests = append(group.Tests, test)
+			}
+		}
+		if len(group.Tests) > 0 {
+			groups = append(groups, group)
+		}
+	}
+	return groups
+}
+
+// relPath returns path relative to the project root when possible.
+func relPath(path types.AbsPath) string {
+	if path == "" {
+		return ""
+	}
+	if rel, err := projectfs.GetProjectFS().Rel(path); err == nil {
+		return rel.String()
+	}
+	return path.String()
+}
+
+// location formats a path and line as "path:line".
+func location(path types.AbsPath, line int) string {
+	if line <= 0 {
+		return relPath(path)
+	}
+	return relPath(path) + ":" + strconv.Itoa(line)
+}
+
+// formatFrame formats a stack frame as "path:line:in `function'".
+func formatFrame(frame types.StackFrame) string {
+	formatted := location(frame.FilePath, frame.Line)
+	if frame.Function != "" {
+		formatted += ":in `" + frame.Function + "'"
+	}
+	return formatted
+}
+
+// statusName returns the status as written in summaries: passed, failed or skipped.
+func statusName(status testresult.TestStatus) string {
+	switch status {
+	case testresult.StatusPass:
+		return "passed"
+	case testresult.StatusFail:
+		return "failed"
+	case testresult.StatusSkip:
+		return "skipped"
+	default:
+		return string(status)
+	}
+}
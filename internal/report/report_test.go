@@ -0,0 +1,91 @@
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/adamakhtar/wing_commander/internal/backtrace"
+	"github.com/adamakhtar/wing_commander/internal/projectfs"
+	"github.com/adamakhtar/wing_commander/internal/runner"
+	"github.com/adamakhtar/wing_commander/internal/testresult"
+	"github.com/adamakhtar/wing_commander/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestExecutionResult builds a run with an assertion failure, a production
+// code failure, a pass and a skip in a temporary project.
+func newTestExecutionResult(t *testing.T) *runner.TestExecutionResult {
+	t.Helper()
+
+	root := t.TempDir()
+	rootPath, _ := types.NewAbsPath(root)
+	require.NoError(t, projectfs.InitProjectFS(rootPath, ""))
+
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "app"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "app", "worker.rb"), []byte("class Worker\n  def call\n    raise \"boom\"\n  end\nend\n"), 0o644))
+
+	abs := func(rel string) types.AbsPath {
+		return types.AbsPath(filepath.Join(root, rel))
+	}
+
+	assertion := testresult.TestResult{
+		GroupName:      "WorkerTest",
+		TestCaseName:   "test_sum",
+		Status:         testresult.StatusFail,
+		FailureCause:   testresult.FailureCauseAssertion,
+		FailureDetails: "Expected: 1\n  Actual: 2",
+		TestFilePath:   abs("test/worker_test.rb"),
+		TestLineNumber: 4,
+		Duration:       0.5,
+	}
+	production := testresult.TestResult{
+		GroupName:         "WorkerTest",
+		TestCaseName:      "test_call",
+		Status:            testresult.StatusFail,
+		FailureCause:      testresult.FailureCauseProductionCode,
+		FailureDetails:    "RuntimeError: boom",
+		FailureFilePath:   abs("app/worker.rb"),
+		FailureLineNumber: 3,
+		TestFilePath:      abs("test/worker_test.rb"),
+		TestLineNumber:    8,
+		Output:            "debug output",
+		Duration:          0.25,
+		FilteredBacktrace: backtrace.Backtrace{Frames: []types.StackFrame{
+			{FilePath: abs("app/worker.rb"), Line: 3, Function: "call"},
+		}},
+	}
+	passed := testresult.TestResult{
+		GroupName:    "CartTest",
+		TestCaseName: "test_total",
+		Status:       testresult.StatusPass,
+		Duration:     0.125,
+	}
+	skipped := testresult.TestResult{
+		GroupName:    "WorkerTest",
+		TestCaseName: "test_later",
+		Status:       testresult.StatusSkip,
+	}
+
+	return &runner.TestExecutionResult{
+		TestResults:  []testresult.TestResult{assertion, passed, production, skipped},
+		FailedTests:  []testresult.TestResult{assertion, production},
+		PassedTests:  []testresult.TestResult{passed},
+		SkippedTests: []testresult.TestResult{skipped},
+		Metrics:      runner.Metrics{TotalTests: 4, PassedTests: 1, FailedTests: 2, SkippedTests: 1},
+	}
+}
+
+func TestValidateFormat(t *testing.T) {
+	format, err := ValidateFormat("")
+	require.NoError(t, err)
+	assert.Equal(t, FormatText, format)
+
+	format, err = ValidateFormat("junit")
+	require.NoError(t, err)
+	assert.Equal(t, FormatJUnit, format)
+
+	_, err = ValidateFormat("html")
+	assert.ErrorContains(t, err, "unsupported format: html")
+}
@@ -0,0 +1,109 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/adamakhtar/wing_commander/internal/filesnippet"
+	"github.com/adamakhtar/wing_commander/internal/runner"
+	"github.com/adamakhtar/wing_commander/internal/testresult"
+)
+
+// snippetRadius is how many lines around a backtrace frame's line are shown.
+const snippetRadius = 2
+
+// WriteText writes a human readable report: failures grouped by cause, each
+// with its message, output and filtered backtrace with source snippets,
+// followed by a summary line.
+func WriteText(w io.Writer, result *runner.TestExecutionResult) error {
+	tw := &textWriter{w: w}
+
+	number := 0
+	for _, group := range groupFailures(result.FailedTests) {
+		tw.printf("%s (%d)\n\n", failureGroupTitle(group.Cause), len(group.Tests))
+		for _, test := range group.Tests {
+			number++
+			tw.writeFailure(number, test)
+		}
+	}
+
+	metrics := result.Metrics
+	tw.printf("%d tests, %d passed, %d failed, %d skipped\n",
+		metrics.TotalTests, metrics.PassedTests, metrics.FailedTests, metrics.SkippedTests)
+	if metrics.FlakyTests > 0 {
+		tw.printf("%d flaky: passed when retried after failing\n", metrics.FlakyTests)
+	}
+	if result.Crashed {
+		tw.printf("Suite crashed: %s\n", firstLine(result.CrashReason))
+	}
+	if result.Cancelled {
+		tw.printf("Run cancelled, results are incomplete\n")
+	}
+	return tw.err
+}
+
+// failureGroupTitle names a group of failures in the text report.
+func failureGroupTitle(cause testresult.FailureCause) string {
+	if cause.String() == "" {
+		return "Other Failures"
+	}
+	return cause.String() + "s"
+}
+
+// textWriter remembers the first write error so a report can be written
+// without checking every line.
+type textWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (tw *textWriter) printf(format string, args ...any) {
+	if tw.err != nil {
+		return
+	}
+	_, tw.err = fmt.Fprintf(tw.w, format, args...)
+}
+
+func (tw *textWriter) writeFailure(number int, test testresult.TestResult) {
+	tw.printf("%d) %s\n", number, strings.TrimSpace(test.GroupName+" "+test.TestCaseName))
+	if test.TestFilePath != "" {
+		tw.printf("   %s\n", location(test.TestFilePath, test.TestLineNumber))
+	}
+	if test.FailureDetails != "" {
+		tw.printf("\n%s\n", indent(test.FailureDetails, "   "))
+	}
+	if test.Output != "" {
+		tw.printf("\n   Output:\n%s\n", indent(test.Output, "     "))
+	}
+
+	for _, frame := range test.FilteredBacktrace.Frames {
+		tw.printf("\n   %s\n", formatFrame(frame))
+		snippet, err := filesnippet.ExtractLines(frame.FilePath.String(), frame.Line, snippetRadius)
+		if err != nil {
+			continue
+		}
+		for _, line := range snippet.Lines {
+			marker := " "
+			if line.IsCenter {
+				marker = ">"
+			}
+			tw.printf("   %s %4d: %s\n", marker, line.Number, line.Content)
+		}
+	}
+	tw.printf("\n")
+}
+
+// indent prefixes every line of s, trimming trailing blank lines.
+func indent(s string, prefix string) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(prefix+line, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
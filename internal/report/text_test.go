@@ -0,0 +1,56 @@
+package report
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteText(t *testing.T) {
+	result := newTestExecutionResult(t)
+
+	var out bytes.Buffer
+	require.NoError(t, WriteText(&out, result))
+
+	want := "Production Code Errors (1)\n" +
+		"\n" +
+		"1) WorkerTest test_call\n" +
+		"   test/worker_test.rb:8\n" +
+		"\n" +
+		"   RuntimeError: boom\n" +
+		"\n" +
+		"   Output:\n" +
+		"     debug output\n" +
+		"\n" +
+		"   app/worker.rb:3:in `call'\n" +
+		"        1: class Worker\n" +
+		"        2:   def call\n" +
+		"   >    3:     raise \"boom\"\n" +
+		"        4:   end\n" +
+		"        5: end\n" +
+		"\n" +
+		"Assertion Failures (1)\n" +
+		"\n" +
+		"2) WorkerTest test_sum\n" +
+		"   test/worker_test.rb:4\n" +
+		"\n" +
+		"   Expected: 1\n" +
+		"     Actual: 2\n" +
+		"\n" +
+		"4 tests, 1 passed, 2 failed, 1 skipped\n"
+	assert.Equal(t, want, out.String())
+}
+
+func TestWriteText_CrashedRun(t *testing.T) {
+	result := newTestExecutionResult(t)
+	result.Crashed = true
+	result.CrashReason = "test command exited with code 139\nmore details"
+
+	var out bytes.Buffer
+	require.NoError(t, WriteText(&out, result))
+
+	assert.Contains(t, out.String(), "Suite crashed: test command exited with code 139\n")
+	assert.NotContains(t, out.String(), "more details")
+}
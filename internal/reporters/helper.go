@@ -0,0 +1,71 @@
+package reporters
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/adamakhtar/wing_commander/internal/config"
+)
+
+// HelperChange is the change to a project file that loads the installed
+// reporter, e.g. enabling WingCommanderFormatter in .rspec.
+type HelperChange struct {
+	// Path is relative to the project root.
+	Path string
+	// Snippet is appended to the file.
+	Snippet string
+	// marker is present in the file once the change has been made.
+	marker string
+}
+
+var helperChanges = map[config.TestFramework]HelperChange{
+	config.FrameworkRSpec: {
+		Path: ".rspec",
+		Snippet: `--require wing_commander_formatter
+--format WingCommanderFormatter
+`,
+		marker: "WingCommanderFormatter",
+	},
+}
+
+// HelperChangeFor returns the change loading the framework's reporter. The
+// second value is false for frameworks that don't need one, including
+// Minitest whose reporter is loaded at run time.
+func HelperChangeFor(framework config.TestFramework) (HelperChange, bool) {
+	change, ok := helperChanges[framework]
+	return change, ok
+}
+
+// IsApplied reports whether the project at root already loads the reporter.
+func (c HelperChange) IsApplied(root string) bool {
+	data, err := os.ReadFile(filepath.Join(root, c.Path))
+	return err == nil && strings.Contains(string(data), c.marker)
+}
+
+// Apply appends the snippet to the file in the project at root, creating it
+// when needed, unless it has already been applied.
+func (c HelperChange) Apply(root string) error {
+	if c.IsApplied(root) {
+		return nil
+	}
+
+	target := filepath.Join(root, c.Path)
+	data, err := os.ReadFile(target)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to read %s: %w", target, err)
+	}
+
+	content := string(data)
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	content += c.Snippet
+
+	if err := os.WriteFile(target, []byte(content), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", target, err)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package reporters
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/adamakhtar/wing_commander/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHelperChangeApplyRSpec(t *testing.T) {
+	root := t.TempDir()
+	optionsPath := filepath.Join(root, ".rspec")
+	require.NoError(t, os.WriteFile(optionsPath, []byte("--require spec_helper"), 0o644))
+
+	change, ok := HelperChangeFor(config.FrameworkRSpec)
+	require.True(t, ok)
+	assert.False(t, change.IsApplied(root))
+
+	require.NoError(t, change.Apply(root))
+	require.NoError(t, change.Apply(root))
+
+	content, err := os.ReadFile(optionsPath)
+	require.NoError(t, err)
+	assert.Equal(t, "--require spec_helper\n"+change.Snippet, string(content))
+	assert.True(t, change.IsApplied(root))
+}
+
+func TestHelperChangeApplyCreatesRSpecOptions(t *testing.T) {
+	root := t.TempDir()
+	change, ok := HelperChangeFor(config.FrameworkRSpec)
+	require.True(t, ok)
+
+	require.NoError(t, change.Apply(root))
+
+	content, err := os.ReadFile(filepath.Join(root, ".rspec"))
+	require.NoError(t, err)
+	assert.Equal(t, change.Snippet, string(content))
+}
+
+func TestHelperChangeForFrameworkWithoutReporter(t *testing.T) {
+	_, ok := HelperChangeFor(config.FrameworkGoTest)
+	assert.False(t, ok)
+
+	_, ok = HelperChangeFor(config.FrameworkMinitest)
+	assert.False(t, ok, "the Minitest reporter is loaded at run time")
+}
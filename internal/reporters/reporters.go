@@ -0,0 +1,150 @@
+// Package reporters embeds the Ruby reporter, formatter and runner that write
+// Wing Commander summaries, and installs them into projects.
+package reporters
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/adamakhtar/wing_commander/internal/config"
+)
+
+//go:embed ruby
+var rubyFiles embed.FS
+
+// frameworkDirs maps frameworks to their directory of files in rubyFiles, laid
+// out as they are installed into the project.
+var frameworkDirs = map[config.TestFramework]string{
+	config.FrameworkMinitest: "ruby/minitest",
+	config.FrameworkRSpec:    "ruby/rspec",
+}
+
+// File is a file installed into a project.
+type File struct {
+	// Path is relative to the project root, e.g. test/wing_commander_reporter.rb.
+	Path       string
+	Content    []byte
+	Executable bool
+}
+
+// InstallStatus says what Install did with a file.
+type InstallStatus string
+
+const (
+	StatusCreated     InstallStatus = "created"
+	StatusOverwritten InstallStatus = "overwritten"
+	StatusUnchanged   InstallStatus = "unchanged"
+	// StatusSkipped means a different file already exists and wasn't overwritten.
+	StatusSkipped InstallStatus = "skipped"
+)
+
+// InstallResult is the outcome of installing one file.
+type InstallResult struct {
+	Path   string
+	Status InstallStatus
+}
+
+// minitestLoadPathDir holds the Minitest reporter and a plugin registering it,
+// laid out as a Ruby load path directory.
+const minitestLoadPathDir = "ruby/inject"
+
+// Files returns the files the framework needs installed into a project.
+// Frameworks whose own reports are read, or whose reporter is loaded at run
+// time, may need none.
+func Files(framework config.TestFramework) ([]File, error) {
+	dir, ok := frameworkDirs[framework]
+	if !ok {
+		return nil, nil
+	}
+	return embeddedFiles(dir)
+}
+
+// WriteMinitestLoadPath writes the Minitest reporter and a Minitest plugin
+// registering it into dir. Adding dir to the load path of a test process, e.g.
+// with RUBYOPT=-Idir, reports its tests without changes to the project.
+func WriteMinitestLoadPath(dir string) error {
+	files, err := embeddedFiles(minitestLoadPathDir)
+	if err != nil {
+		return err
+	}
+	_, err = Install(dir, files, true)
+	return err
+}
+
+// embeddedFiles returns the files under dir in rubyFiles with paths relative to it.
+func embeddedFiles(dir string) ([]File, error) {
+	var files []File
+	err := fs.WalkDir(rubyFiles, dir, func(name string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		content, err := rubyFiles.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		rel := name[len(dir)+1:]
+		files = append(files, File{
+			Path:       rel,
+			Content:    content,
+			Executable: path.Dir(rel) == "bin" && path.Ext(rel) == "",
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read embedded files in %s: %w", dir, err)
+	}
+	return files, nil
+}
+
+// Install writes files into the project at root. Existing files with other
+// content are left alone unless force is set.
+func Install(root string, files []File, force bool) ([]InstallResult, error) {
+	results := make([]InstallResult, 0, len(files))
+	for _, file := range files {
+		status, err := install(root, file, force)
+		if err != nil {
+			return results, err
+		}
+		results = append(results, InstallResult{Path: file.Path, Status: status})
+	}
+	return results, nil
+}
+
+func install(root string, file File, force bool) (InstallStatus, error) {
+	target := filepath.Join(root, filepath.FromSlash(file.Path))
+
+	status := StatusCreated
+	existing, err := os.ReadFile(target)
+	switch {
+	case err == nil && bytes.Equal(existing, file.Content):
+		return StatusUnchanged, nil
+	case err == nil && !force:
+		return StatusSkipped, nil
+	case err == nil:
+		status = StatusOverwritten
+	case !errors.Is(err, os.ErrNotExist):
+		return "", fmt.Errorf("failed to read %s: %w", target, err)
+	}
+
+	mode := os.FileMode(0o644)
+	if file.Executable {
+		mode = 0o755
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return "", fmt.Errorf("failed to create directory for %s: %w", target, err)
+	}
+	if err := os.WriteFile(target, file.Content, mode); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", target, err)
+	}
+	// WriteFile keeps the mode of a file it overwrites
+	if err := os.Chmod(target, mode); err != nil {
+		return "", fmt.Errorf("failed to set mode of %s: %w", target, err)
+	}
+	return status, nil
+}
@@ -0,0 +1,78 @@
+package reporters
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/adamakhtar/wing_commander/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFiles(t *testing.T) {
+	files, err := Files(config.FrameworkMinitest)
+	require.NoError(t, err)
+
+	paths := map[string]File{}
+	for _, file := range files {
+		paths[file.Path] = file
+	}
+	assert.Len(t, paths, 2, "the reporter is loaded at run time, not installed")
+	assert.Contains(t, paths, "bin/wing_commander_runner.rb")
+	assert.True(t, paths["bin/test"].Executable)
+	assert.False(t, paths["bin/wing_commander_runner.rb"].Executable)
+
+	files, err = Files(config.FrameworkRSpec)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, "spec/wing_commander_formatter.rb", files[0].Path)
+
+	files, err = Files(config.FrameworkGoTest)
+	require.NoError(t, err)
+	assert.Empty(t, files)
+}
+
+func TestInstall(t *testing.T) {
+	root := t.TempDir()
+	files := []File{
+		{Path: "bin/test", Content: []byte("#!/usr/bin/env ruby\n"), Executable: true},
+		{Path: "test/reporter.rb", Content: []byte("class Reporter; end\n")},
+	}
+
+	results, err := Install(root, files, false)
+	require.NoError(t, err)
+	assert.Equal(t, []InstallResult{{"bin/test", StatusCreated}, {"test/reporter.rb", StatusCreated}}, results)
+
+	info, err := os.Stat(filepath.Join(root, "bin", "test"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o755), info.Mode().Perm())
+
+	require.NoError(t, os.WriteFile(filepath.Join(root, "bin", "test"), []byte("# ours\n"), 0o755))
+
+	results, err = Install(root, files, false)
+	require.NoError(t, err)
+	assert.Equal(t, []InstallResult{{"bin/test", StatusSkipped}, {"test/reporter.rb", StatusUnchanged}}, results)
+	content, _ := os.ReadFile(filepath.Join(root, "bin", "test"))
+	assert.Equal(t, "# ours\n", string(content))
+
+	results, err = Install(root, files, true)
+	require.NoError(t, err)
+	assert.Equal(t, StatusOverwritten, results[0].Status)
+	content, _ = os.ReadFile(filepath.Join(root, "bin", "test"))
+	assert.Equal(t, "#!/usr/bin/env ruby\n", string(content))
+}
+
+func TestWriteMinitestLoadPath(t *testing.T) {
+	dir := t.TempDir()
+
+	require.NoError(t, WriteMinitestLoadPath(dir))
+
+	reporter, err := os.ReadFile(filepath.Join(dir, "wing_commander_reporter.rb"))
+	require.NoError(t, err)
+	assert.Contains(t, string(reporter), "class WingCommanderReporter")
+
+	plugin, err := os.ReadFile(filepath.Join(dir, "minitest", "wing_commander_plugin.rb"))
+	require.NoError(t, err)
+	assert.Contains(t, string(plugin), "def self.plugin_wing_commander_init")
+}
@@ -0,0 +1,28 @@
+# frozen_string_literal: true
+
+# Registers WingCommanderReporter in test processes Wing Commander runs, so
+# projects don't need to set it up in test_helper.rb.
+#
+# Wing Commander writes this file and the reporter to a directory it adds to
+# the load path through RUBYOPT. Minitest loads every minitest/*_plugin.rb on
+# the load path when the tests start, after Bundler has set up the gems, so
+# nothing is loaded into other Ruby processes such as bundle or rake.
+
+module Minitest
+  def self.plugin_wing_commander_init(options)
+    return if ENV["WING_COMMANDER_SUMMARY_PATH"].to_s.empty?
+
+    begin
+      require "wing_commander_reporter"
+      require "minitest/minitest_reporter_plugin"
+    rescue LoadError => e
+      warn "Wing Commander: can't load its reporter (#{e.message}), add the minitest-reporters gem to the Gemfile"
+      return
+    end
+
+    # The project registered the reporter itself
+    return if Array(Minitest::Reporters.reporters).any? { |r| r.is_a?(WingCommanderReporter) }
+
+    reporter << Minitest::Reporters::DelegateReporter.new([WingCommanderReporter.new], options)
+  end
+end
@@ -10,24 +10,28 @@
 #   Minitest::Reporters.use! [
 #     WingCommanderReporter.new(
 #       backtrace_depth: 50,
-#       summary_output_path: '/path/to/summary.yml'  # Optional: save summary to file
+#       summary_output_path: '/path/to/summary.yml',  # Optional: save summary to file, defaults to $WING_COMMANDER_SUMMARY_PATH
+#       stream_output_path: '/path/to/stream.jsonl'   # Optional: defaults to $WING_COMMANDER_STREAM_PATH
 #     )
 #   ]
 #
 # Output format:
 #   Progress markers: <<START>>PPFSSP<<END>> (P=pass, F=fail, S=skip) - always to stdout
 #   Summary: YAML array of all test details - to stdout or file if specified
+#   Stream: a started JSON line before each test and a result JSON line as it finishes - only if a stream path is set
 
 require 'yaml'
+require 'json'
 require 'minitest/reporters'
 require 'fileutils'
 
 
 class WingCommanderReporter < Minitest::Reporters::BaseReporter
-  def initialize(backtrace_depth: 50, summary_output_path: nil, **options)
+  def initialize(backtrace_depth: 50, summary_output_path: ENV['WING_COMMANDER_SUMMARY_PATH'], stream_output_path: ENV['WING_COMMANDER_STREAM_PATH'], **options)
     super(options)
     @backtrace_depth = backtrace_depth
-    @summary_output_path = summary_output_path
+    @summary_output_path = summary_output_path unless summary_output_path.to_s.empty?
+    @stream_output_path = stream_output_path unless stream_output_path.to_s.empty?
     @all_tests = []
   end
 
@@ -37,9 +41,17 @@ class WingCommanderReporter < Minitest::Reporters::BaseReporter
     if @summary_output_path && File.exist?(@summary_output_path)
       File.delete(@summary_output_path)
     end
+    open_stream
     io.puts '<<START>>'
   end
 
+  def before_test(test)
+    super
+
+    # Announce the test so Wing Commander can spot it if it never finishes
+    write_stream(build_test_started(test))
+  end
+
   def record(result)
     super
 
@@ -52,6 +64,9 @@ class WingCommanderReporter < Minitest::Reporters::BaseReporter
       io.print 'F'
     end
 
+    # Append the test to the stream immediately so it survives a crash
+    write_stream(build_test_summary(result))
+
     # Store all tests for summary
     @all_tests << result
   end
@@ -60,6 +75,7 @@ class WingCommanderReporter < Minitest::Reporters::BaseReporter
     super
     io.puts
     io.puts '<<END>>'
+    @stream&.close
 
     # Output YAML summary of all tests
     summary = @all_tests.map { |test| build_test_summary(test) }
@@ -78,6 +94,35 @@ class WingCommanderReporter < Minitest::Reporters::BaseReporter
 
   private
 
+  def open_stream
+    return unless @stream_output_path
+
+    stream_dir = File.dirname(@stream_output_path)
+    FileUtils.mkdir_p(stream_dir) unless stream_dir == '.' || stream_dir.empty?
+    @stream = File.open(@stream_output_path, 'w')
+    @stream.sync = true
+  end
+
+  def write_stream(summary)
+    @stream&.puts(JSON.generate(summary))
+  end
+
+  def build_test_started(test)
+    started = {
+      'event' => 'started',
+      'test_group_name' => test.class.name,
+      'test_case_name' => test.name
+    }
+
+    source_location = test.method(test.name).source_location
+    if source_location
+      started['test_file_path'] = File.expand_path(source_location[0])
+      started['test_line_number'] = source_location[1]
+    end
+
+    started
+  end
+
   def build_test_summary(result)
     test_class_name = if result.respond_to?(:klass)
       klass = result.klass
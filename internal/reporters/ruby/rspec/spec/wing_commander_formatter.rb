@@ -0,0 +1,159 @@
+# frozen_string_literal: true
+
+# WingCommanderFormatter - Custom RSpec formatter for Wing Commander CLI
+#
+# Emits the same progress markers and YAML summary schema as WingCommanderReporter
+# (Minitest) so Wing Commander can read RSpec results.
+#
+# Usage:
+#   # .rspec
+#   --require spec_helper
+#   --format WingCommanderFormatter
+#
+#   # spec/spec_helper.rb
+#   require_relative 'wing_commander_formatter'
+#
+# Options (environment variables):
+#   WING_COMMANDER_SUMMARY_PATH     - summary file (default: .wing_commander/test_results/summary.yml)
+#   WING_COMMANDER_BACKTRACE_DEPTH  - max backtrace frames per failure (default: 50)
+#   WING_COMMANDER_STREAM_PATH      - results stream file (set by Wing Commander, disabled when unset)
+#
+# Output format:
+#   Progress markers: <<START>>PPFSSP<<END>> (P=pass, F=fail, S=skip) - always to stdout
+#   Summary: YAML array of all example details - to the summary file
+#   Stream: a started JSON line before each example and a result JSON line as it finishes - to the stream file
+
+require 'yaml'
+require 'json'
+require 'fileutils'
+require 'rspec/core'
+require 'rspec/core/formatters/base_formatter'
+
+class WingCommanderFormatter < RSpec::Core::Formatters::BaseFormatter
+  RSpec::Core::Formatters.register self, :start, :example_started, :example_passed, :example_failed, :example_pending, :stop, :close
+
+  DEFAULT_SUMMARY_PATH = '.wing_commander/test_results/summary.yml'
+
+  def initialize(output)
+    super
+    @backtrace_depth = Integer(ENV.fetch('WING_COMMANDER_BACKTRACE_DEPTH', 50))
+    @summary_output_path = ENV.fetch('WING_COMMANDER_SUMMARY_PATH', DEFAULT_SUMMARY_PATH)
+    @stream_output_path = ENV['WING_COMMANDER_STREAM_PATH'] unless ENV['WING_COMMANDER_STREAM_PATH'].to_s.empty?
+    @all_examples = []
+  end
+
+  def start(notification)
+    super
+    File.delete(@summary_output_path) if File.exist?(@summary_output_path)
+    open_stream
+    output.puts '<<START>>'
+  end
+
+  # Announces the example so Wing Commander can spot it if it never finishes.
+  def example_started(notification)
+    example = notification.example
+    @stream&.puts(JSON.generate(
+      'event' => 'started',
+      'test_id' => example.id,
+      'test_group_name' => example.example_group.description,
+      'test_case_name' => example.description,
+      'test_file_path' => File.expand_path(example.metadata[:file_path]),
+      'test_line_number' => example.metadata[:line_number]
+    ))
+  end
+
+  def example_passed(notification)
+    output.print 'P'
+    record(build_example_summary(notification.example, 'passed'))
+  end
+
+  def example_failed(notification)
+    output.print 'F'
+    record(build_example_summary(notification.example, 'failed'))
+  end
+
+  def example_pending(notification)
+    output.print 'S'
+    record(build_example_summary(notification.example, 'skipped'))
+  end
+
+  def stop(_notification)
+    output.puts
+    output.puts '<<END>>'
+    @stream&.close
+  end
+
+  def close(notification)
+    super
+    summary_dir = File.dirname(@summary_output_path)
+    FileUtils.mkdir_p(summary_dir) unless summary_dir == '.' || summary_dir.empty?
+    File.write(@summary_output_path, YAML.dump(@all_examples))
+  end
+
+  private
+
+  # Appends the example to the stream immediately so it survives a crash, and
+  # keeps it for the summary.
+  def record(summary)
+    @stream&.puts(JSON.generate(summary))
+    @all_examples << summary
+  end
+
+  def open_stream
+    return unless @stream_output_path
+
+    stream_dir = File.dirname(@stream_output_path)
+    FileUtils.mkdir_p(stream_dir) unless stream_dir == '.' || stream_dir.empty?
+    @stream = File.open(@stream_output_path, 'w')
+    @stream.sync = true
+  end
+
+  def build_example_summary(example, status)
+    summary = {
+      'test_id' => example.id,
+      'test_group_name' => example.example_group.description,
+      'test_case_name' => example.description,
+      'test_status' => status,
+      'duration' => format('%.2f', example.execution_result.run_time.to_f),
+      'test_file_path' => File.expand_path(example.metadata[:file_path]),
+      'test_line_number' => example.metadata[:line_number]
+    }
+
+    add_failure_details(summary, example) if status == 'failed'
+    summary
+  end
+
+  def add_failure_details(summary, example)
+    exception = example.execution_result.exception
+    return unless exception
+
+    summary['failure_details'] = failure_message(exception)
+
+    backtrace = exception.backtrace || []
+    file_path, line_number = parse_backtrace_line(backtrace.first)
+    if file_path
+      summary['failure_file_path'] = File.expand_path(file_path)
+      summary['failure_line_number'] = line_number
+    end
+
+    summary['full_backtrace'] = backtrace.first(@backtrace_depth) unless backtrace.empty?
+  end
+
+  def failure_message(exception)
+    if defined?(RSpec::Expectations::ExpectationNotMetError) && exception.is_a?(RSpec::Expectations::ExpectationNotMetError)
+      exception.message.strip
+    else
+      "#{exception.class}: #{exception.message}"
+    end
+  end
+
+  def parse_backtrace_line(backtrace_line)
+    return [nil, nil] unless backtrace_line
+
+    # Format: "file:line:in method" or "file:line"
+    match = backtrace_line.match(/^(.+?):(\d+)/)
+    return [nil, nil] unless match
+
+    [match[1], match[2].to_i]
+  end
+end
@@ -0,0 +1,137 @@
+package runner
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/adamakhtar/wing_commander/internal/testresult"
+)
+
+// Change classifies how a test's outcome changed from one test run to another.
+type Change string
+
+const (
+	ChangeNone                       Change = "none"         // Not failing in either run
+	ChangeNewlyFailed                Change = "newly_failed" // Fails now but didn't before
+	ChangeFixed                      Change = "fixed"        // Failed before but doesn't now
+	ChangeStillFailingSameError      Change = "still_failing_same_error"
+	ChangeStillFailingDifferentError Change = "still_failing_different_error"
+	ChangeAdded                      Change = "added"   // Only in the current run
+	ChangeRemoved                    Change = "removed" // Only in the previous run
+)
+
+// TestDiff is the change of a single test between two runs.
+type TestDiff struct {
+	Change   Change
+	Previous *testresult.TestResult // nil when the test was added
+	Current  *testresult.TestResult // nil when the test was removed
+}
+
+// Diff compares the results of two test runs test by test.
+type Diff struct {
+	PreviousTestRunId int
+	CurrentTestRunId  int
+	Tests             []TestDiff
+	byCurrentId       map[int]Change
+}
+
+// Compare matches the tests of two runs by group, name and file and classifies
+// how each changed from previous to current. Tests sharing a key are paired in
+// the order they were reported.
+func Compare(previous *TestExecutionResult, current *TestExecutionResult) Diff {
+	diff := Diff{byCurrentId: make(map[int]Change)}
+	var previousResults, currentResults []testresult.TestResult
+	if previous != nil {
+		diff.PreviousTestRunId = previous.TestRunId
+		previousResults = previous.TestResults
+	}
+	if current != nil {
+		diff.CurrentTestRunId = current.TestRunId
+		currentResults = current.TestResults
+	}
+
+	unmatched := make(map[testresult.Key][]int)
+	for i, result := range previousResults {
+		key := result.Key()
+		unmatched[key] = append(unmatched[key], i)
+	}
+
+	matched := make([]bool, len(previousResults))
+	for i := range currentResults {
+		currentResult := &currentResults[i]
+		key := currentResult.Key()
+		testDiff := TestDiff{Change: ChangeAdded, Current: currentResult}
+		if indexes := unmatched[key]; len(indexes) > 0 {
+			unmatched[key] = indexes[1:]
+			matched[indexes[0]] = true
+			testDiff.Previous = &previousResults[indexes[0]]
+			testDiff.Change = classify(*testDiff.Previous, *currentResult)
+		}
+		diff.Tests = append(diff.Tests, testDiff)
+		diff.byCurrentId[currentResult.Id] = testDiff.Change
+	}
+
+	for i := range previousResults {
+		if !matched[i] {
+			diff.Tests = append(diff.Tests, TestDiff{Change: ChangeRemoved, Previous: &previousResults[i]})
+		}
+	}
+	return diff
+}
+
+func classify(previous testresult.TestResult, current testresult.TestResult) Change {
+	switch {
+	case previous.IsFailed() && current.IsFailed():
+		if errorSignature(previous) == errorSignature(current) {
+			return ChangeStillFailingSameError
+		}
+		return ChangeStillFailingDifferentError
+	case current.IsFailed():
+		return ChangeNewlyFailed
+	case previous.IsFailed():
+		return ChangeFixed
+	default:
+		return ChangeNone
+	}
+}
+
+// objectAddress matches addresses in inspected objects, e.g. #<User:0x000055d5>,
+// which differ from run to run.
+var objectAddress = regexp.MustCompile(`0x[0-9a-fA-F]+`)
+
+// errorSignature identifies a failure by its cause and message, ignoring
+// details that change between runs of the same error.
+func errorSignature(result testresult.TestResult) string {
+	details := objectAddress.ReplaceAllString(strings.TrimSpace(result.FailureDetails), "0x")
+	return string(result.FailureCause) + "\n" + details
+}
+
+// ChangeOf returns how the current run's result with the given ID changed.
+func (d Diff) ChangeOf(testResultId int) (Change, bool) {
+	change, ok := d.byCurrentId[testResultId]
+	return change, ok
+}
+
+// Count returns the number of tests with the given change.
+func (d Diff) Count(change Change) int {
+	count := 0
+	for _, test := range d.Tests {
+		if test.Change == change {
+			count++
+		}
+	}
+	return count
+}
+
+// WithoutRemoved drops the tests only found in the previous run, e.g. when the
+// current run only ran some of the tests.
+func (d Diff) WithoutRemoved() Diff {
+	tests := make([]TestDiff, 0, len(d.Tests))
+	for _, test := range d.Tests {
+		if test.Change != ChangeRemoved {
+			tests = append(tests, test)
+		}
+	}
+	d.Tests = tests
+	return d
+}
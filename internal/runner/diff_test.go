@@ -0,0 +1,87 @@
+package runner
+
+import (
+	"testing"
+
+	"github.com/adamakhtar/wing_commander/internal/testresult"
+	"github.com/adamakhtar/wing_commander/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func diffResult(id int, name string, status testresult.TestStatus, details string) testresult.TestResult {
+	result := testresult.NewTestResult("UserTest", name, status)
+	result.Id = id
+	result.TestFilePath = types.AbsPath("/project/test/user_test.rb")
+	if status == testresult.StatusFail {
+		result.FailureCause = testresult.FailureCauseAssertion
+		result.FailureDetails = details
+	}
+	return result
+}
+
+func TestCompare(t *testing.T) {
+	previous := NewTestExecutionResult(1, []testresult.TestResult{
+		diffResult(1, "test_passing", testresult.StatusPass, ""),
+		diffResult(2, "test_breaks", testresult.StatusPass, ""),
+		diffResult(3, "test_fixed", testresult.StatusFail, "Expected: 1"),
+		diffResult(4, "test_same_error", testresult.StatusFail, "Expected #<User:0x000055d5c8> to be valid"),
+		diffResult(5, "test_different_error", testresult.StatusFail, "Expected: 1"),
+		diffResult(6, "test_removed", testresult.StatusFail, "Expected: 1"),
+	})
+	current := NewTestExecutionResult(2, []testresult.TestResult{
+		diffResult(1, "test_passing", testresult.StatusPass, ""),
+		diffResult(2, "test_breaks", testresult.StatusFail, "Expected: 1"),
+		diffResult(3, "test_fixed", testresult.StatusPass, ""),
+		diffResult(4, "test_same_error", testresult.StatusFail, "Expected #<User:0x00007f3a10> to be valid"),
+		diffResult(5, "test_different_error", testresult.StatusFail, "Expected: 2"),
+		diffResult(6, "test_added", testresult.StatusPass, ""),
+	})
+
+	diff := Compare(previous, current)
+
+	assert.Equal(t, 1, diff.PreviousTestRunId)
+	assert.Equal(t, 2, diff.CurrentTestRunId)
+	want := map[int]Change{
+		1: ChangeNone,
+		2: ChangeNewlyFailed,
+		3: ChangeFixed,
+		4: ChangeStillFailingSameError,
+		5: ChangeStillFailingDifferentError,
+		6: ChangeAdded,
+	}
+	for id, change := range want {
+		got, ok := diff.ChangeOf(id)
+		require.True(t, ok, "result %d", id)
+		assert.Equal(t, change, got, "result %d", id)
+	}
+
+	require.Len(t, diff.Tests, 7)
+	removed := diff.Tests[6]
+	assert.Equal(t, ChangeRemoved, removed.Change)
+	assert.Nil(t, removed.Current)
+	assert.Equal(t, "test_removed", removed.Previous.TestCaseName)
+	assert.Equal(t, 1, diff.Count(ChangeRemoved))
+	assert.Zero(t, diff.WithoutRemoved().Count(ChangeRemoved))
+}
+
+func TestCompare_MatchesByFile(t *testing.T) {
+	inOtherFile := diffResult(1, "test_name", testresult.StatusFail, "Expected: 1")
+	inOtherFile.TestFilePath = types.AbsPath("/project/test/admin_test.rb")
+	previous := NewTestExecutionResult(1, []testresult.TestResult{inOtherFile})
+	current := NewTestExecutionResult(2, []testresult.TestResult{diffResult(1, "test_name", testresult.StatusFail, "Expected: 1")})
+
+	diff := Compare(previous, current)
+
+	assert.Equal(t, 1, diff.Count(ChangeAdded))
+	assert.Equal(t, 1, diff.Count(ChangeRemoved))
+}
+
+func TestCompare_WithoutPreviousRun(t *testing.T) {
+	current := NewTestExecutionResult(2, []testresult.TestResult{diffResult(1, "test_name", testresult.StatusPass, "")})
+
+	diff := Compare(nil, current)
+
+	assert.Equal(t, 1, diff.Count(ChangeAdded))
+	assert.Zero(t, diff.PreviousTestRunId)
+}
@@ -0,0 +1,66 @@
+package runner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/adamakhtar/wing_commander/internal/config"
+	"github.com/adamakhtar/wing_commander/internal/projectfs"
+	"github.com/adamakhtar/wing_commander/internal/reporters"
+	"github.com/adamakhtar/wing_commander/internal/testrun"
+)
+
+// rubyOptEnvVar holds options Ruby applies to every process it starts.
+const rubyOptEnvVar = "RUBYOPT"
+
+// injectsReporter reports whether the runner loads the Minitest reporter into
+// the test processes, so projects don't register it in test_helper.rb.
+func (r *TestRunner) injectsReporter() bool {
+	return r.config.TestFramework == config.FrameworkMinitest && !r.config.DisableReporterInjection
+}
+
+// summaryPath returns where the summary of testRun is written. When the runner
+// injects the reporter it owns the location, and each run gets its own file
+// next to test_results_path, e.g. summary-3.yml, so runs never read each
+// other's summaries.
+func (r *TestRunner) summaryPath(testRun testrun.TestRun) string {
+	resultsPath := r.resultsPath()
+	if !r.injectsReporter() || resultsPath == "" {
+		return resultsPath
+	}
+	ext := filepath.Ext(resultsPath)
+	return fmt.Sprintf("%s-%d%s", strings.TrimSuffix(resultsPath, ext), testRun.Id, ext)
+}
+
+// injectReporter writes the Minitest reporter and the plugin registering it to
+// a new directory and returns the RUBYOPT value adding that directory to the
+// load path, along with a function removing it. Locally the directory is
+// temporary and any RUBYOPT already set is kept. For other execution targets it
+// is created inside the project so the target sees it too.
+func (r *TestRunner) injectReporter(target executionTarget) (string, func(), error) {
+	parent := ""
+	if target.prefix != "" {
+		parent = filepath.Join(projectfs.GetProjectFS().RootPath.String(), ".wing_commander", "tmp")
+		if err := os.MkdirAll(parent, 0o755); err != nil {
+			return "", nil, fmt.Errorf("failed to create directory for the reporter: %w", err)
+		}
+	}
+
+	dir, err := os.MkdirTemp(parent, "wing_commander-reporter-")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create directory for the reporter: %w", err)
+	}
+	cleanup := func() { os.RemoveAll(dir) }
+	if err := reporters.WriteMinitestLoadPath(dir); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+
+	rubyOpt := "-I" + target.path(dir)
+	if existing := os.Getenv(rubyOptEnvVar); existing != "" && target.prefix == "" {
+		rubyOpt = existing + " " + rubyOpt
+	}
+	return rubyOpt, cleanup, nil
+}
@@ -0,0 +1,87 @@
+package runner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/adamakhtar/wing_commander/internal/config"
+	"github.com/adamakhtar/wing_commander/internal/projectfs"
+	"github.com/adamakhtar/wing_commander/internal/testrun"
+	"github.com/adamakhtar/wing_commander/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTestRunner_SummaryPath(t *testing.T) {
+	rootPath, _ := types.NewAbsPath(t.TempDir())
+	require.NoError(t, projectfs.InitProjectFS(rootPath, ""))
+	testRun := testrun.TestRun{Id: 3}
+
+	cfg := &config.Config{
+		TestFramework:   config.FrameworkMinitest,
+		TestResultsPath: ".wing_commander/test_results/summary.yml",
+	}
+	assert.Equal(t, filepath.Join(rootPath.String(), ".wing_commander/test_results/summary-3.yml"), NewTestRunner(cfg).summaryPath(testRun))
+
+	cfg.DisableReporterInjection = true
+	assert.Equal(t, filepath.Join(rootPath.String(), ".wing_commander/test_results/summary.yml"), NewTestRunner(cfg).summaryPath(testRun))
+
+	cfg = &config.Config{
+		TestFramework:   config.FrameworkRSpec,
+		TestResultsPath: ".wing_commander/test_results/summary.yml",
+	}
+	assert.Equal(t, filepath.Join(rootPath.String(), ".wing_commander/test_results/summary.yml"), NewTestRunner(cfg).summaryPath(testRun))
+}
+
+func TestTestRunner_ExecuteTestsInjectsReporter(t *testing.T) {
+	rootPath, _ := types.NewAbsPath(t.TempDir())
+	require.NoError(t, projectfs.InitProjectFS(rootPath, ""))
+	t.Setenv("RUBYOPT", "-W0")
+
+	// Stands in for Ruby: checks the plugin is on the load path RUBYOPT adds,
+	// then writes the summary where the runner asked for it
+	command := `dir="${RUBYOPT#-W0 -I}"; echo "$dir" > loadpath.txt; ` +
+		`test -f "$dir/minitest/wing_commander_plugin.rb" && test -f "$dir/wing_commander_reporter.rb" || exit 3; ` +
+		`printf '%s' '` + summaryYAML + `' > "$WING_COMMANDER_SUMMARY_PATH"`
+
+	runner := NewTestRunner(&config.Config{
+		TestFramework:   config.FrameworkMinitest,
+		TestCommand:     command,
+		TestResultsPath: "summary.yml",
+		TestStreamPath:  "stream.jsonl",
+	})
+
+	result, err := runner.ExecuteTests(context.Background(), testrun.TestRun{Id: 7, Mode: string(testrun.ModeRunWholeSuite)}, Hooks{})
+
+	require.NoError(t, err)
+	assert.False(t, result.Crashed, result.CrashReason)
+	assert.Equal(t, 1, result.Metrics.PassedTests)
+
+	assert.NoFileExists(t, filepath.Join(rootPath.String(), "summary-7.yml"), "the run's summary is removed once parsed")
+	loadPath, err := os.ReadFile(filepath.Join(rootPath.String(), "loadpath.txt"))
+	require.NoError(t, err)
+	assert.NoDirExists(t, strings.TrimSpace(string(loadPath)), "the injected reporter is removed after the run")
+}
+
+func TestTestRunner_ExecuteTestsFallsBackToConfiguredSummary(t *testing.T) {
+	rootPath, _ := types.NewAbsPath(t.TempDir())
+	require.NoError(t, projectfs.InitProjectFS(rootPath, ""))
+
+	// A project registering the reporter itself with a fixed summary_output_path
+	runner := NewTestRunner(&config.Config{
+		TestFramework:   config.FrameworkMinitest,
+		TestCommand:     "printf '%s' '" + summaryYAML + "' > summary.yml",
+		TestResultsPath: "summary.yml",
+		TestStreamPath:  "stream.jsonl",
+	})
+
+	result, err := runner.ExecuteTests(context.Background(), testrun.TestRun{Id: 2, Mode: string(testrun.ModeRunWholeSuite)}, Hooks{})
+
+	require.NoError(t, err)
+	assert.False(t, result.Crashed, result.CrashReason)
+	assert.Equal(t, 1, result.Metrics.PassedTests)
+	assert.FileExists(t, filepath.Join(rootPath.String(), "summary.yml"))
+}
@@ -0,0 +1,103 @@
+package runner
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/adamakhtar/wing_commander/internal/framework"
+	"github.com/adamakhtar/wing_commander/internal/parser"
+	"github.com/adamakhtar/wing_commander/internal/projectfs"
+	"github.com/adamakhtar/wing_commander/internal/testresult"
+	"github.com/adamakhtar/wing_commander/internal/types"
+	"github.com/charmbracelet/log"
+)
+
+// LoadResults reads existing summary files, e.g. downloaded from CI, into a
+// single result without running any tests. When the summaries were written in
+// a checkout elsewhere, their absolute paths are mapped onto the project root
+// so backtraces, previews and re-runs work against the local files.
+func (r *TestRunner) LoadResults(testRunId int, paths []string) (*TestExecutionResult, error) {
+	adapter, err := framework.Get(r.config.TestFramework)
+	if err != nil {
+		return nil, err
+	}
+
+	tests, err := parseSummaryFiles(adapter, paths)
+	if err != nil {
+		return nil, err
+	}
+
+	fs := projectfs.GetProjectFS()
+	if mapping, ok := fs.DetectRootMapping(testFilePaths(tests)); ok {
+		log.Info("mapping summary paths to project root", "from", mapping.From, "to", mapping.To)
+		fs.AddPathMapping(mapping)
+		// Parse again so classification and backtrace filtering see local paths
+		if tests, err = parseSummaryFiles(adapter, paths); err != nil {
+			return nil, err
+		}
+	}
+
+	normalizedResults := testresult.NewNormalizer().NormalizeTestResults(tests)
+	result := NewTestExecutionResult(testRunId, normalizedResults)
+	result.ExecutionTime = latestModTime(paths, result.ExecutionTime)
+	return result, nil
+}
+
+// parseSummaryFiles parses every file with the adapter, numbering the results
+// of all files in one sequence.
+func parseSummaryFiles(adapter framework.Adapter, paths []string) ([]testresult.TestResult, error) {
+	var tests []testresult.TestResult
+	for _, path := range paths {
+		parsed, err := parseSummaryFile(adapter, path)
+		if err != nil {
+			return nil, err
+		}
+		for _, test := range parsed.Tests {
+			test.Id = len(tests) + 1
+			tests = append(tests, test)
+		}
+	}
+	return tests, nil
+}
+
+func parseSummaryFile(adapter framework.Adapter, path string) (*parser.ParseResult, error) {
+	output := framework.Output{ResultsPath: path}
+	if !framework.ReadsSummaryFile(adapter) {
+		// e.g. saved `go test -json` output
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		output.CommandOutput = string(data)
+	}
+
+	parsed, err := adapter.ParseResults(output)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return parsed, nil
+}
+
+func testFilePaths(tests []testresult.TestResult) []types.AbsPath {
+	paths := make([]types.AbsPath, 0, len(tests))
+	for _, test := range tests {
+		paths = append(paths, test.TestFilePath)
+	}
+	return paths
+}
+
+// latestModTime returns when the newest of the files was written, or fallback
+// when none can be read.
+func latestModTime(paths []string, fallback time.Time) time.Time {
+	var latest time.Time
+	for _, path := range paths {
+		if info, err := os.Stat(path); err == nil && info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	if latest.IsZero() {
+		return fallback
+	}
+	return latest
+}
@@ -0,0 +1,88 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/adamakhtar/wing_commander/internal/config"
+	"github.com/adamakhtar/wing_commander/internal/projectfs"
+	"github.com/adamakhtar/wing_commander/internal/testresult"
+	"github.com/adamakhtar/wing_commander/internal/testrun"
+	"github.com/adamakhtar/wing_commander/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const ciSummary = `---
+- test_group_name: UserTest
+  test_case_name: test_name
+  test_status: failed
+  duration: "0.01"
+  test_file_path: "/home/runner/work/app/app/test/user_test.rb"
+  test_line_number: 4
+  failure_details: "NoMethodError: undefined method 'name'"
+  failure_file_path: "/home/runner/work/app/app/app/models/user.rb"
+  failure_line_number: 2
+  full_backtrace:
+    - "/home/runner/work/app/app/app/models/user.rb:2:in 'full_name'"
+    - "/home/runner/work/app/app/test/user_test.rb:5:in 'test_name'"
+    - "/opt/hostedtoolcache/Ruby/3.3.0/lib/ruby/gems/3.3.0/gems/minitest-5.20.0/lib/minitest/test.rb:94:in 'run'"
+`
+
+const ciSummaryPass = `---
+- test_group_name: UserTest
+  test_case_name: test_email
+  test_status: passed
+  duration: "0.02"
+  test_file_path: "/home/runner/work/app/app/test/user_test.rb"
+  test_line_number: 8
+`
+
+func TestTestRunner_LoadResultsMapsForeignPaths(t *testing.T) {
+	root := t.TempDir()
+	rootPath, _ := types.NewAbsPath(root)
+	require.NoError(t, projectfs.InitProjectFS(rootPath, "test/**/*_test.rb"))
+
+	for _, rel := range []string{"test/user_test.rb", "app/models/user.rb"} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(root, rel)), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(root, rel), []byte("\n"), 0o644))
+	}
+
+	downloads := t.TempDir()
+	failedPath := filepath.Join(downloads, "summary_1.yml")
+	passedPath := filepath.Join(downloads, "summary_2.yml")
+	require.NoError(t, os.WriteFile(failedPath, []byte(ciSummary), 0o644))
+	require.NoError(t, os.WriteFile(passedPath, []byte(ciSummaryPass), 0o644))
+
+	runner := NewTestRunner(config.NewConfig("bin/rails test", "", "", "", false))
+	result, err := runner.LoadResults(3, []string{failedPath, passedPath})
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, result.TestRunId)
+	assert.Equal(t, Metrics{TotalTests: 2, PassedTests: 1, FailedTests: 1}, result.Metrics)
+	assert.Equal(t, []int{1, 2}, []int{result.TestResults[0].Id, result.TestResults[1].Id})
+
+	failed := result.FailedTests[0]
+	testFile := types.AbsPath(filepath.Join(root, "test/user_test.rb"))
+	assert.Equal(t, testFile, failed.TestFilePath)
+	assert.Equal(t, types.AbsPath(filepath.Join(root, "app/models/user.rb")), failed.FailureFilePath)
+	assert.Equal(t, testresult.FailureCauseProductionCode, failed.FailureCause)
+	// Project frames survive filtering once mapped, the gem frame does not
+	require.Len(t, failed.FilteredBacktrace.Frames, 2)
+	assert.Equal(t, testFile, failed.FilteredBacktrace.Frames[1].FilePath)
+
+	// Re-runs target the local checkout
+	pattern, err := testrun.NewTestPatternForResult(failed)
+	require.NoError(t, err)
+	assert.Equal(t, testFile.String(), pattern.Path)
+}
+
+func TestTestRunner_LoadResultsInvalidFile(t *testing.T) {
+	rootPath, _ := types.NewAbsPath(t.TempDir())
+	require.NoError(t, projectfs.InitProjectFS(rootPath, ""))
+
+	runner := NewTestRunner(config.NewConfig("bin/rails test", "", "", "", false))
+	_, err := runner.LoadResults(1, []string{filepath.Join(rootPath.String(), "missing.yml")})
+	assert.ErrorContains(t, err, "missing.yml")
+}
@@ -0,0 +1,9 @@
+//go:build !unix
+
+package runner
+
+import "os/exec"
+
+// configureProcessGroup is a no-op where process groups aren't available;
+// cancellation only kills the shell.
+func configureProcessGroup(cmd *exec.Cmd) {}
@@ -0,0 +1,18 @@
+//go:build unix
+
+package runner
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// configureProcessGroup starts the command in its own process group and makes
+// cancellation kill the whole group, so test processes spawned by `sh -c` (and
+// their children) don't outlive a cancelled run.
+func configureProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+}
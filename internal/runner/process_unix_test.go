@@ -0,0 +1,78 @@
+//go:build unix
+
+package runner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adamakhtar/wing_commander/internal/config"
+	"github.com/adamakhtar/wing_commander/internal/projectfs"
+	"github.com/adamakhtar/wing_commander/internal/testresult"
+	"github.com/adamakhtar/wing_commander/internal/testrun"
+	"github.com/adamakhtar/wing_commander/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTestRunner_ExecuteTestsCancelKillsProcessGroup(t *testing.T) {
+	rootPath, _ := types.NewAbsPath(t.TempDir())
+	require.NoError(t, projectfs.InitProjectFS(rootPath, ""))
+	pidPath := filepath.Join(rootPath.String(), "sleep.pid")
+
+	// The hung "test" is a grandchild of sh -c, like a test process started by bundle exec
+	command := `printf '%s\n' '` + streamedPass + `' >> "$WING_COMMANDER_STREAM_PATH"; ` +
+		`sh -c 'echo $$ > sleep.pid; exec sleep 30'`
+
+	runner := NewTestRunner(&config.Config{
+		TestFramework:   config.FrameworkMinitest,
+		TestCommand:     command,
+		TestResultsPath: filepath.Join(rootPath.String(), "summary.yml"),
+		TestStreamPath:  "stream.jsonl",
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	started := time.Now()
+	result, err := runner.ExecuteTests(ctx, testrun.TestRun{Id: 3, Mode: string(testrun.ModeRunWholeSuite)}, Hooks{
+		OnResult: func(testresult.TestResult) {
+			// Cancel once the first result has streamed in and the sleep is running
+			go func() {
+				require.Eventually(t, func() bool {
+					_, err := os.Stat(pidPath)
+					return err == nil
+				}, 5*time.Second, 10*time.Millisecond)
+				cancel()
+			}()
+		},
+	})
+
+	require.NoError(t, err)
+	assert.Less(t, time.Since(started), 10*time.Second)
+	assert.True(t, result.Cancelled)
+	assert.Equal(t, 3, result.TestRunId)
+	require.Len(t, result.TestResults, 1)
+	assert.Equal(t, "test_ok", result.TestResults[0].TestCaseName)
+
+	data, err := os.ReadFile(pidPath)
+	require.NoError(t, err)
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool { return !processRunning(pid) }, 5*time.Second, 10*time.Millisecond)
+}
+
+// processRunning reports whether pid is alive, treating zombies as dead.
+func processRunning(pid int) bool {
+	stat, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "stat"))
+	if err != nil {
+		return false
+	}
+	fields := strings.Fields(string(stat))
+	return len(fields) > 2 && fields[2] != "Z"
+}
@@ -0,0 +1,131 @@
+package runner
+
+import (
+	"bytes"
+	"sync"
+)
+
+const (
+	progressStartMarker = "<<START>>"
+	progressEndMarker   = "<<END>>"
+)
+
+// Progress counts the progress markers a reporter has printed so far.
+type Progress struct {
+	Started  bool // <<START>> has been seen
+	Finished bool // <<END>> has been seen
+	Passed   int
+	Failed   int
+	Skipped  int
+}
+
+// Completed returns the number of tests that have reported a result.
+func (p Progress) Completed() int {
+	return p.Passed + p.Failed + p.Skipped
+}
+
+// ProgressFunc is called with the updated counts every time new markers arrive.
+type ProgressFunc func(Progress)
+
+// progressParser incrementally parses `<<START>>PPFS…<<END>>` progress markers
+// from stdout as it is streamed. Markers may be split across writes, so the
+// tail of the previous write that could be the start of a marker is kept.
+type progressParser struct {
+	progress Progress
+	pending  []byte
+}
+
+// Feed parses the next chunk of stdout and reports whether the counts changed.
+func (p *progressParser) Feed(chunk []byte) bool {
+	before := p.progress
+	data := append(p.pending, chunk...)
+	p.pending = nil
+
+	for len(data) > 0 {
+		if !p.progress.Started || p.progress.Finished {
+			// Wait for the next suite, some commands run several
+			idx := bytes.Index(data, []byte(progressStartMarker))
+			if idx == -1 {
+				p.pending = partialMarkerSuffix(data, progressStartMarker)
+				break
+			}
+			p.progress.Started = true
+			p.progress.Finished = false
+			data = data[idx+len(progressStartMarker):]
+			continue
+		}
+
+		if data[0] == '<' {
+			if bytes.HasPrefix(data, []byte(progressEndMarker)) {
+				p.progress.Finished = true
+				data = data[len(progressEndMarker):]
+				continue
+			}
+			if bytes.HasPrefix([]byte(progressEndMarker), data) {
+				p.pending = data
+				break
+			}
+		}
+
+		switch data[0] {
+		case 'P':
+			p.progress.Passed++
+		case 'F':
+			p.progress.Failed++
+		case 'S':
+			p.progress.Skipped++
+		}
+		data = data[1:]
+	}
+
+	// Copy so pending never aliases the caller's buffer
+	p.pending = append([]byte(nil), p.pending...)
+
+	return p.progress != before
+}
+
+// Progress returns the counts parsed so far.
+func (p *progressParser) Progress() Progress {
+	return p.progress
+}
+
+// partialMarkerSuffix returns the longest suffix of data that is a prefix of marker.
+func partialMarkerSuffix(data []byte, marker string) []byte {
+	for n := min(len(marker)-1, len(data)); n > 0; n-- {
+		if bytes.HasSuffix(data, []byte(marker[:n])) {
+			return data[len(data)-n:]
+		}
+	}
+	return nil
+}
+
+// progressWriter feeds stdout to a progressParser and notifies onProgress of changes.
+type progressWriter struct {
+	parser     progressParser
+	onProgress ProgressFunc
+}
+
+func (w *progressWriter) Write(p []byte) (int, error) {
+	if w.parser.Feed(p) && w.onProgress != nil {
+		w.onProgress(w.parser.Progress())
+	}
+	return len(p), nil
+}
+
+// commandOutput collects stdout and stderr, which are written from separate goroutines.
+type commandOutput struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (o *commandOutput) Write(p []byte) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.buf.Write(p)
+}
+
+func (o *commandOutput) String() string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.buf.String()
+}
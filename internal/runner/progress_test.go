@@ -0,0 +1,90 @@
+package runner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/adamakhtar/wing_commander/internal/config"
+	"github.com/adamakhtar/wing_commander/internal/projectfs"
+	"github.com/adamakhtar/wing_commander/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProgressParser_Feed(t *testing.T) {
+	tests := []struct {
+		name   string
+		chunks []string
+		want   Progress
+	}{
+		{
+			name:   "no markers",
+			chunks: []string{"Run options: --seed 1234\n", "PASS"},
+			want:   Progress{},
+		},
+		{
+			name:   "whole run in one write",
+			chunks: []string{"Run options: --seed 1234\n<<START>>\nPPFSP\n<<END>>\n- name: test_total\n"},
+			want:   Progress{Started: true, Finished: true, Passed: 3, Failed: 1, Skipped: 1},
+		},
+		{
+			name:   "markers streamed one test at a time",
+			chunks: []string{"<<START>>\n", "P", "F", "S"},
+			want:   Progress{Started: true, Passed: 1, Failed: 1, Skipped: 1},
+		},
+		{
+			name:   "start marker split across writes",
+			chunks: []string{"Started with run options <<STA", "RT>>\nP", "P"},
+			want:   Progress{Started: true, Passed: 2},
+		},
+		{
+			name:   "end marker split across writes",
+			chunks: []string{"<<START>>\nPF\n<<E", "ND>>\nFailure: PFS"},
+			want:   Progress{Started: true, Finished: true, Passed: 1, Failed: 1},
+		},
+		{
+			name:   "other output between markers is ignored",
+			chunks: []string{"<<START>>\nP<warning> deprecated\nF"},
+			want:   Progress{Started: true, Passed: 1, Failed: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := progressParser{}
+			for _, chunk := range tt.chunks {
+				parser.Feed([]byte(chunk))
+			}
+			assert.Equal(t, tt.want, parser.Progress())
+		})
+	}
+}
+
+func TestProgressParser_FeedReportsChanges(t *testing.T) {
+	parser := progressParser{}
+
+	assert.False(t, parser.Feed([]byte("loading\n")))
+	assert.True(t, parser.Feed([]byte("<<START>>\n")))
+	assert.False(t, parser.Feed([]byte("\n")))
+	assert.True(t, parser.Feed([]byte("P")))
+	assert.Equal(t, 1, parser.Progress().Completed())
+}
+
+func TestTestRunner_ExecuteTestCommandStreamsProgress(t *testing.T) {
+	rootPath, _ := types.NewAbsPath(t.TempDir())
+	require.NoError(t, projectfs.InitProjectFS(rootPath, ""))
+
+	runner := NewTestRunner(&config.Config{})
+
+	var updates []Progress
+	output, exitCode, err := runner.executeTestCommand(context.Background(), `printf '<<START>>\n'; printf P; printf F; echo oops >&2; printf '\n<<END>>\n'`, nil, func(p Progress) {
+		updates = append(updates, p)
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 0, exitCode)
+	assert.Contains(t, output, "oops")
+	assert.Contains(t, output, "<<END>>")
+	require.NotEmpty(t, updates)
+	assert.Equal(t, Progress{Started: true, Finished: true, Passed: 1, Failed: 1}, updates[len(updates)-1])
+}
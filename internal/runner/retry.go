@@ -0,0 +1,104 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/adamakhtar/wing_commander/internal/testresult"
+	"github.com/adamakhtar/wing_commander/internal/testrun"
+	"github.com/charmbracelet/log"
+)
+
+// RetryFailures re-runs the failed tests of a finished test run up to
+// retry_failures times through ModeReRunAllFailures, each time only the tests
+// still failing, and merges every attempt into result. Tests that pass on a
+// retry are marked flaky and count as passed. Cancelled and crashed runs aren't
+// retried. When a retry fails to run, result keeps the attempts merged so far.
+func (r *TestRunner) RetryFailures(ctx context.Context, testRun testrun.TestRun, result *TestExecutionResult, hooks Hooks) error {
+	if result.Cancelled || result.Crashed {
+		return nil
+	}
+
+	for attempt := 1; attempt <= r.config.RetryAttempts(); attempt++ {
+		retryRun, ok := retryTestRun(testRun, result)
+		if !ok {
+			return nil
+		}
+
+		log.Debug("retrying failed tests", "testRunId", testRun.Id, "attempt", attempt, "tests", len(retryRun.Patterns))
+		retried, err := r.ExecuteTests(ctx, retryRun, hooks)
+		if err != nil {
+			return fmt.Errorf("failed to retry failed tests: %w", err)
+		}
+		result.mergeAttempt(retried)
+		if retried.Cancelled || retried.Crashed {
+			return nil
+		}
+	}
+	return nil
+}
+
+// retryTestRun builds a test run re-running the failed tests of result with the
+// original run's ID and seed. Returns false when there is nothing to retry.
+func retryTestRun(testRun testrun.TestRun, result *TestExecutionResult) (testrun.TestRun, bool) {
+	var patterns []testrun.TestPattern
+	for _, failed := range result.FailedTests {
+		pattern, err := testrun.NewTestPatternForResult(failed)
+		if err != nil {
+			// Tests without a file can't be targeted
+			continue
+		}
+		patterns = append(patterns, pattern)
+	}
+	if len(patterns) == 0 {
+		return testrun.TestRun{}, false
+	}
+
+	return testrun.TestRun{
+		Id:       testRun.Id,
+		Patterns: patterns,
+		Mode:     string(testrun.ModeReRunAllFailures),
+		Status:   testrun.StatusRunning,
+		Seed:     testRun.Seed,
+	}, true
+}
+
+// mergeAttempt records a retry's results with the failed tests they re-ran,
+// matched by group, name and file. A test passing on the retry takes the
+// passing result's details, keeping its ID, and is marked flaky.
+func (result *TestExecutionResult) mergeAttempt(retried *TestExecutionResult) {
+	failed := make(map[testresult.Key][]int)
+	for i, test := range result.TestResults {
+		if test.IsFailed() {
+			failed[test.Key()] = append(failed[test.Key()], i)
+		}
+	}
+
+	for _, retry := range retried.TestResults {
+		indexes := failed[retry.Key()]
+		if len(indexes) == 0 {
+			continue
+		}
+		failed[retry.Key()] = indexes[1:]
+
+		original := &result.TestResults[indexes[0]]
+		attempts := original.Attempts
+		if len(attempts) == 0 {
+			attempts = []testresult.Attempt{testresult.NewAttempt(*original)}
+		}
+		attempts = append(attempts, testresult.NewAttempt(retry))
+		if retry.IsPassed() {
+			id := original.Id
+			*original = retry
+			original.Id = id
+			original.Flaky = true
+		}
+		original.Attempts = attempts
+	}
+
+	merged := NewTestExecutionResult(result.TestRunId, result.TestResults)
+	result.PassedTests = merged.PassedTests
+	result.FailedTests = merged.FailedTests
+	result.SkippedTests = merged.SkippedTests
+	result.Metrics = merged.Metrics
+}
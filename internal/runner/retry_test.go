@@ -0,0 +1,121 @@
+package runner
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/adamakhtar/wing_commander/internal/config"
+	"github.com/adamakhtar/wing_commander/internal/projectfs"
+	"github.com/adamakhtar/wing_commander/internal/testresult"
+	"github.com/adamakhtar/wing_commander/internal/testrun"
+	"github.com/adamakhtar/wing_commander/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func retryResult(id int, name string, status testresult.TestStatus, details string) testresult.TestResult {
+	result := testresult.NewTestResult("WorkerTest", name, status)
+	result.Id = id
+	result.TestFilePath = types.AbsPath("/project/test/worker_test.rb")
+	result.TestLineNumber = id
+	result.Duration = 0.5
+	if status == testresult.StatusFail {
+		result.FailureCause = testresult.FailureCauseAssertion
+		result.FailureDetails = details
+	}
+	return result
+}
+
+func TestTestExecutionResult_MergeAttempt(t *testing.T) {
+	result := NewTestExecutionResult(1, []testresult.TestResult{
+		retryResult(1, "test_ok", testresult.StatusPass, ""),
+		retryResult(2, "test_flaky", testresult.StatusFail, "Timed out"),
+		retryResult(3, "test_broken", testresult.StatusFail, "Expected: 1"),
+	})
+
+	result.mergeAttempt(NewTestExecutionResult(1, []testresult.TestResult{
+		retryResult(1, "test_flaky", testresult.StatusFail, "Connection refused"),
+		retryResult(2, "test_broken", testresult.StatusFail, "Expected: 1"),
+	}))
+	result.mergeAttempt(NewTestExecutionResult(1, []testresult.TestResult{
+		retryResult(1, "test_flaky", testresult.StatusPass, ""),
+		retryResult(2, "test_broken", testresult.StatusFail, "Expected: 1"),
+	}))
+
+	flaky := result.TestResults[1]
+	assert.Equal(t, 2, flaky.Id, "keeps the original ID")
+	assert.True(t, flaky.IsPassed())
+	assert.True(t, flaky.IsFlaky())
+	assert.Empty(t, flaky.FailureDetails)
+	require.Len(t, flaky.Attempts, 3)
+	assert.Equal(t, "Timed out", flaky.Attempts[0].FailureDetails)
+	assert.Equal(t, "Connection refused", flaky.Attempts[1].FailureDetails)
+	assert.Equal(t, testresult.StatusPass, flaky.Attempts[2].Status)
+
+	broken := result.TestResults[2]
+	assert.True(t, broken.IsFailed())
+	assert.False(t, broken.IsFlaky())
+	assert.Len(t, broken.Attempts, 3)
+
+	assert.Empty(t, result.TestResults[0].Attempts)
+	assert.Equal(t, Metrics{TotalTests: 3, PassedTests: 2, FailedTests: 1, FlakyTests: 1}, result.Metrics)
+	require.Len(t, result.FailedTests, 1)
+	assert.Equal(t, "test_broken", result.FailedTests[0].TestCaseName)
+}
+
+const failingSummaryYAML = `- test_group_name: WorkerTest
+  test_case_name: test_flaky
+  test_status: failed
+  duration: "0.01"
+  test_file_path: test/worker_test.rb
+  test_line_number: 3
+  failure_details: "Timed out"
+`
+
+const passingSummaryYAML = `- test_group_name: WorkerTest
+  test_case_name: test_flaky
+  test_status: passed
+  duration: "0.02"
+  test_file_path: test/worker_test.rb
+  test_line_number: 3
+`
+
+func TestTestRunner_RetryFailures(t *testing.T) {
+	rootPath, _ := types.NewAbsPath(t.TempDir())
+	require.NoError(t, projectfs.InitProjectFS(rootPath, ""))
+	summaryPath := filepath.Join(rootPath.String(), "summary.yml")
+
+	// Retries write to the summary with the re-run command, arguments are commented out
+	runner := NewTestRunner(&config.Config{
+		TestFramework:      config.FrameworkMinitest,
+		TestCommand:        "printf '%s' '" + failingSummaryYAML + "' > summary.yml; exit 1 #",
+		RunTestCaseCommand: "printf '%s' '" + passingSummaryYAML + "' > summary.yml; exit 0 #",
+		TestResultsPath:    summaryPath,
+		TestStreamPath:     "stream.jsonl",
+		RetryFailures:      2,
+	})
+	testRun := testrun.TestRun{Id: 1, Mode: string(testrun.ModeRunWholeSuite)}
+
+	result, err := runner.ExecuteTests(context.Background(), testRun, Hooks{})
+	require.NoError(t, err)
+	require.Equal(t, 1, result.Metrics.FailedTests)
+
+	require.NoError(t, runner.RetryFailures(context.Background(), testRun, result, Hooks{}))
+
+	assert.Equal(t, Metrics{TotalTests: 1, PassedTests: 1, FlakyTests: 1}, result.Metrics)
+	flaky := result.TestResults[0]
+	assert.True(t, flaky.IsFlaky())
+	require.Len(t, flaky.Attempts, 2, "stops retrying once nothing fails")
+	assert.Equal(t, "Timed out", flaky.Attempts[0].FailureDetails)
+}
+
+func TestTestRunner_RetryFailuresSkipsCrashedRuns(t *testing.T) {
+	runner := NewTestRunner(&config.Config{TestFramework: config.FrameworkMinitest, RetryFailures: 3})
+	result := NewTestExecutionResult(1, []testresult.TestResult{retryResult(1, "test_broken", testresult.StatusFail, "boom")})
+	result.Crashed = true
+
+	require.NoError(t, runner.RetryFailures(context.Background(), testrun.TestRun{Id: 1}, result, Hooks{}))
+
+	assert.Empty(t, result.TestResults[0].Attempts)
+}
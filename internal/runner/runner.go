@@ -1,13 +1,21 @@
 package runner
 
 import (
+	"cmp"
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/adamakhtar/wing_commander/internal/config"
+	"github.com/adamakhtar/wing_commander/internal/framework"
 	"github.com/adamakhtar/wing_commander/internal/parser"
 	"github.com/adamakhtar/wing_commander/internal/projectfs"
 	"github.com/adamakhtar/wing_commander/internal/testresult"
@@ -27,29 +35,128 @@ func NewTestRunner(cfg *config.Config) *TestRunner {
 	}
 }
 
-// ExecuteTests runs the configured test command and returns parsed results
-func (r *TestRunner) ExecuteTests(testRun testrun.TestRun) (*TestExecutionResult, error) {
-	// Execute the test command
-	output, err := r.executeTestCommand(testRun)
+// commandWaitDelay bounds how long a killed command's output is waited for.
+const commandWaitDelay = 2 * time.Second
+
+// Hooks are called during a test run. All are optional.
+type Hooks struct {
+	OnProgress ProgressFunc // Called as the reporter's progress markers stream in
+	OnResult   ResultFunc   // Called for every result appended to the results stream
+	OnStalled  StalledFunc  // Called when started tests stop reporting for the hung test timeout
+	OnSummary  SummaryFunc  // Called with the fresh summary file before it is parsed and removed
+}
+
+// SummaryFunc receives the path of the summary file a run wrote.
+type SummaryFunc func(path string)
+
+// ExecuteTests runs the configured test command and returns parsed results.
+// When the command crashes, exits with a code outside the exit code policy or
+// leaves no fresh summary behind, the results are returned with a synthetic
+// "suite crashed" result instead of an error. Cancelling ctx kills the command
+// and returns the streamed results marked as cancelled.
+func (r *TestRunner) ExecuteTests(ctx context.Context, testRun testrun.TestRun, hooks Hooks) (*TestExecutionResult, error) {
+	adapter, err := framework.Get(r.config.TestFramework)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute test command: %w", err)
+		return nil, err
 	}
 
-	var testResults []testresult.TestResult
-
-	// Parse YAML summary file
-	parseOpts := &parser.ParseOptions{}
+	target := newExecutionTarget(r.config.ExecutionTarget)
+	commandStr, err := framework.BuildTestRunCommand(adapter, r.commandTemplate(testRun), target.testRun(testRun))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build test command: %w", err)
+	}
+	summaryPath := r.summaryPath(testRun)
+	var rubyOpt string
+	if r.injectsReporter() {
+		opt, cleanup, err := r.injectReporter(target)
+		if err != nil {
+			return nil, err
+		}
+		defer cleanup()
+		rubyOpt = opt
+	}
+	env := r.commandEnv(summaryPath, rubyOpt)
+	commandStr = target.command(commandStr, env)
+	target.mapResultsBack()
+
+	parseOptions := framework.ParseOptions(adapter)
+	stream := newResultStream(r.streamPath(), parseOptions, hooks.OnResult)
+	stream.detectHungTests(r.config.HungTestTimeout(), hooks.OnStalled)
+	if err := stream.reset(); err != nil {
+		return nil, err
+	}
 
-	parsed, err := parser.ParseFile(r.config.TestResultsPath, parseOpts)
+	// Execute the test command, tailing the results stream while it runs
+	startedAt := time.Now()
+	done := make(chan struct{})
+	followed := make(chan struct{})
+	go func() {
+		stream.follow(done)
+		close(followed)
+	}()
+	output, exitCode, err := r.executeTestCommand(ctx, commandStr, env, hooks.OnProgress)
+	close(done)
+	<-followed
+	if ctx.Err() != nil {
+		log.Debug("test run cancelled", "testRunId", testRun.Id, "streamedResults", len(stream.Results()))
+		result := NewTestExecutionResult(testRun.Id, stream.Results())
+		result.CommandOutput = output
+		result.Cancelled = true
+		return result, nil
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse test output summary: %w", err)
+		return nil, fmt.Errorf("failed to execute test command: %w", err)
+	}
+
+	if summaryPath != r.resultsPath() {
+		if _, err := os.Stat(summaryPath); err == nil {
+			defer os.Remove(summaryPath)
+		} else {
+			// The project registered the reporter itself with a fixed summary path
+			summaryPath = r.resultsPath()
+		}
+	}
+
+	exitErr := r.checkExitCode(exitCode, commandStr)
+	summaryErr := r.checkSummaryWritten(adapter, summaryPath, startedAt)
+	var parsed *parser.ParseResult
+	var parseErr error
+	if summaryErr == nil {
+		if hooks.OnSummary != nil && framework.ReadsSummaryFile(adapter) {
+			hooks.OnSummary(summaryPath)
+		}
+		parsed, parseErr = adapter.ParseResults(framework.Output{
+			ResultsPath:   summaryPath,
+			CommandOutput: output,
+		})
+		if parseErr != nil {
+			parseErr = fmt.Errorf("failed to parse test output summary: %w", parseErr)
+		}
 	}
-	testResults = parsed.Tests
 
 	// Normalize backtraces
-	normalizer := testresult.NewNormalizer()
-	normalizedResults := normalizer.NormalizeTestResults(testResults)
+	var normalizedResults []testresult.TestResult
+	if parsed != nil {
+		normalizer := testresult.NewNormalizer()
+		normalizedResults = normalizer.NormalizeTestResults(parsed.Tests)
+	}
+
+	if crashErr := cmp.Or(exitErr, summaryErr, parseErr); crashErr != nil {
+		if parsed == nil {
+			// Keep what was streamed before the crash
+			normalizedResults = stream.Results()
+		}
+		return crashedTestExecutionResult(testRun.Id, normalizedResults, output, crashErr, parseOptions), nil
+	}
+
+	result := NewTestExecutionResult(testRun.Id, normalizedResults)
+	result.CommandOutput = output
+	return result, nil
+}
 
+// NewTestExecutionResult partitions normalized results by status and
+// calculates their metrics.
+func NewTestExecutionResult(testRunId int, normalizedResults []testresult.TestResult) *TestExecutionResult {
 	// Partition results by status (no backtrace grouping)
 	var passedTests []testresult.TestResult
 	var failedTests []testresult.TestResult
@@ -66,69 +173,152 @@ func (r *TestRunner) ExecuteTests(testRun testrun.TestRun) (*TestExecutionResult
 		}
 	}
 
-	metrics := calculateMetrics(normalizedResults)
-
 	return &TestExecutionResult{
-		TestRunId:     testRun.Id,
+		TestRunId:     testRunId,
 		TestResults:   normalizedResults,
-		Metrics:       metrics,
+		Metrics:       calculateMetrics(normalizedResults),
 		PassedTests:   passedTests,
 		FailedTests:   failedTests,
 		SkippedTests:  skippedTests,
 		ExecutionTime: time.Now(),
-		CommandOutput: output,
-	}, nil
+	}
+}
+
+// crashedTestExecutionResult keeps the results of a run that crashed, adding a
+// synthetic "suite crashed" result holding the command output and any load
+// error backtrace found in it.
+func crashedTestExecutionResult(testRunId int, results []testresult.TestResult, output string, err error, opts *parser.ParseOptions) *TestExecutionResult {
+	log.Warn("test suite crashed", "results", len(results), "error", err)
+
+	crashed := parser.NewSuiteCrashedResult(len(results)+1, err.Error(), output, opts)
+	crashed = testresult.NewNormalizer().NormalizeTestResults([]testresult.TestResult{crashed})[0]
+
+	result := NewTestExecutionResult(testRunId, append(slices.Clone(results), crashed))
+	result.CommandOutput = output
+	result.Crashed = true
+	result.CrashReason = err.Error()
+	return result
+}
+
+// checkExitCode applies the exit code policy: 0 means the suite passed, the
+// configured test failure codes mean it ran with failures and anything else
+// means it crashed.
+func (r *TestRunner) checkExitCode(exitCode int, commandStr string) error {
+	if exitCode == 0 || slices.Contains(r.config.FailureExitCodes(), exitCode) {
+		return nil
+	}
+	return fmt.Errorf("test command exited with code %d, expected 0 or one of the test failure exit codes %v\nCommand: %s",
+		exitCode, r.config.FailureExitCodes(), commandStr)
 }
 
-// executeTestCommand runs the configured test command and returns the output
-func (r *TestRunner) executeTestCommand(testRun testrun.TestRun) (string, error) {
-	commandStr, err := BuildRunTestCaseCommand(r.config.TestCommand, testRun)
+// checkSummaryWritten returns an error when the adapter reads a summary file
+// and the command didn't write one at resultsPath during this run.
+func (r *TestRunner) checkSummaryWritten(adapter framework.Adapter, resultsPath string, startedAt time.Time) error {
+	if !framework.ReadsSummaryFile(adapter) {
+		return nil
+	}
+
+	info, err := os.Stat(resultsPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("test command did not write the summary file %s", resultsPath)
+	}
 	if err != nil {
-		return "", fmt.Errorf("failed to build test command: %w", err)
+		return fmt.Errorf("failed to read the summary file %s: %w", resultsPath, err)
+	}
+	// Allow for file systems that store modification times in whole seconds
+	if info.ModTime().Before(startedAt.Truncate(time.Second)) {
+		return fmt.Errorf("summary file %s was not updated by the test command, it is older than the run", resultsPath)
+	}
+	return nil
+}
+
+// commandTemplate returns run_test_case_command for re-runs of specific test
+// cases and test_command for everything else.
+func (r *TestRunner) commandTemplate(testRun testrun.TestRun) string {
+	if testRun.IsRunningSpecificTestCases() && r.config.RunTestCaseCommand != "" {
+		return r.config.RunTestCaseCommand
+	}
+	return r.config.TestCommand
+}
+
+// streamPath returns the absolute path reporters append streamed results to.
+func (r *TestRunner) streamPath() string {
+	return projectPath(r.config.TestStreamPath)
+}
+
+// resultsPath returns the absolute path of the summary file.
+func (r *TestRunner) resultsPath() string {
+	return projectPath(r.config.TestResultsPath)
+}
+
+// projectPath resolves a configured path against the project root.
+func projectPath(path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(projectfs.GetProjectFS().RootPath.String(), path)
+}
+
+// commandEnv returns the variables telling reporters where to write, and the
+// RUBYOPT loading an injected reporter, as "KEY=value" pairs. Unset values are
+// left out so reporters use their defaults.
+func (r *TestRunner) commandEnv(summaryPath string, rubyOpt string) []string {
+	var env []string
+	if path := r.streamPath(); path != "" {
+		env = append(env, streamPathEnvVar+"="+path)
+	}
+	if summaryPath != "" {
+		env = append(env, summaryPathEnvVar+"="+summaryPath)
 	}
+	if rubyOpt != "" {
+		env = append(env, rubyOptEnvVar+"="+rubyOpt)
+	}
+	return env
+}
 
+// executeTestCommand runs the built test command and returns its output and
+// exit code. env is added to the environment. Stdout is streamed through a
+// progress parser while the command runs. Errors are only returned when the command couldn't be run at all; how
+// to treat the exit code is up to the caller.
+func (r *TestRunner) executeTestCommand(ctx context.Context, commandStr string, env []string, onProgress ProgressFunc) (string, int, error) {
 	log.Debug("executeTestCommand", "command", commandStr)
 	// Execute via shell to handle multi-word commands like "bundle exec rake test"
-	cmd := exec.Command("sh", "-c", commandStr)
+	cmd := exec.CommandContext(ctx, "sh", "-c", commandStr)
+	configureProcessGroup(cmd)
+	// Don't wait forever on output pipes held open by processes that escaped the group
+	cmd.WaitDelay = commandWaitDelay
 
 	// Set working directory to project path
 	fs := projectfs.GetProjectFS()
 	cmd.Dir = fs.RootPath.String()
+	cmd.Env = append(os.Environ(), env...)
 	// Execute command and capture output
-	output, err := cmd.CombinedOutput()
+	output := &commandOutput{}
+	cmd.Stdout = io.MultiWriter(output, &progressWriter{onProgress: onProgress})
+	cmd.Stderr = output
+	err := cmd.Run()
 	if err != nil {
 		// Check if it's a command not found error (when sh itself is not found)
 		if execErr, ok := err.(*exec.Error); ok && execErr.Err == exec.ErrNotFound {
-			return "", fmt.Errorf("test command not found: %s (make sure it's installed and in PATH)", commandStr)
+			return "", -1, fmt.Errorf("test command not found: %s (make sure it's installed and in PATH)", commandStr)
 		}
 
 		// Check if it's a permission error
 		if execErr, ok := err.(*exec.Error); ok && execErr.Err == os.ErrPermission {
-			return "", fmt.Errorf("permission denied running test command: %s", commandStr)
+			return "", -1, fmt.Errorf("permission denied running test command: %s", commandStr)
 		}
 
-		// Check if it's an exit error (command ran but returned non-zero)
+		// The command ran but returned non-zero, which the exit code policy decides on
 		if exitErr, ok := err.(*exec.ExitError); ok {
-			exitCode := -1
-			if exitErr.ProcessState != nil {
-				exitCode = exitErr.ExitCode()
-			}
-			// Exit code 1 typically means tests failed, which is normal - we still have valid output
-			// Only return error if exit code suggests a real problem (not 1)
-			if exitCode != 1 {
-				return "", fmt.Errorf("test command failed (exit code %d): %w\nCommand: %s\nOutput: %s",
-					exitCode, err, commandStr, string(output))
-			}
-			// Exit code 1 (test failures) - return the output so it can be parsed
-			return string(output), nil
+			return output.String(), exitErr.ExitCode(), nil
 		}
 
 		// Unknown error type
-		return "", fmt.Errorf("unexpected error executing test command: %w\nCommand: %s\nOutput: %s",
-			err, commandStr, string(output))
+		return output.String(), -1, fmt.Errorf("unexpected error executing test command: %w\nCommand: %s\nOutput: %s",
+			err, commandStr, output.String())
 	}
 
-	return string(output), nil
+	return output.String(), 0, nil
 }
 
 // TestExecutionResult represents the complete result of a test execution
@@ -141,6 +331,9 @@ type TestExecutionResult struct {
 	ExecutionTime time.Time                // When the tests were executed
 	Metrics       Metrics                  // Metrics of the test execution
 	CommandOutput string                   // Raw output from test command
+	Cancelled     bool                     // The run was cancelled, results are those streamed before it was killed
+	Crashed       bool                     // The suite crashed, results end with a synthetic "suite crashed" result
+	CrashReason   string                   // Why the run was considered crashed
 }
 
 // GetSummary returns a summary of the test execution
@@ -149,8 +342,12 @@ func calculateMetrics(testResults []testresult.TestResult) Metrics {
 	failedTests := 0
 	passedTests := 0
 	skippedTests := 0
+	flakyTests := 0
 
 	for _, result := range testResults {
+		if result.IsFlaky() {
+			flakyTests++
+		}
 		switch result.Status {
 		case testresult.StatusFail:
 			failedTests++
@@ -166,6 +363,7 @@ func calculateMetrics(testResults []testresult.TestResult) Metrics {
 		FailedTests:  failedTests,
 		PassedTests:  passedTests,
 		SkippedTests: skippedTests,
+		FlakyTests:   flakyTests,
 	}
 }
 
@@ -175,6 +373,7 @@ type Metrics struct {
 	FailedTests  int
 	PassedTests  int
 	SkippedTests int
+	FlakyTests   int // Passed on a retry after failing, also counted as passed
 }
 
 // ValidateConfig checks if the test configuration is valid
@@ -187,8 +386,8 @@ func (r *TestRunner) ValidateConfig() error {
 		return fmt.Errorf("test_command must be specified either via CLI option --test-command or in config file")
 	}
 
-	if r.config.TestFramework != config.FrameworkMinitest {
-		return fmt.Errorf("unsupported test framework: %s (Wing Commander currently requires WingCommanderReporter YAML output)", r.config.TestFramework)
+	if _, err := framework.Get(r.config.TestFramework); err != nil {
+		return err
 	}
 
 	return nil
@@ -199,18 +398,51 @@ func (r *TestRunner) GetWorkingDirectory() (string, error) {
 	return os.Getwd()
 }
 
-// CheckTestCommandExists verifies that the test command can be found
+// CheckTestCommandExists verifies that the test command can be found.
+// Commands run through a command prefix are only found inside the target, so
+// the prefix is checked instead.
 func (r *TestRunner) CheckTestCommandExists() error {
-	parts := strings.Fields(r.config.TestCommand)
-	if len(parts) == 0 {
+	if !r.config.ExecutionTarget.IsLocal() {
+		prefix := strings.Fields(r.config.ExecutionTarget.CommandPrefix)
+		if _, err := exec.LookPath(prefix[0]); err != nil {
+			return fmt.Errorf("command prefix not found in PATH: %s", prefix[0])
+		}
+		return nil
+	}
+
+	command := commandName(r.config.TestCommand)
+	if command == "" {
 		return fmt.Errorf("no test command configured")
 	}
 
+	// Commands like bin/test are run from the project root
+	if strings.Contains(command, "/") && !filepath.IsAbs(command) {
+		path := filepath.Join(projectfs.GetProjectFS().RootPath.String(), command)
+		if _, err := exec.LookPath(path); err != nil {
+			return fmt.Errorf("test command not found in the project: %s", path)
+		}
+		return nil
+	}
+
 	// Check if the command exists in PATH
-	_, err := exec.LookPath(parts[0])
+	_, err := exec.LookPath(command)
 	if err != nil {
-		return fmt.Errorf("test command not found in PATH: %s", parts[0])
+		return fmt.Errorf("test command not found in PATH: %s", command)
 	}
 
 	return nil
 }
+
+// envAssignment matches the VAR=value words a shell command may start with.
+var envAssignment = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*=`)
+
+// commandName returns the program a shell command runs, skipping leading
+// environment variable assignments.
+func commandName(commandStr string) string {
+	for _, field := range strings.Fields(commandStr) {
+		if !envAssignment.MatchString(field) {
+			return field
+		}
+	}
+	return ""
+}
@@ -1,19 +1,26 @@
 package runner
 
 import (
+	"context"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/adamakhtar/wing_commander/internal/config"
+	"github.com/adamakhtar/wing_commander/internal/parser"
+	"github.com/adamakhtar/wing_commander/internal/projectfs"
 	"github.com/adamakhtar/wing_commander/internal/testresult"
+	"github.com/adamakhtar/wing_commander/internal/testrun"
 	"github.com/adamakhtar/wing_commander/internal/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewTestRunner(t *testing.T) {
 	cfg := &config.Config{
-		TestFramework: config.FrameworkRSpec,
-		TestCommand:   "bundle exec rspec --format json",
+		TestFramework: config.FrameworkMinitest,
+		TestCommand:   "bin/test",
 	}
 
 	runner := NewTestRunner(cfg)
@@ -33,7 +40,7 @@ func TestTestRunner_ValidateConfig(t *testing.T) {
 			name: "Valid RSpec config",
 			config: &config.Config{
 				TestFramework: config.FrameworkRSpec,
-				TestCommand:   "bundle exec rspec --format json",
+				TestCommand:   "bundle exec rspec",
 			},
 			expectError: false,
 		},
@@ -48,7 +55,7 @@ func TestTestRunner_ValidateConfig(t *testing.T) {
 		{
 			name: "Missing test framework",
 			config: &config.Config{
-				TestCommand: "bundle exec rspec --format json",
+				TestCommand: "bin/test",
 			},
 			expectError: true,
 			errorMsg:    "test_framework not specified in config",
@@ -56,7 +63,7 @@ func TestTestRunner_ValidateConfig(t *testing.T) {
 		{
 			name: "Missing test command",
 			config: &config.Config{
-				TestFramework: "rspec",
+				TestFramework: config.FrameworkMinitest,
 			},
 			expectError: true,
 			errorMsg:    "test_command must be specified either via CLI option --test-command or in config file",
@@ -64,7 +71,7 @@ func TestTestRunner_ValidateConfig(t *testing.T) {
 		{
 			name: "Unsupported test framework",
 			config: &config.Config{
-				TestFramework: config.FrameworkUnknown,
+				TestFramework: config.TestFramework("unknown"),
 				TestCommand:   "some command",
 			},
 			expectError: true,
@@ -114,6 +121,21 @@ func TestTestRunner_CheckTestCommandExists(t *testing.T) {
 			},
 			expectError: true,
 		},
+		{
+			name: "Environment variables before the command",
+			config: &config.Config{
+				TestCommand: "RAILS_ENV=test ls",
+			},
+			expectError: false,
+		},
+		{
+			name: "Command prefix is checked instead of the command",
+			config: &config.Config{
+				TestCommand:     "bin/rails test",
+				ExecutionTarget: config.ExecutionTarget{CommandPrefix: "nonexistentcommand12345 exec web"},
+			},
+			expectError: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -130,6 +152,18 @@ func TestTestRunner_CheckTestCommandExists(t *testing.T) {
 	}
 }
 
+func TestTestRunner_CheckTestCommandExistsInProject(t *testing.T) {
+	rootPath, _ := types.NewAbsPath(t.TempDir())
+	require.NoError(t, projectfs.InitProjectFS(rootPath, ""))
+	runner := NewTestRunner(&config.Config{TestCommand: "bin/test"})
+
+	assert.ErrorContains(t, runner.CheckTestCommandExists(), "test command not found in the project")
+
+	require.NoError(t, os.MkdirAll(filepath.Join(rootPath.String(), "bin"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(rootPath.String(), "bin", "test"), []byte("#!/bin/sh\n"), 0o755))
+	assert.NoError(t, runner.CheckTestCommandExists())
+}
+
 func TestTestRunner_GetWorkingDirectory(t *testing.T) {
 	runner := NewTestRunner(&config.Config{})
 
@@ -173,77 +207,144 @@ func TestTestExecutionResult_EmptyResults(t *testing.T) {
 	assert.Equal(t, 0, summary.SkippedTests)
 }
 
-func TestTestRunner_ParseTestOutput_RSpec(t *testing.T) {
+func TestTestRunner_CommandTemplate(t *testing.T) {
 	runner := NewTestRunner(&config.Config{
-		TestFramework: config.FrameworkRSpec,
+		TestFramework:      config.FrameworkMinitest,
+		TestCommand:        "bin/rails test %{paths}",
+		RunTestCaseCommand: "bin/rails test %{locations}",
 	})
 
-	// Mock RSpec XML output
-	rspecXML := `<?xml version="1.0" encoding="UTF-8"?>
-<testsuites>
-  <testsuite name="RSpec" tests="2" failures="1" skipped="0" time="0.3">
-    <testcase classname="TestSpec" name="should pass" time="0.1">
-    </testcase>
-    <testcase classname="TestSpec" name="should fail" time="0.2">
-      <failure message="Expected true to be false">
-        spec/test_spec.rb:10:in 'block (2 levels) in &lt;top (required)&gt;'
-      </failure>
-    </testcase>
-  </testsuite>
-</testsuites>`
-
-	results, err := runner.parseTestOutput(rspecXML)
+	assert.Equal(t, "bin/rails test %{paths}", runner.commandTemplate(testrun.TestRun{Mode: string(testrun.ModeRunWholeSuite)}))
+	assert.Equal(t, "bin/rails test %{paths}", runner.commandTemplate(testrun.TestRun{Mode: string(testrun.ModeRunSelectedPatterns)}))
+	assert.Equal(t, "bin/rails test %{locations}", runner.commandTemplate(testrun.TestRun{Mode: string(testrun.ModeReRunSingleFailure)}))
+	assert.Equal(t, "bin/rails test %{locations}", runner.commandTemplate(testrun.TestRun{Mode: string(testrun.ModeReRunAllFailures)}))
+}
+
+const summaryYAML = `- test_group_name: WorkerTest
+  test_case_name: test_ok
+  test_status: passed
+  duration: "0.01"
+`
 
-	assert.NoError(t, err)
-	assert.Len(t, results, 2)
-	assert.Equal(t, "TestSpec", results[0].GroupName)
-	assert.Equal(t, "should pass", results[0].TestCaseName)
-	assert.Equal(t, types.StatusPass, results[0].Status)
-	assert.Equal(t, "TestSpec", results[1].GroupName)
-	assert.Equal(t, "should fail", results[1].TestCaseName)
-	assert.Equal(t, types.StatusFail, results[1].Status)
+func TestTestRunner_ExecuteTestsExitCodePolicy(t *testing.T) {
+	tests := []struct {
+		name          string
+		exitCode      string
+		failureCodes  []int
+		wantCrashed   bool
+		wantReason    string
+		wantTotalRuns int
+	}{
+		{name: "passed", exitCode: "0", wantTotalRuns: 1},
+		{name: "default test failure code", exitCode: "1", wantTotalRuns: 1},
+		{name: "configured test failure code", exitCode: "5", failureCodes: []int{1, 5}, wantTotalRuns: 1},
+		{name: "crash code", exitCode: "3", wantCrashed: true, wantReason: "exited with code 3", wantTotalRuns: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rootPath, _ := types.NewAbsPath(t.TempDir())
+			require.NoError(t, projectfs.InitProjectFS(rootPath, ""))
+			summaryPath := filepath.Join(rootPath.String(), "summary.yml")
+
+			runner := NewTestRunner(&config.Config{
+				TestFramework:        config.FrameworkMinitest,
+				TestCommand:          "printf '%s' '" + summaryYAML + "' > summary.yml; exit " + tt.exitCode,
+				TestResultsPath:      summaryPath,
+				TestStreamPath:       "stream.jsonl",
+				TestFailureExitCodes: tt.failureCodes,
+			})
+
+			result, err := runner.ExecuteTests(context.Background(), testrun.TestRun{Id: 1, Mode: string(testrun.ModeRunWholeSuite)}, Hooks{})
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantCrashed, result.Crashed)
+			assert.Contains(t, result.CrashReason, tt.wantReason)
+			// A crash keeps the summary's results
+			assert.Equal(t, tt.wantTotalRuns, result.Metrics.TotalTests)
+			assert.Equal(t, "test_ok", result.TestResults[0].TestCaseName)
+		})
+	}
 }
 
-func TestTestRunner_ParseTestOutput_Minitest(t *testing.T) {
+func TestTestRunner_ExecuteTestsOnSummaryHook(t *testing.T) {
+	rootPath, _ := types.NewAbsPath(t.TempDir())
+	require.NoError(t, projectfs.InitProjectFS(rootPath, ""))
+	summaryPath := filepath.Join(rootPath.String(), "summary.yml")
+
 	runner := NewTestRunner(&config.Config{
-		TestFramework: config.FrameworkMinitest,
+		TestFramework:   config.FrameworkMinitest,
+		TestCommand:     "printf '%s' '" + summaryYAML + "' > summary.yml",
+		TestResultsPath: summaryPath,
+		TestStreamPath:  "stream.jsonl",
 	})
 
-	// Mock Minitest XML output
-	minitestXML := `<?xml version="1.0" encoding="UTF-8"?>
-<testsuites>
-  <testsuite name="Minitest" tests="2" failures="1" skipped="0" time="0.3">
-    <testcase classname="TestClass" name="test_pass" time="0.1">
-    </testcase>
-    <testcase classname="TestClass" name="test_fail" time="0.2">
-      <failure message="Expected true to be false">
-        test/test_class.rb:10:in 'test_fail'
-      </failure>
-    </testcase>
-  </testsuite>
-</testsuites>`
-
-	results, err := runner.parseTestOutput(minitestXML)
+	var summary []byte
+	hooks := Hooks{OnSummary: func(path string) {
+		summary, _ = os.ReadFile(path)
+	}}
+	_, err := runner.ExecuteTests(context.Background(), testrun.TestRun{Id: 1, Mode: string(testrun.ModeRunWholeSuite)}, hooks)
 
-	assert.NoError(t, err)
-	assert.Len(t, results, 2)
-	assert.Equal(t, "TestClass", results[0].GroupName)
-	assert.Equal(t, "test_pass", results[0].TestCaseName)
-	assert.Equal(t, types.StatusPass, results[0].Status)
-	assert.Equal(t, "TestClass", results[1].GroupName)
-	assert.Equal(t, "test_fail", results[1].TestCaseName)
-	assert.Equal(t, types.StatusFail, results[1].Status)
+	require.NoError(t, err)
+	assert.Equal(t, summaryYAML, string(summary))
 }
 
-func TestTestRunner_ParseTestOutput_InvalidXML(t *testing.T) {
+func TestTestRunner_ExecuteTestsLoadErrorIsSuiteCrash(t *testing.T) {
+	rootPath, _ := types.NewAbsPath(t.TempDir())
+	require.NoError(t, projectfs.InitProjectFS(rootPath, ""))
+	require.NoError(t, os.MkdirAll(filepath.Join(rootPath.String(), "test"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(rootPath.String(), "test", "test_helper.rb"), []byte("require 'missing'\n"), 0o644))
+
+	// A load error in test_helper.rb exits 1 like failing tests but never writes the summary
+	command := `echo "test/test_helper.rb:1:in 'Kernel#require': cannot load such file -- missing (LoadError)" >&2; ` +
+		`printf '\tfrom test/user_test.rb:1:in '"'"'<main>'"'"'\n' >&2; exit 1`
+
 	runner := NewTestRunner(&config.Config{
-		TestFramework: config.FrameworkRSpec,
+		TestFramework:   config.FrameworkMinitest,
+		TestCommand:     command,
+		TestResultsPath: filepath.Join(rootPath.String(), "summary.yml"),
+		TestStreamPath:  "stream.jsonl",
 	})
 
-	invalidXML := `<?xml version="1.0"?><invalid>xml`
+	result, err := runner.ExecuteTests(context.Background(), testrun.TestRun{Id: 1, Mode: string(testrun.ModeRunWholeSuite)}, Hooks{})
+
+	require.NoError(t, err)
+	assert.True(t, result.Crashed)
+	assert.Contains(t, result.CrashReason, "did not write the summary file")
+	require.Len(t, result.TestResults, 1)
+
+	crashed := result.TestResults[0]
+	assert.Equal(t, parser.SuiteCrashedGroupName, crashed.GroupName)
+	assert.Equal(t, testresult.StatusFail, crashed.Status)
+	assert.Contains(t, crashed.FailureDetails, "cannot load such file -- missing (LoadError)")
+	assert.Contains(t, crashed.Output, "LoadError")
+	assert.Equal(t, filepath.Join(rootPath.String(), "test", "test_helper.rb"), crashed.TestFilePath.String())
+	assert.Equal(t, 1, crashed.TestLineNumber)
+	assert.Len(t, crashed.FullBacktrace.Frames, 2)
+}
+
+func TestTestRunner_ExecuteTestsStaleSummaryIsSuiteCrash(t *testing.T) {
+	rootPath, _ := types.NewAbsPath(t.TempDir())
+	require.NoError(t, projectfs.InitProjectFS(rootPath, ""))
+
+	// Summary left behind by an earlier run
+	summaryPath := filepath.Join(rootPath.String(), "summary.yml")
+	require.NoError(t, os.WriteFile(summaryPath, []byte(summaryYAML), 0o644))
+	earlier := time.Now().Add(-time.Hour)
+	require.NoError(t, os.Chtimes(summaryPath, earlier, earlier))
+
+	runner := NewTestRunner(&config.Config{
+		TestFramework:   config.FrameworkMinitest,
+		TestCommand:     "exit 0",
+		TestResultsPath: summaryPath,
+		TestStreamPath:  "stream.jsonl",
+	})
 
-	_, err := runner.parseTestOutput(invalidXML)
+	result, err := runner.ExecuteTests(context.Background(), testrun.TestRun{Id: 1, Mode: string(testrun.ModeRunWholeSuite)}, Hooks{})
 
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "failed to parse XML output")
+	require.NoError(t, err)
+	assert.True(t, result.Crashed)
+	assert.Contains(t, result.CrashReason, "older than the run")
+	require.Len(t, result.TestResults, 1, "stale results are not shown")
+	assert.Equal(t, parser.SuiteCrashedTestCaseName, result.TestResults[0].TestCaseName)
 }
@@ -0,0 +1,219 @@
+package runner
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/adamakhtar/wing_commander/internal/parser"
+	"github.com/adamakhtar/wing_commander/internal/testresult"
+	"github.com/charmbracelet/log"
+)
+
+// streamPathEnvVar tells reporters where to append per-test JSON lines.
+const streamPathEnvVar = "WING_COMMANDER_STREAM_PATH"
+
+// streamPollInterval is how often the results stream is checked for new lines.
+const streamPollInterval = 100 * time.Millisecond
+
+// ResultFunc is called with every test result streamed while the command runs.
+type ResultFunc func(testresult.TestResult)
+
+// RunningTest is a test the reporter announced as started but hasn't finished.
+type RunningTest struct {
+	parser.TestStarted
+	StartedAt time.Time
+
+	order int // Position in the stream, breaks StartedAt ties
+}
+
+// StalledFunc is called with the running tests, oldest first, once the results
+// stream has been quiet for the hung test timeout, and with nil when it resumes.
+type StalledFunc func([]RunningTest)
+
+// resultStream tails a reporter's JSONL results stream, parsing lines as they
+// are appended. Lines are only parsed once their trailing newline has arrived.
+type resultStream struct {
+	path     string
+	opts     *parser.ParseOptions
+	offset   int64
+	pending  []byte
+	results  []testresult.TestResult
+	onResult ResultFunc
+
+	// Hung test detection, disabled when hungTimeout is zero
+	running      map[string]RunningTest
+	started      int
+	lastActivity time.Time
+	hungTimeout  time.Duration
+	stalled      bool
+	onStalled    StalledFunc
+}
+
+func newResultStream(path string, opts *parser.ParseOptions, onResult ResultFunc) *resultStream {
+	return &resultStream{
+		path:         path,
+		opts:         opts,
+		onResult:     onResult,
+		running:      map[string]RunningTest{},
+		lastActivity: time.Now(),
+	}
+}
+
+// detectHungTests reports running tests to onStalled once the stream has been
+// quiet for timeout.
+func (s *resultStream) detectHungTests(timeout time.Duration, onStalled StalledFunc) {
+	s.hungTimeout = timeout
+	s.onStalled = onStalled
+}
+
+// reset removes a stream left behind by a previous run.
+func (s *resultStream) reset() error {
+	if err := os.Remove(s.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove results stream %s: %w", s.path, err)
+	}
+	return nil
+}
+
+// follow polls the stream until done is closed, then reads whatever is left.
+func (s *resultStream) follow(done <-chan struct{}) {
+	ticker := time.NewTicker(streamPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			s.poll()
+			return
+		case now := <-ticker.C:
+			s.poll()
+			s.checkStalled(now)
+		}
+	}
+}
+
+// poll reads lines appended since the last poll.
+func (s *resultStream) poll() {
+	file, err := os.Open(s.path)
+	if err != nil {
+		// The reporter hasn't created the stream yet, or doesn't support it
+		return
+	}
+	defer file.Close()
+
+	if _, err := file.Seek(s.offset, io.SeekStart); err != nil {
+		log.Warn("failed to seek results stream", "path", s.path, "error", err)
+		return
+	}
+	data, err := io.ReadAll(file)
+	if err != nil {
+		log.Warn("failed to read results stream", "path", s.path, "error", err)
+		return
+	}
+	s.offset += int64(len(data))
+
+	data = append(s.pending, data...)
+	for {
+		idx := bytes.IndexByte(data, '\n')
+		if idx == -1 {
+			break
+		}
+		s.parseLine(bytes.TrimSpace(data[:idx]))
+		data = data[idx+1:]
+	}
+	s.pending = append([]byte(nil), data...)
+}
+
+func (s *resultStream) parseLine(line []byte) {
+	if len(line) == 0 {
+		return
+	}
+
+	event, err := parser.ParseStreamLine(len(s.results)+1, line, s.opts)
+	if err != nil {
+		log.Warn("skipping results stream line", "path", s.path, "error", err)
+		return
+	}
+	s.markActivity()
+
+	if event.Started != nil {
+		started := *event.Started
+		s.running[runningTestKey(started.TestId, started.GroupName, started.TestCaseName)] = RunningTest{
+			TestStarted: started,
+			StartedAt:   time.Now(),
+			order:       s.started,
+		}
+		s.started++
+		return
+	}
+
+	result := testresult.NewNormalizer().NormalizeTestResults([]testresult.TestResult{*event.Result})[0]
+	delete(s.running, runningTestKey(result.TestId, result.GroupName, result.TestCaseName))
+	s.results = append(s.results, result)
+	if s.onResult != nil {
+		s.onResult(result)
+	}
+}
+
+// markActivity records that the stream changed, clearing a reported stall.
+func (s *resultStream) markActivity() {
+	s.lastActivity = time.Now()
+	if s.stalled {
+		s.stalled = false
+		if s.onStalled != nil {
+			s.onStalled(nil)
+		}
+	}
+}
+
+// checkStalled reports the running tests once when the stream has been quiet
+// for longer than the hung test timeout.
+func (s *resultStream) checkStalled(now time.Time) {
+	if s.hungTimeout <= 0 || s.stalled || len(s.running) == 0 {
+		return
+	}
+	if now.Sub(s.lastActivity) < s.hungTimeout {
+		return
+	}
+
+	s.stalled = true
+	running := s.Running()
+	log.Debug("results stream stalled", "path", s.path, "running", len(running))
+	if s.onStalled != nil {
+		s.onStalled(running)
+	}
+}
+
+// Running returns the tests that started but haven't finished, oldest first.
+func (s *resultStream) Running() []RunningTest {
+	running := make([]RunningTest, 0, len(s.running))
+	for _, test := range s.running {
+		running = append(running, test)
+	}
+	sort.Slice(running, func(i, j int) bool {
+		if !running[i].StartedAt.Equal(running[j].StartedAt) {
+			return running[i].StartedAt.Before(running[j].StartedAt)
+		}
+		return running[i].order < running[j].order
+	})
+	return running
+}
+
+// runningTestKey matches a started test to its result. The test id is
+// preferred as names such as RSpec's generated descriptions can change once
+// the test has run.
+func runningTestKey(testId, groupName, testCaseName string) string {
+	if testId != "" {
+		return testId
+	}
+	return groupName + "\x00" + testCaseName
+}
+
+// Results returns every result read from the stream so far.
+func (s *resultStream) Results() []testresult.TestResult {
+	return s.results
+}
@@ -0,0 +1,153 @@
+package runner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/adamakhtar/wing_commander/internal/config"
+	"github.com/adamakhtar/wing_commander/internal/projectfs"
+	"github.com/adamakhtar/wing_commander/internal/testresult"
+	"github.com/adamakhtar/wing_commander/internal/testrun"
+	"github.com/adamakhtar/wing_commander/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	streamedStart = `{"event":"started","test_group_name":"WorkerTest","test_case_name":"test_ok","test_file_path":"test/worker_test.rb","test_line_number":4}`
+	streamedPass  = `{"test_group_name":"WorkerTest","test_case_name":"test_ok","test_status":"passed","duration":"0.01"}`
+	streamedFail  = `{"test_group_name":"WorkerTest","test_case_name":"test_broken","test_status":"failed","duration":"0.02","failure_details":"Expected: 1\n  Actual: 2"}`
+)
+
+func TestResultStream_Poll(t *testing.T) {
+	rootPath, _ := types.NewAbsPath(t.TempDir())
+	require.NoError(t, projectfs.InitProjectFS(rootPath, ""))
+	path := filepath.Join(rootPath.String(), "stream.jsonl")
+
+	var streamed []testresult.TestResult
+	stream := newResultStream(path, nil, func(result testresult.TestResult) {
+		streamed = append(streamed, result)
+	})
+
+	// Nothing written yet
+	stream.poll()
+	assert.Empty(t, stream.Results())
+
+	file, err := os.Create(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	// A line is only parsed once its newline has been written
+	_, err = file.WriteString(streamedPass + "\n" + streamedFail[:20])
+	require.NoError(t, err)
+	stream.poll()
+	require.Len(t, stream.Results(), 1)
+
+	_, err = file.WriteString(streamedFail[20:] + "\nnot json\n")
+	require.NoError(t, err)
+	stream.poll()
+	require.Len(t, stream.Results(), 2)
+
+	assert.Equal(t, stream.Results(), streamed)
+	assert.Equal(t, 1, streamed[0].Id)
+	assert.Equal(t, "test_ok", streamed[0].TestCaseName)
+	assert.Equal(t, 2, streamed[1].Id)
+	assert.Equal(t, testresult.StatusFail, streamed[1].Status)
+}
+
+func TestResultStream_ReportsStalledTests(t *testing.T) {
+	rootPath, _ := types.NewAbsPath(t.TempDir())
+	require.NoError(t, projectfs.InitProjectFS(rootPath, ""))
+	path := filepath.Join(rootPath.String(), "stream.jsonl")
+
+	var reports [][]RunningTest
+	stream := newResultStream(path, nil, nil)
+	stream.detectHungTests(time.Minute, func(running []RunningTest) {
+		reports = append(reports, running)
+	})
+
+	file, err := os.Create(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	_, err = file.WriteString(streamedStart + "\n")
+	require.NoError(t, err)
+	stream.poll()
+	require.Len(t, stream.Running(), 1)
+	assert.Empty(t, stream.Results(), "started events are not results")
+
+	// Still within the quiet period
+	stream.checkStalled(time.Now())
+	assert.Empty(t, reports)
+
+	// Reported once when the quiet period elapses
+	stream.checkStalled(time.Now().Add(2 * time.Minute))
+	stream.checkStalled(time.Now().Add(3 * time.Minute))
+	require.Len(t, reports, 1)
+	require.Len(t, reports[0], 1)
+	assert.Equal(t, "test_ok", reports[0][0].TestCaseName)
+	assert.Equal(t, filepath.Join(rootPath.String(), "test/worker_test.rb"), reports[0][0].TestFilePath.String())
+	assert.Equal(t, 4, reports[0][0].TestLineNumber)
+
+	// Cleared when the test finishes
+	_, err = file.WriteString(streamedPass + "\n")
+	require.NoError(t, err)
+	stream.poll()
+	require.Len(t, reports, 2)
+	assert.Nil(t, reports[1])
+	assert.Empty(t, stream.Running())
+	assert.Len(t, stream.Results(), 1)
+}
+
+func TestResultStream_HungTestDetectionDisabled(t *testing.T) {
+	rootPath, _ := types.NewAbsPath(t.TempDir())
+	require.NoError(t, projectfs.InitProjectFS(rootPath, ""))
+	path := filepath.Join(rootPath.String(), "stream.jsonl")
+	require.NoError(t, os.WriteFile(path, []byte(streamedStart+"\n"), 0o644))
+
+	stream := newResultStream(path, nil, nil)
+	stream.detectHungTests(0, func([]RunningTest) {
+		t.Fatal("stall reported with detection disabled")
+	})
+	stream.poll()
+	stream.checkStalled(time.Now().Add(time.Hour))
+}
+
+func TestTestRunner_ExecuteTestsKeepsStreamedResultsOfCrashedRun(t *testing.T) {
+	rootPath, _ := types.NewAbsPath(t.TempDir())
+	require.NoError(t, projectfs.InitProjectFS(rootPath, ""))
+
+	// Stale stream from a previous run is removed before the command starts
+	streamPath := filepath.Join(rootPath.String(), "stream.jsonl")
+	require.NoError(t, os.WriteFile(streamPath, []byte(streamedPass+"\n"), 0o644))
+
+	command := `printf '%s\n' '` + streamedPass + `' >> "$WING_COMMANDER_STREAM_PATH"; ` +
+		`printf '%s\n' '` + streamedFail + `' >> "$WING_COMMANDER_STREAM_PATH"; ` +
+		`echo "Segmentation fault"; exit 139`
+
+	runner := NewTestRunner(&config.Config{
+		TestFramework:   config.FrameworkMinitest,
+		TestCommand:     command,
+		TestResultsPath: filepath.Join(rootPath.String(), "summary.yml"),
+		TestStreamPath:  "stream.jsonl",
+	})
+
+	var streamed []testresult.TestResult
+	result, err := runner.ExecuteTests(context.Background(), testrun.TestRun{Id: 7, Mode: string(testrun.ModeRunWholeSuite)}, Hooks{
+		OnResult: func(result testresult.TestResult) {
+			streamed = append(streamed, result)
+		},
+	})
+
+	require.NoError(t, err)
+	assert.True(t, result.Crashed)
+	assert.Contains(t, result.CrashReason, "exited with code 139")
+	assert.Contains(t, result.CommandOutput, "Segmentation fault")
+	assert.Equal(t, 7, result.TestRunId)
+	// The streamed results plus the suite crashed result
+	assert.Equal(t, Metrics{TotalTests: 3, PassedTests: 1, FailedTests: 2}, result.Metrics)
+	assert.Len(t, streamed, 2)
+}
@@ -0,0 +1,102 @@
+package runner
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/adamakhtar/wing_commander/internal/config"
+	"github.com/adamakhtar/wing_commander/internal/framework"
+	"github.com/adamakhtar/wing_commander/internal/projectfs"
+	"github.com/adamakhtar/wing_commander/internal/testrun"
+	"github.com/adamakhtar/wing_commander/internal/types"
+)
+
+// summaryPathEnvVar tells reporters where to write the summary file.
+const summaryPathEnvVar = "WING_COMMANDER_SUMMARY_PATH"
+
+// executionTarget translates test runs between the host and where the test
+// command runs. Locally it changes nothing. With a command prefix, paths are
+// moved from the host project root to the target's on the way in and results
+// are mapped back by ProjectFS on the way out.
+type executionTarget struct {
+	prefix     string
+	hostRoot   types.AbsPath
+	targetRoot string // Project root inside the target, empty when it matches the host's
+}
+
+func newExecutionTarget(cfg config.ExecutionTarget) executionTarget {
+	target := executionTarget{
+		prefix:   strings.TrimSpace(cfg.CommandPrefix),
+		hostRoot: projectfs.GetProjectFS().RootPath,
+	}
+	if !cfg.IsLocal() && cfg.ProjectPath != "" {
+		target.targetRoot = filepath.Clean(cfg.ProjectPath)
+	}
+	return target
+}
+
+// translatesPaths reports whether host and target see the project at different paths.
+func (t executionTarget) translatesPaths() bool {
+	return t.targetRoot != "" && t.targetRoot != t.hostRoot.String()
+}
+
+// path translates a host path inside the project root to the target's path.
+// Relative paths and paths outside the project are returned unchanged.
+func (t executionTarget) path(hostPath string) string {
+	if !t.translatesPaths() || !filepath.IsAbs(hostPath) {
+		return hostPath
+	}
+	rel, err := projectfs.GetProjectFS().Rel(types.AbsPath(filepath.Clean(hostPath)))
+	if err != nil {
+		return hostPath
+	}
+	return filepath.Join(t.targetRoot, rel.String())
+}
+
+// testRun returns a copy of testRun with its patterns pointing at target paths.
+func (t executionTarget) testRun(testRun testrun.TestRun) testrun.TestRun {
+	if !t.translatesPaths() {
+		return testRun
+	}
+	testRun.Patterns = slices.Clone(testRun.Patterns)
+	for i := range testRun.Patterns {
+		testRun.Patterns[i].Path = t.path(testRun.Patterns[i].Path)
+	}
+	return testRun
+}
+
+// command wraps commandStr in the prefix so it runs in the target's project
+// root with env set there. env is passed as "KEY=value" pairs holding host
+// paths, which are translated too.
+func (t executionTarget) command(commandStr string, env []string) string {
+	if t.prefix == "" {
+		return commandStr
+	}
+
+	root := t.hostRoot.String()
+	if t.targetRoot != "" {
+		root = t.targetRoot
+	}
+
+	parts := []string{t.prefix, "env"}
+	for _, pair := range env {
+		key, value, _ := strings.Cut(pair, "=")
+		parts = append(parts, framework.ShellEscape(key+"="+t.path(value)))
+	}
+	inner := "cd " + framework.ShellEscape(root) + " && " + commandStr
+	parts = append(parts, "sh", "-c", framework.ShellEscape(inner))
+	return strings.Join(parts, " ")
+}
+
+// mapResultsBack registers the mapping from target paths to host paths, so
+// results written in the target are parsed with host paths.
+func (t executionTarget) mapResultsBack() {
+	if !t.translatesPaths() {
+		return
+	}
+	projectfs.GetProjectFS().AddPathMapping(projectfs.PathMapping{
+		From: types.AbsPath(t.targetRoot),
+		To:   t.hostRoot,
+	})
+}
@@ -0,0 +1,101 @@
+package runner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/adamakhtar/wing_commander/internal/config"
+	"github.com/adamakhtar/wing_commander/internal/projectfs"
+	"github.com/adamakhtar/wing_commander/internal/testrun"
+	"github.com/adamakhtar/wing_commander/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecutionTarget_Command(t *testing.T) {
+	rootPath, _ := types.NewAbsPath("/home/dev/app")
+	require.NoError(t, projectfs.InitProjectFS(rootPath, ""))
+
+	local := newExecutionTarget(config.ExecutionTarget{})
+	assert.Equal(t, "bin/rails test", local.command("bin/rails test", []string{"A=/home/dev/app/a"}))
+
+	target := newExecutionTarget(config.ExecutionTarget{CommandPrefix: "docker compose exec -T web", ProjectPath: "/app"})
+	assert.Equal(t,
+		`docker compose exec -T web env 'WING_COMMANDER_STREAM_PATH=/app/stream.jsonl' 'OTHER=/tmp/x' sh -c 'cd '\''/app'\'' && bin/rails test '\''test/a_test.rb'\'''`,
+		target.command(`bin/rails test 'test/a_test.rb'`, []string{"WING_COMMANDER_STREAM_PATH=/home/dev/app/stream.jsonl", "OTHER=/tmp/x"}))
+
+	line := 4
+	testRun := testrun.TestRun{Patterns: []testrun.TestPattern{
+		{Path: "/home/dev/app/test/a_test.rb", LineNumber: &line},
+		{Path: "test/b_test.rb"},
+		{Path: "/elsewhere/c_test.rb"},
+	}}
+	translated := target.testRun(testRun)
+	assert.Equal(t, []string{"/app/test/a_test.rb", "test/b_test.rb", "/elsewhere/c_test.rb"}, translated.PatternsToFilePaths())
+	assert.Equal(t, "/home/dev/app/test/a_test.rb", testRun.Patterns[0].Path, "the original test run is unchanged")
+}
+
+// The fake prefix runs the command as is, but the "container" sees the project
+// through a symlink at another path, like a mounted volume.
+func TestTestRunner_ExecuteTestsThroughCommandPrefix(t *testing.T) {
+	tmp := t.TempDir()
+	hostRoot := filepath.Join(tmp, "host")
+	containerRoot := filepath.Join(tmp, "container")
+	require.NoError(t, os.MkdirAll(filepath.Join(hostRoot, "test"), 0o755))
+	require.NoError(t, os.Symlink(hostRoot, containerRoot))
+	rootPath, _ := types.NewAbsPath(hostRoot)
+	require.NoError(t, projectfs.InitProjectFS(rootPath, "test/**/*_test.rb"))
+
+	prefix := filepath.Join(tmp, "fake-exec")
+	require.NoError(t, os.WriteFile(prefix, []byte("#!/bin/sh\nexec \"$@\"\n"), 0o755))
+
+	// Writes a failing test located via the container's working directory and
+	// records the arguments it was given
+	script := `printf '%s\n' "$@" > args.txt
+cat > "$WING_COMMANDER_SUMMARY_PATH" <<SUMMARY
+- test_group_name: UserTest
+  test_case_name: test_name
+  test_status: failed
+  test_file_path: "$PWD/test/user_test.rb"
+  test_line_number: 3
+  failure_details: "boom"
+  full_backtrace:
+    - "$PWD/test/user_test.rb:4:in 'test_name'"
+SUMMARY
+exit 1
+`
+	require.NoError(t, os.WriteFile(filepath.Join(hostRoot, "fake_tests.sh"), []byte(script), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(hostRoot, "test", "user_test.rb"), []byte("\n"), 0o644))
+
+	runner := NewTestRunner(&config.Config{
+		TestFramework:   config.FrameworkMinitest,
+		TestCommand:     "sh fake_tests.sh %{paths}",
+		TestResultsPath: filepath.Join(hostRoot, "summary.yml"),
+		ExecutionTarget: config.ExecutionTarget{CommandPrefix: prefix, ProjectPath: containerRoot},
+	})
+
+	pattern, err := testrun.NewTestPattern(filepath.Join(hostRoot, "test", "user_test.rb"), nil, nil, nil)
+	require.NoError(t, err)
+	result, err := runner.ExecuteTests(context.Background(), testrun.TestRun{
+		Id:       1,
+		Mode:     string(testrun.ModeRunSelectedPatterns),
+		Patterns: []testrun.TestPattern{pattern},
+	}, Hooks{})
+	require.NoError(t, err)
+
+	// Paths went in as container paths...
+	args, err := os.ReadFile(filepath.Join(hostRoot, "args.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(containerRoot, "test", "user_test.rb")+"\n", string(args))
+
+	// ...and came back as host paths
+	require.False(t, result.Crashed, result.CrashReason)
+	require.Len(t, result.FailedTests, 1)
+	failed := result.FailedTests[0]
+	testFile := types.AbsPath(filepath.Join(hostRoot, "test", "user_test.rb"))
+	assert.Equal(t, testFile, failed.TestFilePath)
+	require.Len(t, failed.FilteredBacktrace.Frames, 1)
+	assert.Equal(t, testFile, failed.FilteredBacktrace.Frames[0].FilePath)
+}
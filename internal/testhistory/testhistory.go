@@ -0,0 +1,219 @@
+package testhistory
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/adamakhtar/wing_commander/internal/runner"
+	"github.com/adamakhtar/wing_commander/internal/testresult"
+	"github.com/adamakhtar/wing_commander/internal/testrun"
+	"github.com/adamakhtar/wing_commander/internal/types"
+)
+
+// Run is a finished test run and its results.
+type Run struct {
+	TestRun testrun.TestRun
+	Result  *runner.TestExecutionResult
+}
+
+// Outcome is how a test did in one test run.
+type Outcome struct {
+	TestRunId    int
+	StartedAt    time.Time
+	Status       testresult.TestStatus
+	Duration     float64
+	FilesChanged bool // The files in the test's backtrace changed since its previous outcome, or it isn't known whether they did
+}
+
+// Flakiness counts how often a test flipped between passing and failing while
+// the files in its backtrace stayed the same.
+type Flakiness struct {
+	Flips       int // Consecutive outcomes that flipped between pass and fail
+	Comparisons int // Consecutive pass or fail outcomes with the same files
+}
+
+// Score returns the share of comparisons that flipped, from 0 to 1.
+func (f Flakiness) Score() float64 {
+	if f.Comparisons == 0 {
+		return 0
+	}
+	return float64(f.Flips) / float64(f.Comparisons)
+}
+
+// IsFlaky reports whether the test flipped at least once without a change.
+func (f Flakiness) IsFlaky() bool {
+	return f.Flips > 0
+}
+
+// TestHistory is a test's outcomes across test runs, oldest first, and its
+// flakiness.
+type TestHistory struct {
+	Outcomes  []Outcome
+	Flakiness Flakiness
+}
+
+// Last returns the n most recent outcomes, oldest first.
+func (h TestHistory) Last(n int) []Outcome {
+	if len(h.Outcomes) <= n {
+		return h.Outcomes
+	}
+	return h.Outcomes[len(h.Outcomes)-n:]
+}
+
+// CommitDiffFunc returns the files changed from one commit to another, by path
+// relative to the project root.
+type CommitDiffFunc func(from string, to string) ([]string, error)
+
+// CachedCommitDiff remembers the files changed between each pair of commits,
+// which never changes, so diff is asked once per pair.
+func CachedCommitDiff(diff CommitDiffFunc) CommitDiffFunc {
+	type commits struct{ from, to string }
+	type changes struct {
+		files []string
+		err   error
+	}
+	var mu sync.Mutex
+	cache := make(map[commits]changes)
+
+	return func(from string, to string) ([]string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		key := commits{from, to}
+		if cached, ok := cache[key]; ok {
+			return cached.files, cached.err
+		}
+		files, err := diff(from, to)
+		cache[key] = changes{files, err}
+		return files, err
+	}
+}
+
+// History indexes the results of test runs by test.
+type History struct {
+	root        string
+	runs        []Run
+	results     map[testresult.Key][]runResult
+	diffCommits CommitDiffFunc
+}
+
+type runResult struct {
+	run    int // Index in runs
+	result testresult.TestResult
+}
+
+// New indexes the results of runs. Runs still in flight or without results
+// are left out. root is the project root the tests' files are in.
+func New(root string, runs []Run, diffCommits CommitDiffFunc) *History {
+	h := &History{root: root, results: make(map[testresult.Key][]runResult), diffCommits: diffCommits}
+	for _, run := range runs {
+		if run.Result != nil && run.TestRun.Status != testrun.StatusRunning {
+			h.runs = append(h.runs, run)
+		}
+	}
+	sort.Slice(h.runs, func(i, j int) bool {
+		return h.runs[i].TestRun.Id < h.runs[j].TestRun.Id
+	})
+
+	for i, run := range h.runs {
+		for _, result := range run.Result.TestResults {
+			key := result.Key()
+			h.results[key] = append(h.results[key], runResult{run: i, result: result})
+		}
+	}
+	return h
+}
+
+// Of returns the history of a test up to and including the test run with the
+// ID upTo.
+func (h *History) Of(key testresult.Key, upTo int) TestHistory {
+	var history TestHistory
+	var previous *runResult
+	for i, current := range h.results[key] {
+		testRun := h.runs[current.run].TestRun
+		if testRun.Id > upTo {
+			break
+		}
+
+		outcome := Outcome{
+			TestRunId: testRun.Id,
+			StartedAt: testRun.StartedAt,
+			Status:    current.result.Status,
+			Duration:  current.result.Duration,
+		}
+		if previous != nil {
+			outcome.FilesChanged = h.filesChanged(h.runs[previous.run].TestRun, testRun, previous.result, current.result)
+			if !outcome.FilesChanged && !current.result.IsSkipped() {
+				history.Flakiness.Comparisons++
+				if previous.result.Status != current.result.Status {
+					history.Flakiness.Flips++
+				}
+			}
+		}
+		history.Outcomes = append(history.Outcomes, outcome)
+
+		// Skipped outcomes say nothing about passing or failing
+		if !current.result.IsSkipped() {
+			previous = &h.results[key][i]
+		}
+	}
+	return history
+}
+
+// filesChanged reports whether any file of the test, its own or in the
+// backtraces of either result, changed from one run to the next. It is
+// assumed they did when either run has no git revision.
+func (h *History) filesChanged(from testrun.TestRun, to testrun.TestRun, results ...testresult.TestResult) bool {
+	if from.GitSHA == "" || to.GitSHA == "" {
+		return true
+	}
+	// Runs saved before their uncommitted files were recorded
+	if (from.GitDirty && from.DirtyFiles == nil) || (to.GitDirty && to.DirtyFiles == nil) {
+		return true
+	}
+
+	changed := make(map[string]bool)
+	if from.GitSHA != to.GitSHA {
+		files, err := h.diffCommits(from.GitSHA, to.GitSHA)
+		if err != nil {
+			return true
+		}
+		for _, file := range files {
+			changed[file] = true
+		}
+	}
+	for path, hash := range from.DirtyFiles {
+		if to.DirtyFiles[path] != hash {
+			changed[path] = true
+		}
+	}
+	for path, hash := range to.DirtyFiles {
+		if from.DirtyFiles[path] != hash {
+			changed[path] = true
+		}
+	}
+
+	for _, result := range results {
+		if changed[h.rel(result.TestFilePath)] {
+			return true
+		}
+		for _, frame := range result.FilteredBacktrace.Frames {
+			if changed[h.rel(frame.FilePath)] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// rel returns path relative to the project root, empty for paths outside it.
+func (h *History) rel(path types.AbsPath) string {
+	rel, err := filepath.Rel(h.root, path.String())
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return ""
+	}
+	return filepath.ToSlash(rel)
+}
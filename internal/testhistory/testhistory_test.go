@@ -0,0 +1,133 @@
+package testhistory
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/adamakhtar/wing_commander/internal/runner"
+	"github.com/adamakhtar/wing_commander/internal/testresult"
+	"github.com/adamakhtar/wing_commander/internal/testrun"
+	"github.com/adamakhtar/wing_commander/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const root = "/project"
+
+func result(status testresult.TestStatus, duration float64) testresult.TestResult {
+	result := testresult.NewTestResult("UserTest", "test_save", status)
+	result.TestFilePath = types.AbsPath("/project/test/user_test.rb")
+	result.Duration = duration
+	if status == testresult.StatusFail {
+		result.FilteredBacktrace.AppendFrame(types.StackFrame{FilePath: "/project/app/models/user.rb", Line: 12})
+	}
+	return result
+}
+
+func run(id int, sha string, dirtyFiles map[string]string, results ...testresult.TestResult) Run {
+	return Run{
+		TestRun: testrun.TestRun{
+			Id:         id,
+			Status:     testrun.StatusCompleted,
+			StartedAt:  time.Date(2025, 11, 14, 21, id, 0, 0, time.UTC),
+			GitSHA:     sha,
+			GitDirty:   len(dirtyFiles) > 0,
+			DirtyFiles: dirtyFiles,
+		},
+		Result: runner.NewTestExecutionResult(id, results),
+	}
+}
+
+// diffCommits reports app/models/user.rb changed between any two commits.
+func diffCommits(from string, to string) ([]string, error) {
+	return []string{"app/models/user.rb"}, nil
+}
+
+func TestHistory_FlipsWithoutChangesAreFlaky(t *testing.T) {
+	history := New(root, []Run{
+		run(1, "aaa", nil, result(testresult.StatusPass, 0.1)),
+		run(2, "aaa", nil, result(testresult.StatusFail, 0.3)),
+		run(3, "aaa", nil, result(testresult.StatusPass, 0.2)),
+		run(4, "aaa", nil, result(testresult.StatusPass, 0.2)),
+	}, diffCommits)
+
+	testHistory := history.Of(result(testresult.StatusPass, 0).Key(), 4)
+
+	require.Len(t, testHistory.Outcomes, 4)
+	assert.Equal(t, testresult.StatusFail, testHistory.Outcomes[1].Status)
+	assert.Equal(t, 0.3, testHistory.Outcomes[1].Duration)
+	assert.Equal(t, Flakiness{Flips: 2, Comparisons: 3}, testHistory.Flakiness)
+	assert.InDelta(t, 2.0/3.0, testHistory.Flakiness.Score(), 0.001)
+	assert.True(t, testHistory.Flakiness.IsFlaky())
+	assert.Len(t, testHistory.Last(2), 2)
+	assert.Equal(t, 3, testHistory.Last(2)[0].TestRunId)
+}
+
+func TestHistory_FlipsAfterChangesToBacktraceFilesAreNotFlaky(t *testing.T) {
+	history := New(root, []Run{
+		run(1, "aaa", nil, result(testresult.StatusPass, 0.1)),
+		// A commit changed a file in the failure's backtrace
+		run(2, "bbb", nil, result(testresult.StatusFail, 0.1)),
+		// An uncommitted change to the test file
+		run(3, "bbb", map[string]string{"test/user_test.rb": "1f2e"}, result(testresult.StatusPass, 0.1)),
+		// An uncommitted change to an unrelated file
+		run(4, "bbb", map[string]string{"test/user_test.rb": "1f2e", "README.md": "9c8d"}, result(testresult.StatusPass, 0.1)),
+	}, diffCommits)
+
+	testHistory := history.Of(result(testresult.StatusPass, 0).Key(), 4)
+
+	assert.Equal(t, Flakiness{Flips: 0, Comparisons: 1}, testHistory.Flakiness)
+	assert.False(t, testHistory.Flakiness.IsFlaky())
+	assert.True(t, testHistory.Outcomes[1].FilesChanged)
+	assert.True(t, testHistory.Outcomes[2].FilesChanged)
+	assert.False(t, testHistory.Outcomes[3].FilesChanged)
+}
+
+func TestHistory_UnknownRevisionsAreNotCompared(t *testing.T) {
+	failingDiff := func(from string, to string) ([]string, error) {
+		return nil, errors.New("unknown revision")
+	}
+	history := New(root, []Run{
+		run(1, "", nil, result(testresult.StatusPass, 0.1)),
+		run(2, "aaa", nil, result(testresult.StatusFail, 0.1)),
+		run(3, "bbb", nil, result(testresult.StatusPass, 0.1)),
+	}, failingDiff)
+
+	testHistory := history.Of(result(testresult.StatusPass, 0).Key(), 3)
+
+	assert.Equal(t, Flakiness{}, testHistory.Flakiness)
+}
+
+func TestHistory_OfStopsAtTheGivenRun(t *testing.T) {
+	running := run(3, "aaa", nil, result(testresult.StatusFail, 0.1))
+	running.TestRun.Status = testrun.StatusRunning
+	history := New(root, []Run{
+		run(2, "aaa", nil, result(testresult.StatusFail, 0.1)),
+		run(1, "aaa", nil, result(testresult.StatusPass, 0.1)),
+		running,
+		{TestRun: testrun.TestRun{Id: 4, Status: testrun.StatusFailed}},
+	}, diffCommits)
+
+	testHistory := history.Of(result(testresult.StatusPass, 0).Key(), 1)
+	require.Len(t, testHistory.Outcomes, 1)
+	assert.Equal(t, 1, testHistory.Outcomes[0].TestRunId)
+
+	assert.Len(t, history.Of(result(testresult.StatusPass, 0).Key(), 4).Outcomes, 2)
+}
+
+func TestCachedCommitDiff(t *testing.T) {
+	calls := 0
+	diff := CachedCommitDiff(func(from string, to string) ([]string, error) {
+		calls++
+		return []string{from + ".." + to}, nil
+	})
+
+	first, _ := diff("aaa", "bbb")
+	second, _ := diff("aaa", "bbb")
+	_, _ = diff("bbb", "ccc")
+
+	assert.Equal(t, []string{"aaa..bbb"}, first)
+	assert.Equal(t, first, second)
+	assert.Equal(t, 2, calls)
+}
@@ -68,6 +68,7 @@ const (
 // TestResult represents a single test execution result
 type TestResult struct {
 	Id                int
+	TestId            string // Framework specific identifier, e.g. an RSpec example id ("./spec/user_spec.rb[1:2]")
 	GroupName         string
 	TestCaseName      string
 	Status            TestStatus
@@ -80,6 +81,39 @@ type TestResult struct {
 	FullBacktrace     backtrace.Backtrace
 	FilteredBacktrace backtrace.Backtrace
 	Duration          float64
+	Output            string    // Captured stdout/stderr for the test, when the report provides it
+	Flaky             bool      // Failed, then passed when retried
+	Attempts          []Attempt // Every run of a retried test, oldest first, empty when it wasn't retried
+}
+
+// Attempt is one run of a test that failed and was retried.
+type Attempt struct {
+	Status         TestStatus
+	FailureCause   FailureCause
+	FailureDetails string
+	Duration       float64
+}
+
+// NewAttempt records how a test result did as one attempt.
+func NewAttempt(result TestResult) Attempt {
+	return Attempt{
+		Status:         result.Status,
+		FailureCause:   result.FailureCause,
+		FailureDetails: result.FailureDetails,
+		Duration:       result.Duration,
+	}
+}
+
+// Key identifies a test across test runs by its group, name and file.
+type Key struct {
+	GroupName    string
+	TestCaseName string
+	TestFilePath types.AbsPath
+}
+
+// Key returns the key identifying the test across test runs.
+func (tr TestResult) Key() Key {
+	return Key{GroupName: tr.GroupName, TestCaseName: tr.TestCaseName, TestFilePath: tr.TestFilePath}
 }
 
 func (tr *TestResult) AbbreviatedResult() string {
@@ -100,6 +134,11 @@ func (tr *TestResult) IsPassed() bool {
 	return tr.Status == StatusPass
 }
 
+// IsFlaky reports whether the test failed and then passed when retried.
+func (tr *TestResult) IsFlaky() bool {
+	return tr.Flaky
+}
+
 // IsSkipped reports whether the test result represents a skipped test.
 func (tr *TestResult) IsSkipped() bool {
 	return tr.Status == StatusSkip
@@ -2,8 +2,12 @@ package testrun
 
 import (
 	"fmt"
+	"math/rand/v2"
 	"sort"
 	"strings"
+	"time"
+
+	"github.com/adamakhtar/wing_commander/internal/testresult"
 )
 
 var testRunIDCounter int
@@ -13,6 +17,17 @@ func generateTestRunID() int {
 	return testRunIDCounter
 }
 
+// ContinueIDsAfter makes new test runs take IDs greater than id, so runs of
+// this session don't reuse the IDs of runs restored from history.
+func ContinueIDsAfter(id int) {
+	testRunIDCounter = max(testRunIDCounter, id)
+}
+
+// generateSeed returns a seed in the range Minitest and RSpec print, 1-65535.
+func generateSeed() int {
+	return rand.IntN(0xFFFF) + 1
+}
+
 // Mode represents the high-level mode describing how a test run was initiated
 type Mode string
 
@@ -21,6 +36,7 @@ const (
 	ModeRunSelectedPatterns Mode = "run_selected_patterns"
 	ModeReRunSingleFailure  Mode = "rerun_single_failure"
 	ModeReRunAllFailures    Mode = "rerun_all_failures"
+	ModeLoadedSummary       Mode = "loaded_summary" // Results read from existing summary files, nothing was run
 )
 
 // TestPattern represents a test pattern with optional line number and test case name
@@ -29,6 +45,7 @@ type TestPattern struct {
 	LineNumber   *int    // Optional: line number for future RSpec support
 	TestCaseName *string // Optional: test case name for future support
 	TestGroupName *string // Optional: test group name for future support
+	TestId        *string // Optional: framework specific test identifier (e.g. RSpec example id)
 }
 
 // NewTestPattern creates a new TestPattern with validation
@@ -45,6 +62,52 @@ func NewTestPattern(path string, lineNumber *int, testCaseName *string, testGrou
 	}, nil
 }
 
+// NewTestPatternForResult creates a TestPattern targeting a single test result.
+func NewTestPatternForResult(result testresult.TestResult) (TestPattern, error) {
+	pattern, err := NewTestPattern(
+		result.TestFilePath.String(),
+		&result.TestLineNumber,
+		&result.TestCaseName,
+		&result.GroupName,
+	)
+	if err != nil {
+		return TestPattern{}, err
+	}
+
+	if result.TestId != "" {
+		pattern.TestId = &result.TestId
+	}
+	return pattern, nil
+}
+
+// NewTestPatternFromNodeId creates a TestPattern from a pytest node id such as
+// "tests/test_cart.py::TestCart::test_total". The node id itself is kept as TestId
+// so re-runs can pass it straight back to pytest.
+func NewTestPatternFromNodeId(nodeId string) (TestPattern, error) {
+	base, params, _ := strings.Cut(nodeId, "[")
+	if params != "" {
+		params = "[" + params
+	}
+
+	parts := strings.Split(base, "::")
+	pattern, err := NewTestPattern(parts[0], nil, nil, nil)
+	if err != nil {
+		return TestPattern{}, err
+	}
+	if len(parts) == 1 {
+		return pattern, nil
+	}
+
+	testCaseName := parts[len(parts)-1] + params
+	pattern.TestCaseName = &testCaseName
+	if len(parts) > 2 {
+		testGroupName := strings.Join(parts[1:len(parts)-1], "::")
+		pattern.TestGroupName = &testGroupName
+	}
+	pattern.TestId = &nodeId
+	return pattern, nil
+}
+
 // String returns the string representation of the pattern
 // Format rules:
 //   - Only path: "path"
@@ -94,11 +157,29 @@ func PatternsToStrings(patterns []TestPattern) []string {
 	return result
 }
 
+// Status describes where a test run is in its lifecycle
+type Status string
+
+const (
+	StatusRunning   Status = "running"
+	StatusCompleted Status = "completed"
+	StatusCancelled Status = "cancelled"
+	StatusFailed    Status = "failed"
+)
+
 // TestRun captures the metadata needed to execute a run of tests
 type TestRun struct {
 	Id       int           // Unique identifier for the test run
 	Patterns []TestPattern // Specific test patterns to execute
 	Mode     string        // High-level mode describing how the run was initiated (optional)
+	Status   Status        // Lifecycle status, StatusRunning until the run finishes
+	Seed     int           // Random seed handed to command templates as %{seed}
+
+	StartedAt  time.Time // When the run was added
+	FinishedAt time.Time // When the run left StatusRunning, zero until then
+	GitSHA     string    // HEAD commit when the run started, empty outside a git repository
+	GitDirty   bool      // The work tree had uncommitted changes when the run started
+	DirtyFiles map[string]string // Content hashes of the uncommitted and untracked files when the run started, by path relative to the project root
 }
 
 func (tr TestRun) isRunningSpecificTestCases() bool {
@@ -128,15 +209,90 @@ func (tr *TestRuns) Add(patterns []TestPattern, mode Mode) (TestRun, error) {
 	}
 
 	testRun := TestRun{
-		Id:       generateTestRunID(),
-		Patterns: patterns,
-		Mode:     string(mode),
+		Id:        generateTestRunID(),
+		Patterns:  patterns,
+		Mode:      string(mode),
+		Status:    StatusRunning,
+		Seed:      generateSeed(),
+		StartedAt: time.Now(),
+	}
+
+	tr.testRuns[testRun.Id] = testRun
+	return testRun, nil
+}
+
+// Restore adds a test run of an earlier session, keeping its ID. Test runs
+// added later get greater IDs.
+func (tr *TestRuns) Restore(testRun TestRun) {
+	ContinueIDsAfter(testRun.Id)
+	tr.testRuns[testRun.Id] = testRun
+}
+
+// Repeat adds a run of the same patterns, mode and seed as the test run with
+// the given ID, so it runs exactly the same tests in the same order.
+func (tr *TestRuns) Repeat(id int) (TestRun, error) {
+	original, ok := tr.testRuns[id]
+	if !ok {
+		return TestRun{}, fmt.Errorf("test run not found")
+	}
+	if original.Mode == string(ModeLoadedSummary) {
+		return TestRun{}, fmt.Errorf("a loaded summary can't be re-run")
 	}
 
+	testRun, err := tr.Add(original.Patterns, Mode(original.Mode))
+	if err != nil {
+		return TestRun{}, err
+	}
+	testRun.Seed = original.Seed
 	tr.testRuns[testRun.Id] = testRun
 	return testRun, nil
 }
 
+// Remove deletes a test run from the collection
+func (tr *TestRuns) Remove(id int) error {
+	if _, ok := tr.testRuns[id]; !ok {
+		return fmt.Errorf("test run not found")
+	}
+	delete(tr.testRuns, id)
+	return nil
+}
+
+// SetStatus records the lifecycle status of a test run, and when it finished
+// once it is no longer running
+func (tr *TestRuns) SetStatus(id int, status Status) error {
+	testRun, ok := tr.testRuns[id]
+	if !ok {
+		return fmt.Errorf("test run not found")
+	}
+	testRun.Status = status
+	if status != StatusRunning && testRun.FinishedAt.IsZero() {
+		testRun.FinishedAt = time.Now()
+	}
+	tr.testRuns[id] = testRun
+	return nil
+}
+
+// SetRevision records the git commit and work tree state a test run started from
+func (tr *TestRuns) SetRevision(id int, sha string, dirty bool, dirtyFiles map[string]string) error {
+	testRun, ok := tr.testRuns[id]
+	if !ok {
+		return fmt.Errorf("test run not found")
+	}
+	testRun.GitSHA = sha
+	testRun.GitDirty = dirty
+	testRun.DirtyFiles = dirtyFiles
+	tr.testRuns[id] = testRun
+	return nil
+}
+
+// Duration returns how long the run took, zero while it is running.
+func (tr TestRun) Duration() time.Duration {
+	if tr.FinishedAt.IsZero() || tr.StartedAt.IsZero() {
+		return 0
+	}
+	return tr.FinishedAt.Sub(tr.StartedAt)
+}
+
 // Get retrieves a test run by ID
 func (tr *TestRuns) Get(id int) (TestRun, error) {
 	testRun, ok := tr.testRuns[id]
@@ -0,0 +1,95 @@
+package testrun
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTestPatternFromNodeId(t *testing.T) {
+	pattern, err := NewTestPatternFromNodeId("tests/test_cart.py::TestCart::TestTotals::test_total[a::b]")
+	require.NoError(t, err)
+	assert.Equal(t, "tests/test_cart.py", pattern.Path)
+	assert.Equal(t, "TestCart::TestTotals", *pattern.TestGroupName)
+	assert.Equal(t, "test_total[a::b]", *pattern.TestCaseName)
+	assert.Equal(t, "tests/test_cart.py::TestCart::TestTotals::test_total[a::b]", *pattern.TestId)
+
+	pattern, err = NewTestPatternFromNodeId("tests/test_cart.py::test_checkout")
+	require.NoError(t, err)
+	assert.Nil(t, pattern.TestGroupName)
+	assert.Equal(t, "test_checkout", *pattern.TestCaseName)
+
+	pattern, err = NewTestPatternFromNodeId("tests/test_cart.py")
+	require.NoError(t, err)
+	assert.Equal(t, "tests/test_cart.py", pattern.Path)
+	assert.Nil(t, pattern.TestCaseName)
+	assert.Nil(t, pattern.TestId)
+
+	_, err = NewTestPatternFromNodeId("")
+	assert.Error(t, err)
+}
+
+func TestTestRuns_SetStatus(t *testing.T) {
+	testRuns := NewTestRuns()
+	testRun, err := testRuns.Add([]TestPattern{}, ModeRunWholeSuite)
+	require.NoError(t, err)
+	assert.Equal(t, StatusRunning, testRun.Status)
+
+	require.NoError(t, testRuns.SetStatus(testRun.Id, StatusCancelled))
+
+	got, err := testRuns.Get(testRun.Id)
+	require.NoError(t, err)
+	assert.Equal(t, StatusCancelled, got.Status)
+	assert.False(t, got.FinishedAt.Before(got.StartedAt))
+
+	assert.Error(t, testRuns.SetStatus(testRun.Id+1000, StatusCompleted))
+}
+
+func TestTestRuns_RestoreKeepsIDsMonotonic(t *testing.T) {
+	testRuns := NewTestRuns()
+	testRuns.Restore(TestRun{Id: 1000, Mode: string(ModeRunWholeSuite), Status: StatusCompleted})
+
+	restored, err := testRuns.Get(1000)
+	require.NoError(t, err)
+	assert.Equal(t, StatusCompleted, restored.Status)
+
+	testRun, err := testRuns.Add(nil, ModeRunWholeSuite)
+	require.NoError(t, err)
+	assert.Greater(t, testRun.Id, 1000)
+
+	mostRecent, _ := testRuns.MostRecent()
+	assert.Equal(t, testRun.Id, mostRecent.Id)
+}
+
+func TestTestRuns_RepeatKeepsPatternsModeAndSeed(t *testing.T) {
+	testRuns := NewTestRuns()
+	patterns, _ := PatternsFromStrings([]string{"test/user_test.rb"})
+	original, err := testRuns.Add(patterns, ModeRunSelectedPatterns)
+	require.NoError(t, err)
+
+	repeated, err := testRuns.Repeat(original.Id)
+	require.NoError(t, err)
+
+	assert.Greater(t, repeated.Id, original.Id)
+	assert.Equal(t, original.Patterns, repeated.Patterns)
+	assert.Equal(t, original.Mode, repeated.Mode)
+	assert.Equal(t, original.Seed, repeated.Seed)
+	stored, _ := testRuns.Get(repeated.Id)
+	assert.Equal(t, original.Seed, stored.Seed)
+
+	loaded, _ := testRuns.Add(nil, ModeLoadedSummary)
+	_, err = testRuns.Repeat(loaded.Id)
+	assert.Error(t, err)
+}
+
+func TestTestRuns_Remove(t *testing.T) {
+	testRuns := NewTestRuns()
+	testRun, _ := testRuns.Add(nil, ModeRunWholeSuite)
+
+	require.NoError(t, testRuns.Remove(testRun.Id))
+
+	_, err := testRuns.Get(testRun.Id)
+	assert.Error(t, err)
+	assert.Error(t, testRuns.Remove(testRun.Id))
+}
@@ -4,6 +4,7 @@ package types
 type StackFrame struct {
 	FilePath        AbsPath
 	Line            int
+	Column          int // Only reported by some runtimes (e.g. V8), 0 when unknown
 	Function        string
 	ChangeIntensity int
 	ChangeReason    string
@@ -9,6 +9,8 @@ type KeyMap struct {
 	SwitchSection key.Binding
 	RunAllTests key.Binding
 	RunFailedTests key.Binding
+	CancelRun key.Binding
+	ShowHungTest key.Binding
 }
 
 var ResultsKeys = KeyMap{
@@ -28,12 +30,22 @@ var ResultsKeys = KeyMap{
 		key.WithKeys("f"),
 		key.WithHelp("f", "run failed tests"),
 	),
+	CancelRun: key.NewBinding(
+		key.WithKeys("x"),
+		key.WithHelp("x", "cancel running tests"),
+	),
+	ShowHungTest: key.NewBinding(
+		key.WithKeys("s"),
+		key.WithHelp("s", "cancel and show hung test"),
+	),
 }
 
 type ResultsSectionKeyMap struct {
 	LineUp key.Binding
 	LineDown key.Binding
 	RunSelectedTest key.Binding
+	SortByFlakiness key.Binding
+	FilterFlaky key.Binding
 }
 var ResultsSectionKeys = ResultsSectionKeyMap{
 	LineUp: key.NewBinding(
@@ -48,4 +60,42 @@ var ResultsSectionKeys = ResultsSectionKeyMap{
 		key.WithKeys("r"),
 		key.WithHelp("r", "run selected test result"),
 	),
-}
\ No newline at end of file
+	SortByFlakiness: key.NewBinding(
+		key.WithKeys("o"),
+		key.WithHelp("o", "sort flakiest tests first"),
+	),
+	FilterFlaky: key.NewBinding(
+		key.WithKeys("y"),
+		key.WithHelp("y", "show only flaky tests"),
+	),
+}
+
+type TestRunsSectionKeyMap struct {
+	LineUp key.Binding
+	LineDown key.Binding
+	ReRun key.Binding
+	ReRunFailures key.Binding
+	Delete key.Binding
+}
+var TestRunsSectionKeys = TestRunsSectionKeyMap{
+	LineUp: key.NewBinding(
+		key.WithKeys("up"),
+		key.WithHelp("up", "previous test run"),
+	),
+	LineDown: key.NewBinding(
+		key.WithKeys("down"),
+		key.WithHelp("down", "next test run"),
+	),
+	ReRun: key.NewBinding(
+		key.WithKeys("r"),
+		key.WithHelp("r", "re-run selected test run"),
+	),
+	ReRunFailures: key.NewBinding(
+		key.WithKeys("F"),
+		key.WithHelp("F", "re-run failures of selected test run"),
+	),
+	Delete: key.NewBinding(
+		key.WithKeys("d"),
+		key.WithHelp("d", "delete selected test run"),
+	),
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/adamakhtar/wing_commander/internal/filesnippet"
 	"github.com/adamakhtar/wing_commander/internal/projectfs"
+	"github.com/adamakhtar/wing_commander/internal/testhistory"
 	"github.com/adamakhtar/wing_commander/internal/testresult"
 	"github.com/adamakhtar/wing_commander/internal/ui/context"
 	"github.com/charmbracelet/bubbles/viewport"
@@ -17,6 +18,8 @@ import (
 const (
 	paddingX = 1
 	paddingY = 0
+	// timelineLength is the number of recent outcomes shown in a test's history
+	timelineLength = 10
 )
 
 type Model struct {
@@ -25,6 +28,7 @@ type Model struct {
 	width      int
 	height     int
 	testResult *testresult.TestResult
+	history    *testhistory.TestHistory // Outcomes of the test in earlier runs, nil when unknown
 	viewport   viewport.Model
 }
 
@@ -77,6 +81,9 @@ func (m Model) buildContent(innerWidth int) string {
 	sb.WriteString("\n")
 	sb.WriteString(m.renderFailureMessage(innerWidth))
 	sb.WriteString("\n")
+	sb.WriteString(m.renderAttempts(innerWidth))
+	sb.WriteString(m.renderHistory(innerWidth))
+	sb.WriteString(m.renderOutput(innerWidth))
 
 	for _, frame := range m.testResult.FilteredBacktrace.Frames {
 		fs := projectfs.GetProjectFS()
@@ -107,6 +114,10 @@ func (m Model) buildContent(innerWidth int) string {
 
 func (m Model) renderTestHeading(innerWidth int) string {
 	testName := m.testResult.GroupName + " " + m.testResult.TestCaseName
+	if m.testResult.TestFilePath == "" {
+		// Results such as a crashed suite have no location
+		return m.ctx.Styles.HeadingTextStyle.Width(innerWidth).Margin(0, 0, 1).Render(testName)
+	}
 
 	fs := projectfs.GetProjectFS()
 	relPath, err := fs.Rel(m.testResult.TestFilePath)
@@ -158,6 +169,84 @@ func (m Model) renderFailureMessage(innerWidth int) string {
 	return ""
 }
 
+// renderAttempts lists every run of a retried test with its failure message.
+func (m Model) renderAttempts(innerWidth int) string {
+	if len(m.testResult.Attempts) == 0 {
+		return ""
+	}
+
+	lines := []string{m.ctx.Styles.HeadingTextStyle.Width(innerWidth).Render("Attempts")}
+	if m.testResult.IsFlaky() {
+		lines = append(lines, m.ctx.Styles.PreviewSection.Flaky.Width(innerWidth).Render(
+			fmt.Sprintf("Flaky: failed %d times, then passed on a retry", len(m.testResult.Attempts)-1)))
+	}
+	for i, attempt := range m.testResult.Attempts {
+		line := fmt.Sprintf(" Attempt %d  %.2fs", i+1, attempt.Duration)
+		lines = append(lines, m.renderOutcomeStatus(attempt.Status)+m.ctx.Styles.PreviewSection.CodeLine.Render(line))
+		if attempt.FailureDetails != "" {
+			lines = append(lines, m.ctx.Styles.BodyTextLight.Width(innerWidth).PaddingLeft(2).Render(attempt.FailureDetails))
+		}
+	}
+
+	return lipgloss.NewStyle().Margin(0, 0, 1).Render(lipgloss.JoinVertical(lipgloss.Left, lines...)) + "\n"
+}
+
+// renderHistory renders the test's most recent outcomes, newest first, and its
+// flakiness.
+func (m Model) renderHistory(innerWidth int) string {
+	if m.history == nil || len(m.history.Outcomes) == 0 {
+		return ""
+	}
+
+	outcomes := m.history.Last(timelineLength)
+	strip := ""
+	for _, outcome := range outcomes {
+		strip += m.renderOutcomeStatus(outcome.Status)
+	}
+	lines := []string{
+		m.ctx.Styles.HeadingTextStyle.Width(innerWidth).Render("History"),
+		strip,
+	}
+
+	if flakiness := m.history.Flakiness; flakiness.IsFlaky() {
+		lines = append(lines, m.ctx.Styles.PreviewSection.Flaky.Width(innerWidth).Render(
+			fmt.Sprintf("Flaky: flipped between pass and fail %d of %d times with no change to its files", flakiness.Flips, flakiness.Comparisons)))
+	}
+
+	for i := len(outcomes) - 1; i >= 0; i-- {
+		outcome := outcomes[i]
+		line := fmt.Sprintf(" %s  %.2fs", outcome.StartedAt.Local().Format("Jan 2 15:04"), outcome.Duration)
+		if outcome.FilesChanged {
+			line += " · files changed"
+		}
+		lines = append(lines, m.renderOutcomeStatus(outcome.Status)+m.ctx.Styles.PreviewSection.CodeLine.Render(line))
+	}
+
+	return lipgloss.NewStyle().Margin(0, 0, 1).Render(lipgloss.JoinVertical(lipgloss.Left, lines...)) + "\n"
+}
+
+func (m Model) renderOutcomeStatus(status testresult.TestStatus) string {
+	switch status {
+	case testresult.StatusPass:
+		return m.ctx.Styles.PreviewSection.OutcomePass.Render("✓")
+	case testresult.StatusFail:
+		return m.ctx.Styles.PreviewSection.OutcomeFail.Render("✗")
+	default:
+		return m.ctx.Styles.PreviewSection.OutcomeSkip.Render("-")
+	}
+}
+
+func (m Model) renderOutput(innerWidth int) string {
+	if m.testResult.Output == "" {
+		return ""
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Top,
+		m.ctx.Styles.HeadingTextStyle.Width(innerWidth).Render("Output"),
+		m.ctx.Styles.PreviewSection.CodeLine.Width(innerWidth).Margin(0, 0, 1).Render(m.testResult.Output),
+	) + "\n"
+}
+
 func (m Model) renderFileSnippet(snippet *filesnippet.FileSnippet, innerWidth int) string {
 	content := ""
 	for _, line := range snippet.Lines {
@@ -198,8 +287,11 @@ func (m *Model) SetSize(width int, height int) {
 	m.viewport.SetContent(m.buildContent(innerWidth))
 }
 
-func (m *Model) SetTestResult(testResult *testresult.TestResult) {
+// SetTestResult shows a test result and, when history is not nil, its
+// outcomes in earlier runs.
+func (m *Model) SetTestResult(testResult *testresult.TestResult, history *testhistory.TestHistory) {
 	m.testResult = testResult
+	m.history = history
 
 	innerWidth, _ := m.innerDimensions(m.width, m.height)
 	m.viewport.SetContent(m.buildContent(innerWidth))
@@ -1,11 +1,20 @@
 package results
 
 import (
+	gocontext "context"
 	"fmt"
+	"path/filepath"
+	"time"
 
+	"github.com/adamakhtar/wing_commander/internal/config"
+	"github.com/adamakhtar/wing_commander/internal/git"
+	"github.com/adamakhtar/wing_commander/internal/history"
+	"github.com/adamakhtar/wing_commander/internal/projectfs"
 	"github.com/adamakhtar/wing_commander/internal/runner"
+	"github.com/adamakhtar/wing_commander/internal/testhistory"
 	"github.com/adamakhtar/wing_commander/internal/testresult"
 	"github.com/adamakhtar/wing_commander/internal/testrun"
+	"github.com/adamakhtar/wing_commander/internal/types"
 	"github.com/adamakhtar/wing_commander/internal/ui/context"
 	"github.com/adamakhtar/wing_commander/internal/ui/filepicker"
 	"github.com/adamakhtar/wing_commander/internal/ui/keys"
@@ -23,16 +32,27 @@ import (
 //================================================
 
 type Model struct {
-	ctx                 *context.Context
-	testRuns            testrun.TestRuns
-	testRunner          *runner.TestRunner
-	testExecutionResult *runner.TestExecutionResult
-	resultsSection      resultssection.Model
-	previewSection      previewsection.Model
-	testRunsSection     testrunssection.Model
-	width               int
-	height              int
-	error               error
+	ctx                  *context.Context
+	testRuns             testrun.TestRuns
+	testRunner           *runner.TestRunner
+	testExecutionResult  *runner.TestExecutionResult         // Results shown in the results section
+	testExecutionResults map[int]*runner.TestExecutionResult // Results of every test run by ID
+	testRunDiffs         map[int]*runner.Diff                // Changes of every finished test run since the previous run, by ID
+	runningTestRunId     int                                 // 0 when no test run is in flight
+	streamedResults      []testresult.TestResult             // Results streamed so far by the in-flight test run
+	cancelTestRun        gocontext.CancelFunc                // Cancels the in-flight test run, nil when none is running
+	lastSuiteTotal       int                                 // Test count of the last whole suite run, used to size the progress bar
+	hungTests            []runner.RunningTest                // Tests of the in-flight run that stopped reporting, oldest first
+	hungTestPreview      *testresult.TestResult              // Hung test shown in the preview until the selection changes
+	history              *history.Store                      // Keeps finished test runs, nil when the history is off
+	testHistory          *testhistory.History                // Outcomes of each test across finished runs, nil until needed again
+	commitDiffs          testhistory.CommitDiffFunc          // Files changed between two commits of the project
+	resultsSection       resultssection.Model
+	previewSection       previewsection.Model
+	testRunsSection      testrunssection.Model
+	width                int
+	height               int
+	error                error
 }
 
 //
@@ -42,14 +62,19 @@ type Model struct {
 func NewModel(ctx *context.Context) Model {
 	testRunner := runner.NewTestRunner(ctx.Config)
 	testRuns := testrun.NewTestRuns()
+	testExecutionResults := make(map[int]*runner.TestExecutionResult)
+	testRunDiffs := make(map[int]*runner.Diff)
 
 	model := Model{
-		ctx:             ctx,
-		testRunner:      testRunner,
-		testRuns:        testRuns,
-		resultsSection:  resultssection.NewModel(ctx, true),
-		previewSection:  previewsection.NewModel(ctx, false),
-		testRunsSection: testrunssection.NewModel(ctx, &testRuns),
+		ctx:                  ctx,
+		testRunner:           testRunner,
+		testRuns:             testRuns,
+		testExecutionResults: testExecutionResults,
+		testRunDiffs:         testRunDiffs,
+		commitDiffs:          testhistory.CachedCommitDiff(diffCommits),
+		resultsSection:       resultssection.NewModel(ctx, true),
+		previewSection:       previewsection.NewModel(ctx, false),
+		testRunsSection:      testrunssection.NewModel(ctx, &testRuns, testExecutionResults, testRunDiffs),
 	}
 	return model
 }
@@ -75,7 +100,26 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// TODO - handle error
 			return m, nil
 		}
-		return m, m.ExecuteTestRunCmd(testRun.Id)
+		return m, m.startTestRun(testRun)
+	case testrunssection.TestRunSelectedMsg:
+		m.showTestRun(msg.TestRunId)
+		return m, nil
+	case testrunssection.ReRunTestRunMsg:
+		testRun, err := m.testRuns.Repeat(msg.TestRunId)
+		if err != nil {
+			log.Debugf("Failed to re-run test run %d: %v", msg.TestRunId, err)
+			return m, nil
+		}
+		return m, m.startTestRun(testRun)
+	case testrunssection.ReRunFailuresMsg:
+		testRun, err := m.addTestRunForFailedTests(m.testExecutionResults[msg.TestRunId])
+		if err != nil {
+			log.Debugf("Failed to re-run failures of test run %d: %v", msg.TestRunId, err)
+			return m, nil
+		}
+		return m, m.startTestRun(testRun)
+	case testrunssection.DeleteTestRunMsg:
+		return m, m.deleteTestRun(msg.TestRunId)
 	case filepicker.TestsSelectedMsg:
 		m.ctx.CurrentScreen = context.ResultsScreen
 		// TODO - consider running a command here that the results screen listens to and it then
@@ -86,37 +130,81 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
-		return m, m.ExecuteTestRunCmd(testRun.Id)
+		return m, m.startTestRun(testRun)
+	case TestExecutionProgressMsg:
+		if msg.TestRunId != m.runningTestRunId {
+			return m, nil
+		}
+		m.resultsSection.SetProgress(msg.Progress, m.expectedTestCount(msg.TestRunId))
+		return m, waitForTestExecutionProgressCmd(msg.TestRunId, msg.progressCh)
+	case TestsHungMsg:
+		if msg.TestRunId != m.runningTestRunId {
+			return m, nil
+		}
+		m.hungTests = msg.RunningTests
+		m.resultsSection.SetHungTests(msg.RunningTests, m.ctx.Config.HungTestTimeout())
+		return m, waitForHungTestsCmd(msg.TestRunId, msg.hungCh)
+	case TestResultStreamedMsg:
+		// Keep draining results of stale runs so their runner isn't blocked
+		if msg.TestRunId == m.runningTestRunId {
+			m.handleStreamedTestResult(msg.TestRunId, msg.TestResult)
+		}
+		return m, waitForStreamedTestResultCmd(msg.TestRunId, msg.resultsCh)
 	case TestExecutionCompletedMsg:
-		m.handleTestExecutionCompletion(msg.TestExecutionResult)
-		return m, nil
+		status := testrun.StatusCompleted
+		if msg.TestExecutionResult.Cancelled {
+			status = testrun.StatusCancelled
+		}
+		m.setTestRunStatus(msg.TestRunId, status)
+		if m.finishTestRun(msg.TestRunId) {
+			m.handleTestExecutionCompletion(msg.TestRunId, msg.TestExecutionResult)
+		}
+		return m, m.saveToHistoryCmd(msg.TestRunId, msg.TestExecutionResult, nil)
 	case TestExecutionFailedMsg:
-		m.error = msg.error
-		return m, nil
+		m.setTestRunStatus(msg.TestRunId, testrun.StatusFailed)
+		if m.finishTestRun(msg.TestRunId) {
+			m.error = msg.error
+		}
+		return m, m.saveToHistoryCmd(msg.TestRunId, nil, msg.error)
 	case tea.KeyMsg:
 		switch {
 		case key.Matches(msg, keys.ResultsKeys.PickFiles):
 			return m, switchToFilePickerCmd
 		case key.Matches(msg, keys.ResultsKeys.SwitchSection):
-			m.resultsSection.ToggleFocus()
-			m.previewSection.ToggleFocus()
+			m.switchSection()
 		case key.Matches(msg, keys.ResultsKeys.RunAllTests):
 			testRun, err := m.testRuns.Add([]testrun.TestPattern{}, testrun.ModeRunWholeSuite)
 			if err != nil {
 				// TODO - handle error
 				return m, nil
 			}
-			return m, m.ExecuteTestRunCmd(testRun.Id)
+			return m, m.startTestRun(testRun)
+		case key.Matches(msg, keys.ResultsKeys.CancelRun):
+			m.CancelTestRun()
+			return m, nil
+		case key.Matches(msg, keys.ResultsKeys.ShowHungTest):
+			if len(m.hungTests) == 0 {
+				return m, nil
+			}
+			m.hungTestPreview = hungTestResult(m.hungTests[0], m.ctx.Config.HungTestTimeout())
+			m.previewSection.SetTestResult(m.hungTestPreview, nil)
+			m.CancelTestRun()
+			return m, nil
 		case key.Matches(msg, keys.ResultsKeys.RunFailedTests):
 			testRun, err := m.AddTestRunForFailedTests()
 			if err != nil {
 				// TODO - handle error
 				return m, nil
 			}
-			return m, m.ExecuteTestRunCmd(testRun.Id)
+			return m, m.startTestRun(testRun)
 		}
 	}
 
+	testRunsSection, testRunsSectionCmd := m.testRunsSection.Update(msg)
+	m.testRunsSection = testRunsSection.(testrunssection.Model)
+	cmds = append(cmds, testRunsSectionCmd)
+
+	selectedTestResultId := m.resultsSection.GetSelectedTestResultId()
 	resultsSection, resultsSectionCmd := m.resultsSection.Update(msg)
 	m.resultsSection = resultsSection.(resultssection.Model)
 	cmds = append(cmds, resultsSectionCmd)
@@ -125,8 +213,13 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	m.previewSection = previewSection.(previewsection.Model)
 	cmds = append(cmds, previewSectionCmd)
 
-	selectedTestResult := m.GetSelectedTestResultId()
-	m.previewSection.SetTestResult(selectedTestResult)
+	// A hung test stays in the preview until another result is selected
+	if m.hungTestPreview != nil && m.resultsSection.GetSelectedTestResultId() != selectedTestResultId {
+		m.hungTestPreview = nil
+	}
+	if m.hungTestPreview == nil {
+		m.showSelectedTestResult()
+	}
 
 	return m, tea.Batch(cmds...)
 }
@@ -156,16 +249,60 @@ type TestExecutionFailedMsg struct {
 	error     error
 }
 
+// TestExecutionProgressMsg carries the progress markers counted so far for an
+// in-flight test run.
+type TestExecutionProgressMsg struct {
+	TestRunId  int
+	Progress   runner.Progress
+	progressCh <-chan runner.Progress
+}
+
+// TestResultStreamedMsg carries a single result an in-flight test run appended
+// to its results stream.
+type TestResultStreamedMsg struct {
+	TestRunId  int
+	TestResult testresult.TestResult
+	resultsCh  <-chan testresult.TestResult
+}
+
+// TestsHungMsg carries the tests of an in-flight test run that have gone quiet
+// for the hung test timeout. RunningTests is empty once results resume.
+type TestsHungMsg struct {
+	TestRunId    int
+	RunningTests []runner.RunningTest
+	hungCh       <-chan []runner.RunningTest
+}
+
 //
 // COMMANDS
 //================================================
 
+// sendLatest hands an update to the UI without blocking the test command. An
+// update the UI hasn't picked up yet is replaced by the newer one.
+func sendLatest[T any](ch chan T, value T) {
+	for {
+		select {
+		case ch <- value:
+			return
+		default:
+			select {
+			case <-ch:
+			default:
+			}
+		}
+	}
+}
+
 func switchToFilePickerCmd() tea.Msg {
 	return OpenFilePickerMsg{}
 }
 
-func (m Model) ExecuteTestRunCmd(testRunId int) tea.Cmd {
+func (m Model) ExecuteTestRunCmd(ctx gocontext.Context, testRunId int, progressCh chan runner.Progress, resultsCh chan testresult.TestResult, hungCh chan []runner.RunningTest) tea.Cmd {
 	return func() tea.Msg {
+		defer close(progressCh)
+		defer close(resultsCh)
+		defer close(hungCh)
+
 		testRun, err := m.testRuns.Get(testRunId)
 		if err != nil {
 			return TestExecutionFailedMsg{TestRunId: testRunId, error: err}
@@ -173,30 +310,128 @@ func (m Model) ExecuteTestRunCmd(testRunId int) tea.Cmd {
 
 		log.Debugf("Executing tests for test run %d: %v", testRunId, testRun.Patterns)
 
-		testExecutionResult, err := m.testRunner.ExecuteTests(testRun)
+		testExecutionResult, err := m.testRunner.ExecuteTests(ctx, testRun, runner.Hooks{
+			OnProgress: func(progress runner.Progress) {
+				sendLatest(progressCh, progress)
+			},
+			OnResult: func(testResult testresult.TestResult) {
+				resultsCh <- testResult
+			},
+			OnStalled: func(running []runner.RunningTest) {
+				sendLatest(hungCh, running)
+			},
+		})
 		if err != nil {
 			log.Debugf("Failed to execute tests for test run %d: %v", testRunId, err)
 			return TestExecutionFailedMsg{TestRunId: testRunId, error: err}
 		}
+		// Retries don't stream, their results are merged into the run's once done
+		err = m.testRunner.RetryFailures(ctx, testRun, testExecutionResult, runner.Hooks{
+			OnStalled: func(running []runner.RunningTest) {
+				sendLatest(hungCh, running)
+			},
+		})
+		if err != nil {
+			log.Debugf("Failed to retry failed tests of test run %d: %v", testRunId, err)
+		}
 		log.Debugf("Tests executed for test run %d: %v, metrics: %v", testRunId, len(testExecutionResult.TestResults), testExecutionResult.Metrics)
 
 		return TestExecutionCompletedMsg{TestRunId: testRunId, TestExecutionResult: testExecutionResult}
 	}
 }
 
+// saveToHistoryCmd saves a finished test run to the history in the
+// background. result is nil when the run failed with runErr.
+func (m Model) saveToHistoryCmd(testRunId int, result *runner.TestExecutionResult, runErr error) tea.Cmd {
+	if m.history == nil {
+		return nil
+	}
+	testRun, err := m.testRuns.Get(testRunId)
+	if err != nil {
+		return nil
+	}
+
+	store := m.history
+	record := history.NewRecord(testRun, result, runErr)
+	return func() tea.Msg {
+		if err := store.Save(record); err != nil {
+			log.Warn("failed to save test run to history", "testRunId", testRunId, "error", err)
+		}
+		return nil
+	}
+}
+
+// deleteFromHistoryCmd deletes a test run from the history in the background.
+func (m Model) deleteFromHistoryCmd(testRunId int) tea.Cmd {
+	if m.history == nil {
+		return nil
+	}
+
+	store := m.history
+	return func() tea.Msg {
+		if err := store.Delete(testRunId); err != nil {
+			log.Warn("failed to delete test run from history", "testRunId", testRunId, "error", err)
+		}
+		return nil
+	}
+}
+
+// waitForTestExecutionProgressCmd waits for the next progress update of a test
+// run. It returns nil once the run has finished and the channel is closed.
+func waitForTestExecutionProgressCmd(testRunId int, progressCh <-chan runner.Progress) tea.Cmd {
+	return func() tea.Msg {
+		progress, ok := <-progressCh
+		if !ok {
+			return nil
+		}
+		return TestExecutionProgressMsg{TestRunId: testRunId, Progress: progress, progressCh: progressCh}
+	}
+}
+
+// waitForStreamedTestResultCmd waits for the next streamed result of a test
+// run. It returns nil once the run has finished and the channel is closed.
+func waitForStreamedTestResultCmd(testRunId int, resultsCh <-chan testresult.TestResult) tea.Cmd {
+	return func() tea.Msg {
+		testResult, ok := <-resultsCh
+		if !ok {
+			return nil
+		}
+		return TestResultStreamedMsg{TestRunId: testRunId, TestResult: testResult, resultsCh: resultsCh}
+	}
+}
+
+// waitForHungTestsCmd waits for the next hung test report of a test run. It
+// returns nil once the run has finished and the channel is closed.
+func waitForHungTestsCmd(testRunId int, hungCh <-chan []runner.RunningTest) tea.Cmd {
+	return func() tea.Msg {
+		running, ok := <-hungCh
+		if !ok {
+			return nil
+		}
+		return TestsHungMsg{TestRunId: testRunId, RunningTests: running, hungCh: hungCh}
+	}
+}
+
 // EXTERNAL FUNCTIONS
 //================================================
 
 func (m *Model) AddTestRunForFailedTests() (testrun.TestRun, error) {
+	return m.addTestRunForFailedTests(m.testExecutionResult)
+}
+
+func (m *Model) addTestRunForFailedTests(testExecutionResult *runner.TestExecutionResult) (testrun.TestRun, error) {
 	// TODO - create a TestResultsCollection type and move this logic to that type
 	var patterns []testrun.TestPattern
 
-	if m.testExecutionResult == nil {
+	if testExecutionResult == nil {
 		return testrun.TestRun{}, fmt.Errorf("no previous test execution available")
 	}
+	if len(testExecutionResult.FailedTests) == 0 {
+		return testrun.TestRun{}, fmt.Errorf("test run %d has no failed tests", testExecutionResult.TestRunId)
+	}
 
-	for _, testResult := range m.testExecutionResult.FailedTests {
-		pattern, err := testrun.NewTestPattern(testResult.TestFilePath.String(), &testResult.TestLineNumber, &testResult.TestCaseName, &testResult.GroupName)
+	for _, testResult := range testExecutionResult.FailedTests {
+		pattern, err := testrun.NewTestPatternForResult(testResult)
 		if err != nil {
 			return testrun.TestRun{}, err
 		}
@@ -213,7 +448,7 @@ func (m *Model) AddTestRunForFailedTests() (testrun.TestRun, error) {
 func (m Model) GetSelectedTestResultId() *testresult.TestResult {
 	testResultId := m.resultsSection.GetSelectedTestResultId()
 
-	if testResultId == -1 {
+	if testResultId == -1 || m.testExecutionResult == nil {
 		return nil
 	}
 
@@ -226,9 +461,325 @@ func (m Model) GetSelectedTestResultId() *testresult.TestResult {
 	return nil
 }
 
-func (m *Model) handleTestExecutionCompletion(testExecutionResult *runner.TestExecutionResult) {
-	m.testExecutionResult = testExecutionResult
-	m.resultsSection.SetRows(testExecutionResult)
+// LoadSummaryFiles shows the results of existing summary files as a completed
+// test run, so they can be browsed and their failures re-run like any other.
+func (m *Model) LoadSummaryFiles(paths []string) error {
+	testRun, err := m.testRuns.Add(nil, testrun.ModeLoadedSummary)
+	if err != nil {
+		return err
+	}
+
+	m.testRunsSection.Select(testRun.Id)
+
+	testExecutionResult, err := m.testRunner.LoadResults(testRun.Id, paths)
+	if err != nil {
+		m.setTestRunStatus(testRun.Id, testrun.StatusFailed)
+		return err
+	}
+	m.setTestRunStatus(testRun.Id, testrun.StatusCompleted)
+	m.handleTestExecutionCompletion(testRun.Id, testExecutionResult)
+	return nil
+}
+
+// RestoreHistory keeps finished test runs in the project's history and
+// restores the runs of earlier sessions, showing the most recent results. It
+// does nothing when the history is off.
+func (m *Model) RestoreHistory() error {
+	if !m.ctx.Config.HistoryEnabled() {
+		return nil
+	}
+
+	dir := filepath.Join(projectfs.GetProjectFS().RootPath.String(), config.HistoryPath())
+	m.history = history.NewStore(dir, m.ctx.Config.HistoryLimit)
+	records, lastId, err := m.history.Load()
+	if err != nil {
+		return err
+	}
+
+	testrun.ContinueIDsAfter(lastId)
+	for _, record := range records {
+		m.testRuns.Restore(record.TestRun)
+		if result := record.TestExecutionResult(); result != nil {
+			m.handleTestExecutionCompletion(record.TestRun.Id, result)
+		}
+	}
+	if mostRecent, ok := m.testRuns.MostRecent(); ok {
+		m.showTestRun(mostRecent.Id)
+	}
+	log.Debug("restored test runs from history", "runs", len(records), "lastId", lastId)
+	return nil
+}
+
+// startTestRun marks the test run as in flight and returns the commands that
+// execute it and stream its progress and results.
+func (m *Model) startTestRun(testRun testrun.TestRun) tea.Cmd {
+	// Only one run at a time, they share the reporter's output files
+	m.CancelTestRun()
+
+	ctx, cancel := gocontext.WithCancel(gocontext.Background())
+	progressCh := make(chan runner.Progress, 1)
+	resultsCh := make(chan testresult.TestResult, 64)
+	hungCh := make(chan []runner.RunningTest, 1)
+	m.recordRevision(testRun.Id)
+	// The previous results stay on screen until the new run streams its first
+	m.testRunsSection.Select(testRun.Id)
+	m.runningTestRunId = testRun.Id
+	m.cancelTestRun = cancel
+	m.streamedResults = nil
+	m.hungTestPreview = nil
+	m.resultsSection.SetProgress(runner.Progress{}, m.expectedTestCount(testRun.Id))
+
+	return tea.Batch(
+		m.ExecuteTestRunCmd(ctx, testRun.Id, progressCh, resultsCh, hungCh),
+		waitForTestExecutionProgressCmd(testRun.Id, progressCh),
+		waitForStreamedTestResultCmd(testRun.Id, resultsCh),
+		waitForHungTestsCmd(testRun.Id, hungCh),
+	)
+}
+
+// handleStreamedTestResult shows the results streamed so far in place of the
+// previous run's, so failures can be inspected before the run finishes.
+func (m *Model) handleStreamedTestResult(testRunId int, testResult testresult.TestResult) {
+	m.streamedResults = append(m.streamedResults, testResult)
+	m.testExecutionResults[testRunId] = runner.NewTestExecutionResult(testRunId, m.streamedResults)
+	if m.testRunsSection.SelectedId() == testRunId {
+		m.testExecutionResult = m.testExecutionResults[testRunId]
+		m.resultsSection.SetRows(m.testExecutionResult, nil)
+		m.resultsSection.SetFlakiness(nil)
+	}
+}
+
+// showTestRun selects a test run and shows its results, or none when it has
+// no results.
+func (m *Model) showTestRun(testRunId int) {
+	m.testRunsSection.Select(testRunId)
+	m.testExecutionResult = m.testExecutionResults[testRunId]
+	m.resultsSection.SetRows(m.testExecutionResult, m.testRunDiffs[testRunId])
+	m.resultsSection.SetFlakiness(m.flakinessOf(testRunId))
+	m.hungTestPreview = nil
+	m.showSelectedTestResult()
+}
+
+// showSelectedTestResult previews the selected result and the test's outcomes
+// up to the shown test run.
+func (m *Model) showSelectedTestResult() {
+	selected := m.GetSelectedTestResultId()
+	if selected == nil || m.testExecutionResult == nil || m.testExecutionResult.TestRunId == m.runningTestRunId {
+		m.previewSection.SetTestResult(selected, nil)
+		return
+	}
+	testHistory := m.getTestHistory().Of(selected.Key(), m.testExecutionResult.TestRunId)
+	m.previewSection.SetTestResult(selected, &testHistory)
+}
+
+// getTestHistory returns the outcomes of each test across the finished test
+// runs, indexing them again after runs finished or were deleted.
+func (m *Model) getTestHistory() *testhistory.History {
+	if m.testHistory != nil {
+		return m.testHistory
+	}
+
+	var runs []testhistory.Run
+	for _, testRun := range m.testRuns.AllRecentFirst() {
+		runs = append(runs, testhistory.Run{TestRun: testRun, Result: m.testExecutionResults[testRun.Id]})
+	}
+	m.testHistory = testhistory.New(projectfs.GetProjectFS().RootPath.String(), runs, m.commitDiffs)
+	return m.testHistory
+}
+
+// flakinessOf returns the flakiness of each test of a finished test run up to
+// that run, by result ID.
+func (m *Model) flakinessOf(testRunId int) map[int]testhistory.Flakiness {
+	result, ok := m.testExecutionResults[testRunId]
+	if !ok || testRunId == m.runningTestRunId {
+		return nil
+	}
+
+	flakiness := make(map[int]testhistory.Flakiness, len(result.TestResults))
+	for _, testResult := range result.TestResults {
+		flakiness[testResult.Id] = m.getTestHistory().Of(testResult.Key(), testRunId).Flakiness
+	}
+	return flakiness
+}
+
+// deleteTestRun removes a finished test run and its results, selecting the
+// next one, and returns the command deleting it from the history.
+func (m *Model) deleteTestRun(testRunId int) tea.Cmd {
+	if testRunId == m.runningTestRunId {
+		return nil
+	}
+
+	next := m.testRunsSection.NeighbourOf(testRunId)
+	if err := m.testRuns.Remove(testRunId); err != nil {
+		log.Debugf("Failed to delete test run %d: %v", testRunId, err)
+		return nil
+	}
+	delete(m.testExecutionResults, testRunId)
+	delete(m.testRunDiffs, testRunId)
+	m.testHistory = nil
+	// Later runs may have been compared with the deleted one
+	for _, testRun := range m.testRuns.AllRecentFirst() {
+		if testRun.Id > testRunId {
+			m.updateDiff(testRun.Id)
+		}
+	}
+	if m.testRunsSection.SelectedId() == testRunId {
+		m.showTestRun(next)
+	}
+	return m.deleteFromHistoryCmd(testRunId)
+}
+
+// updateDiff compares a finished test run with the run before it. Whole suite
+// runs are compared with the previous whole suite run. Other runs are compared
+// with the previous run of any kind, ignoring the tests they didn't run.
+func (m *Model) updateDiff(testRunId int) {
+	delete(m.testRunDiffs, testRunId)
+	testRun, err := m.testRuns.Get(testRunId)
+	current, ok := m.testExecutionResults[testRunId]
+	if err != nil || !ok || testRun.Status == testrun.StatusRunning {
+		return
+	}
+
+	for _, previousRun := range m.testRuns.AllRecentFirst() {
+		if previousRun.Id >= testRunId || (testRun.IsRunningWholeSuite() && !previousRun.IsRunningWholeSuite()) {
+			continue
+		}
+		previous, ok := m.testExecutionResults[previousRun.Id]
+		if !ok || previous.Cancelled || previous.Crashed {
+			continue
+		}
+
+		diff := runner.Compare(previous, current)
+		if !testRun.IsRunningWholeSuite() {
+			diff = diff.WithoutRemoved()
+		}
+		m.testRunDiffs[testRunId] = &diff
+		return
+	}
+}
+
+// switchSection moves the focus on to the next section: the test runs, the
+// results and then the preview.
+func (m *Model) switchSection() {
+	switch {
+	case m.testRunsSection.Focus():
+		m.testRunsSection.ToggleFocus()
+		m.resultsSection.ToggleFocus()
+	case m.resultsSection.Focus():
+		m.resultsSection.ToggleFocus()
+		m.previewSection.ToggleFocus()
+	default:
+		m.previewSection.ToggleFocus()
+		m.testRunsSection.ToggleFocus()
+	}
+}
+
+// CancelTestRun kills the in-flight test run, if any. Its results streamed so
+// far arrive as a cancelled TestExecutionCompletedMsg.
+func (m *Model) CancelTestRun() {
+	if m.cancelTestRun == nil {
+		return
+	}
+	log.Debugf("Cancelling test run %d", m.runningTestRunId)
+	m.cancelTestRun()
+}
+
+// finishTestRun clears the in-flight state when testRunId is the running test
+// run. It returns false for runs that were superseded by a newer one.
+func (m *Model) finishTestRun(testRunId int) bool {
+	if testRunId != m.runningTestRunId {
+		return false
+	}
+	m.cancelTestRun()
+	m.cancelTestRun = nil
+	m.runningTestRunId = 0
+	m.streamedResults = nil
+	m.hungTests = nil
+	m.resultsSection.ClearProgress()
+	return true
+}
+
+// hungTestResult builds a preview of a hung test pointing at its source.
+func hungTestResult(running runner.RunningTest, timeout time.Duration) *testresult.TestResult {
+	result := testresult.NewTestResult(running.GroupName, running.TestCaseName, "")
+	result.TestFilePath = running.TestFilePath
+	result.TestLineNumber = running.TestLineNumber
+	result.FailureDetails = fmt.Sprintf("No result after %s, the run was cancelled while this test was running.", timeout)
+	if running.TestFilePath != "" && running.TestLineNumber > 0 {
+		result.FilteredBacktrace.AppendFrame(types.StackFrame{
+			FilePath: running.TestFilePath,
+			Line:     running.TestLineNumber,
+		})
+	}
+	return &result
+}
+
+func (m *Model) setTestRunStatus(testRunId int, status testrun.Status) {
+	if err := m.testRuns.SetStatus(testRunId, status); err != nil {
+		log.Debugf("Failed to set status of test run %d: %v", testRunId, err)
+	}
+}
+
+// recordRevision records the git commit and uncommitted files a test run
+// starts from, which its history and the flakiness of its tests rely on.
+func (m *Model) recordRevision(testRunId int) {
+	root := projectfs.GetProjectFS().RootPath.String()
+	sha, dirty, err := git.Revision(root)
+	if err != nil {
+		log.Debugf("No git revision for test run %d: %v", testRunId, err)
+		return
+	}
+	dirtyFiles, err := git.DirtyFiles(root)
+	if err != nil {
+		log.Debugf("No uncommitted files for test run %d: %v", testRunId, err)
+		return
+	}
+	if err := m.testRuns.SetRevision(testRunId, sha, dirty, dirtyFiles); err != nil {
+		log.Debugf("Failed to set revision of test run %d: %v", testRunId, err)
+	}
+}
+
+// diffCommits returns the files changed in the project from one commit to
+// another.
+func diffCommits(from string, to string) ([]string, error) {
+	return git.FilesChangedBetween(projectfs.GetProjectFS().RootPath.String(), from, to)
+}
+
+// expectedTestCount estimates how many tests a run will report so the progress
+// bar can be sized. Returns 0 when there is no reasonable estimate.
+func (m Model) expectedTestCount(testRunId int) int {
+	testRun, err := m.testRuns.Get(testRunId)
+	if err != nil {
+		return 0
+	}
+
+	switch testrun.Mode(testRun.Mode) {
+	case testrun.ModeRunWholeSuite:
+		return m.lastSuiteTotal
+	case testrun.ModeReRunSingleFailure, testrun.ModeReRunAllFailures:
+		return len(testRun.Patterns)
+	default:
+		return 0
+	}
+}
+
+func (m *Model) handleTestExecutionCompletion(testRunId int, testExecutionResult *runner.TestExecutionResult) {
+	complete := !testExecutionResult.Cancelled && !testExecutionResult.Crashed
+	if testRun, err := m.testRuns.Get(testRunId); err == nil && complete && testrun.Mode(testRun.Mode) == testrun.ModeRunWholeSuite {
+		m.lastSuiteTotal = testExecutionResult.Metrics.TotalTests
+	}
+	if testExecutionResult.Cancelled && len(testExecutionResult.TestResults) == 0 {
+		// Nothing ran before the cancel, keep showing the previous results
+		return
+	}
+	m.testExecutionResults[testRunId] = testExecutionResult
+	m.testHistory = nil
+	m.updateDiff(testRunId)
+	if m.testRunsSection.SelectedId() == testRunId {
+		m.testExecutionResult = testExecutionResult
+		m.resultsSection.SetRows(testExecutionResult, m.testRunDiffs[testRunId])
+		m.resultsSection.SetFlakiness(m.flakinessOf(testRunId))
+	}
 }
 
 func (m *Model) Prepare() tea.Cmd {
@@ -0,0 +1,56 @@
+package resultssection
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/adamakhtar/wing_commander/internal/projectfs"
+	"github.com/adamakhtar/wing_commander/internal/runner"
+	"github.com/adamakhtar/wing_commander/internal/ui/keys"
+	"github.com/adamakhtar/wing_commander/internal/ui/styles"
+)
+
+// hungTestsListed caps how many hung tests the warning names.
+const hungTestsListed = 3
+
+// renderHungTestWarning renders the banner shown while started tests have gone
+// quiet for the hung test timeout.
+func renderHungTestWarning(running []runner.RunningTest, quietPeriod time.Duration, width int, styles *styles.Styles) string {
+	return styles.ResultsSection.HungTestWarning.Width(width).Render(HungTestWarning(running, quietPeriod))
+}
+
+// HungTestWarning returns the text of the hung test banner, e.g. "⚠ No results
+// for 30s, possibly hung: WorkerTest test_slow (test/worker_test.rb:12) · s:
+// cancel and show test".
+func HungTestWarning(running []runner.RunningTest, quietPeriod time.Duration) string {
+	names := make([]string, 0, hungTestsListed+1)
+	for i, test := range running {
+		if i == hungTestsListed {
+			names = append(names, fmt.Sprintf("+%d more", len(running)-hungTestsListed))
+			break
+		}
+		names = append(names, hungTestName(test))
+	}
+
+	help := keys.ResultsKeys.ShowHungTest.Help()
+	return fmt.Sprintf("⚠ No results for %s, possibly hung: %s · %s: %s",
+		quietPeriod, strings.Join(names, ", "), help.Key, help.Desc)
+}
+
+// hungTestName names a running test by its group, name and location.
+func hungTestName(test runner.RunningTest) string {
+	name := strings.TrimSpace(test.GroupName + " " + test.TestCaseName)
+	if test.TestFilePath == "" {
+		return name
+	}
+
+	location := test.TestFilePath.String()
+	if rel, err := projectfs.GetProjectFS().Rel(test.TestFilePath); err == nil {
+		location = rel.String()
+	}
+	if test.TestLineNumber > 0 {
+		location = fmt.Sprintf("%s:%d", location, test.TestLineNumber)
+	}
+	return fmt.Sprintf("%s (%s)", name, location)
+}
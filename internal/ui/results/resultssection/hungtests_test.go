@@ -0,0 +1,63 @@
+package resultssection
+
+import (
+	"testing"
+	"time"
+
+	"github.com/adamakhtar/wing_commander/internal/parser"
+	"github.com/adamakhtar/wing_commander/internal/projectfs"
+	"github.com/adamakhtar/wing_commander/internal/runner"
+	"github.com/adamakhtar/wing_commander/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHungTestWarning(t *testing.T) {
+	rootPath, _ := types.NewAbsPath("/path/to/project")
+	require.NoError(t, projectfs.InitProjectFS(rootPath, ""))
+
+	runningTest := func(name string, line int) runner.RunningTest {
+		return runner.RunningTest{TestStarted: parser.TestStarted{
+			GroupName:      "WorkerTest",
+			TestCaseName:   name,
+			TestFilePath:   "/path/to/project/test/worker_test.rb",
+			TestLineNumber: line,
+		}}
+	}
+
+	tests := []struct {
+		name    string
+		running []runner.RunningTest
+		want    string
+	}{
+		{
+			name:    "single test",
+			running: []runner.RunningTest{runningTest("test_slow", 12)},
+			want:    "⚠ No results for 30s, possibly hung: WorkerTest test_slow (test/worker_test.rb:12) · s: cancel and show hung test",
+		},
+		{
+			name: "more tests than listed",
+			running: []runner.RunningTest{
+				runningTest("test_a", 1),
+				runningTest("test_b", 2),
+				runningTest("test_c", 3),
+				runningTest("test_d", 4),
+				runningTest("test_e", 5),
+			},
+			want: "⚠ No results for 30s, possibly hung: WorkerTest test_a (test/worker_test.rb:1), " +
+				"WorkerTest test_b (test/worker_test.rb:2), WorkerTest test_c (test/worker_test.rb:3), " +
+				"+2 more · s: cancel and show hung test",
+		},
+		{
+			name:    "without a location",
+			running: []runner.RunningTest{{TestStarted: parser.TestStarted{TestCaseName: "test_slow"}}},
+			want:    "⚠ No results for 30s, possibly hung: test_slow · s: cancel and show hung test",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, HungTestWarning(tt.running, 30*time.Second))
+		})
+	}
+}
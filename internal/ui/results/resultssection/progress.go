@@ -0,0 +1,55 @@
+package resultssection
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/adamakhtar/wing_commander/internal/runner"
+	"github.com/adamakhtar/wing_commander/internal/ui/styles"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const progressBarMaxWidth = 40
+
+// renderProgress renders the progress bar and counter of an in-flight test run.
+// The bar is only shown when the number of tests to expect is known.
+func renderProgress(progress runner.Progress, expectedTotal int, width int, styles *styles.Styles) string {
+	label := styles.ResultsSection.ProgressLabel.Render(ProgressLabel(progress, expectedTotal))
+	if expectedTotal <= 0 || !progress.Started {
+		return label
+	}
+
+	barWidth := min(progressBarMaxWidth, width-lipgloss.Width(label)-1)
+	if barWidth <= 0 {
+		return label
+	}
+
+	filled := min(barWidth, barWidth*progress.Completed()/expectedTotal)
+	filledStyle := styles.ResultsSection.ProgressBarPassed
+	if progress.Failed > 0 {
+		filledStyle = styles.ResultsSection.ProgressBarFailed
+	}
+	bar := filledStyle.Render(strings.Repeat("█", filled)) +
+		styles.ResultsSection.ProgressBarEmpty.Render(strings.Repeat("░", barWidth-filled))
+
+	return bar + " " + label
+}
+
+// ProgressLabel returns the counter shown next to the progress bar, e.g.
+// "12/40 · 10 passed · 1 failed · 1 skipped".
+func ProgressLabel(progress runner.Progress, expectedTotal int) string {
+	if !progress.Started {
+		return "Starting test run…"
+	}
+
+	counter := fmt.Sprintf("%d tests", progress.Completed())
+	if expectedTotal > 0 {
+		counter = fmt.Sprintf("%d/%d", progress.Completed(), expectedTotal)
+	}
+	if progress.Finished {
+		counter += " · reading results…"
+	}
+
+	return fmt.Sprintf("%s · %d passed · %d failed · %d skipped",
+		counter, progress.Passed, progress.Failed, progress.Skipped)
+}
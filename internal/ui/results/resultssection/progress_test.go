@@ -0,0 +1,46 @@
+package resultssection
+
+import (
+	"testing"
+
+	"github.com/adamakhtar/wing_commander/internal/runner"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgressLabel(t *testing.T) {
+	tests := []struct {
+		name          string
+		progress      runner.Progress
+		expectedTotal int
+		want          string
+	}{
+		{
+			name:     "before the reporter starts",
+			progress: runner.Progress{},
+			want:     "Starting test run…",
+		},
+		{
+			name:     "unknown total",
+			progress: runner.Progress{Started: true, Passed: 10, Failed: 1, Skipped: 1},
+			want:     "12 tests · 10 passed · 1 failed · 1 skipped",
+		},
+		{
+			name:          "known total",
+			progress:      runner.Progress{Started: true, Passed: 10, Failed: 1, Skipped: 1},
+			expectedTotal: 40,
+			want:          "12/40 · 10 passed · 1 failed · 1 skipped",
+		},
+		{
+			name:          "reporter finished",
+			progress:      runner.Progress{Started: true, Finished: true, Passed: 2},
+			expectedTotal: 2,
+			want:          "2/2 · reading results… · 2 passed · 0 failed · 0 skipped",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ProgressLabel(tt.progress, tt.expectedTotal))
+		})
+	}
+}
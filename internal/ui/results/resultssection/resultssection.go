@@ -1,10 +1,13 @@
 package resultssection
 
 import (
+	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/adamakhtar/wing_commander/internal/runner"
+	"github.com/adamakhtar/wing_commander/internal/testhistory"
 	"github.com/adamakhtar/wing_commander/internal/testresult"
 	"github.com/adamakhtar/wing_commander/internal/testrun"
 	"github.com/adamakhtar/wing_commander/internal/ui/context"
@@ -36,6 +39,7 @@ var customBorder = table.Border{
 const (
 	columnKeyTestName        = "test_name"
 	columnKeyTestResult    = "test_result"
+	columnKeyChange          = "change"
 	columnKeyMetaId          = "test_id"
 	columnKeyMetaTestPattern = "test_pattern"
 )
@@ -43,14 +47,25 @@ const (
 const (
 	paddingX = 1
 	paddingY = 0
+	// changeBadgeWidth fits the longest change badge, e.g. "FIXED"
+	changeBadgeWidth = 5
 )
 
 type Model struct {
-	ctx          *context.Context
-	focus        bool
-	resultsTable table.Model
-	width        int
-	height       int
+	ctx                 *context.Context
+	focus               bool
+	resultsTable        table.Model
+	testExecutionResult *runner.TestExecutionResult
+	diff                *runner.Diff                  // Changes since the previous run, nil when not compared
+	flakiness           map[int]testhistory.Flakiness // Flakiness of the results by ID
+	sortByFlakiness     bool                          // Flakiest tests first
+	onlyFlaky           bool                          // Hide tests that aren't flaky
+	progress            *runner.Progress              // nil when no test run is in flight
+	expectedTotal       int                           // Expected number of tests in the in-flight run, 0 when unknown
+	hungTests           []runner.RunningTest
+	hungTimeout         time.Duration
+	width               int
+	height              int
 }
 
 //
@@ -58,7 +73,7 @@ type Model struct {
 //================================================
 
 func NewModel(ctx *context.Context, focus bool) Model {
-	columns := getColumnConfiguration(3, 15, 15, &ctx.Styles)
+	columns := getColumnConfiguration(3, changeBadgeWidth, 15, 15, &ctx.Styles)
 
 	resultsTable := table.New(columns).
 		Focused(true).
@@ -102,6 +117,12 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if ok {
 				cmd = runTestCmd(testPattern)
 			}
+		case key.Matches(msg, keys.ResultsSectionKeys.SortByFlakiness):
+			m.sortByFlakiness = !m.sortByFlakiness
+			m.refreshRows()
+		case key.Matches(msg, keys.ResultsSectionKeys.FilterFlaky):
+			m.onlyFlaky = !m.onlyFlaky
+			m.refreshRows()
 		}
 	}
 	return m, cmd
@@ -115,7 +136,17 @@ func (m Model) View() string {
 		panelStyle = panelStyle.Inherit(m.ctx.Styles.BorderMuted)
 	}
 
-	return panelStyle.Render(m.resultsTable.View())
+	if m.progress == nil {
+		return panelStyle.Render(m.resultsTable.View())
+	}
+
+	header := renderProgress(*m.progress, m.expectedTotal, m.width-2*paddingX, &m.ctx.Styles)
+	if len(m.hungTests) > 0 {
+		header = lipgloss.JoinVertical(lipgloss.Left, header,
+			renderHungTestWarning(m.hungTests, m.hungTimeout, m.width-2*paddingX, &m.ctx.Styles))
+	}
+	resultsTable := m.resultsTable.WithMinimumHeight(m.height - 2*paddingY - lipgloss.Height(header))
+	return panelStyle.Render(lipgloss.JoinVertical(lipgloss.Left, header, resultsTable.View()))
 }
 
 //
@@ -148,22 +179,49 @@ func (m *Model) SetSize(width int, height int) {
 
 	failureCauseWidth := 3
 	groupNameWidth := 15
-	testNameWidth := width - failureCauseWidth - groupNameWidth
+	testNameWidth := width - failureCauseWidth - changeBadgeWidth - groupNameWidth
 
-	columns := getColumnConfiguration(failureCauseWidth, groupNameWidth, testNameWidth, &m.ctx.Styles)
+	columns := getColumnConfiguration(failureCauseWidth, changeBadgeWidth, groupNameWidth, testNameWidth, &m.ctx.Styles)
 	m.resultsTable.WithColumns(columns)
 }
 
-func (m *Model) SetRows(testExecutionResult *runner.TestExecutionResult) {
-	if testExecutionResult == nil {
+// SetRows shows the results of a test run. diff, when not nil, compares them
+// with the previous run and adds a badge to every test whose outcome changed.
+func (m *Model) SetRows(testExecutionResult *runner.TestExecutionResult, diff *runner.Diff) {
+	m.testExecutionResult = testExecutionResult
+	m.diff = diff
+	m.refreshRows()
+}
+
+// SetFlakiness sets the flakiness of the shown results by ID, which the flaky
+// sort and filter use.
+func (m *Model) SetFlakiness(flakiness map[int]testhistory.Flakiness) {
+	m.flakiness = flakiness
+	m.refreshRows()
+}
+
+func (m *Model) refreshRows() {
+	if m.testExecutionResult == nil {
 		m.resultsTable = m.resultsTable.WithRows([]table.Row{})
 		return
 	}
 
-	results := make([]testresult.TestResult, len(testExecutionResult.TestResults))
-	copy(results, testExecutionResult.TestResults)
+	results := make([]testresult.TestResult, 0, len(m.testExecutionResult.TestResults))
+	for _, result := range m.testExecutionResult.TestResults {
+		if !m.onlyFlaky || result.IsFlaky() || m.flakiness[result.Id].IsFlaky() {
+			results = append(results, result)
+		}
+	}
 
 	sort.SliceStable(results, func(i, j int) bool {
+		if m.sortByFlakiness {
+			fi := m.flakiness[results[i].Id].Score()
+			fj := m.flakiness[results[j].Id].Score()
+			if fi != fj {
+				return fi > fj
+			}
+		}
+
 		pi := sortPriority(results[i])
 		pj := sortPriority(results[j])
 		if pi != pj {
@@ -187,19 +245,27 @@ func (m *Model) SetRows(testExecutionResult *runner.TestExecutionResult) {
 
 	rows := []table.Row{}
 	for _, test := range results {
-		testPattern, err := testrun.NewTestPattern(
-			test.TestFilePath.String(),
-			&test.TestLineNumber,
-			&test.TestCaseName,
-			&test.GroupName,
-		)
+		testPattern, err := testrun.NewTestPatternForResult(test)
 		if err != nil {
 			continue
 		}
 
+		var change runner.Change
+		if m.diff != nil {
+			change, _ = m.diff.ChangeOf(test.Id)
+		}
+
+		testName := test.GroupName + " " + test.TestCaseName
+		if flakiness := m.flakiness[test.Id]; flakiness.IsFlaky() {
+			testName += fmt.Sprintf(" (flaky %d/%d)", flakiness.Flips, flakiness.Comparisons)
+		} else if test.IsFlaky() {
+			testName += " (flaky, passed on retry)"
+		}
+
 		row := table.NewRow(table.RowData{
 			columnKeyTestResult:    renderFailureType(test.AbbreviatedResult(), &m.ctx.Styles),
-			columnKeyTestName:        test.GroupName + " " + test.TestCaseName,
+			columnKeyChange:          renderChange(change, &m.ctx.Styles),
+			columnKeyTestName:        testName,
 			columnKeyMetaId:          test.Id,
 			columnKeyMetaTestPattern: testPattern,
 		}).WithStyle(lipgloss.NewStyle().Foreground(m.ctx.Styles.ResultsSection.TableRowTextColor))
@@ -209,6 +275,28 @@ func (m *Model) SetRows(testExecutionResult *runner.TestExecutionResult) {
 	m.resultsTable = m.resultsTable.WithRows(rows)
 }
 
+// SetProgress shows a progress bar and counter above the results while a test
+// run is in flight.
+func (m *Model) SetProgress(progress runner.Progress, expectedTotal int) {
+	m.progress = &progress
+	m.expectedTotal = expectedTotal
+}
+
+// SetHungTests shows a warning naming tests that have gone quiet for the hung
+// test timeout. Passing no tests hides it.
+func (m *Model) SetHungTests(running []runner.RunningTest, timeout time.Duration) {
+	m.hungTests = running
+	m.hungTimeout = timeout
+}
+
+// ClearProgress hides the progress bar and hung test warning once the test run
+// has finished.
+func (m *Model) ClearProgress() {
+	m.progress = nil
+	m.expectedTotal = 0
+	m.hungTests = nil
+}
+
 func (m Model) GetSelectedTestResultId() int {
 	highlightedRow := m.resultsTable.HighlightedRow()
 	if len(highlightedRow.Data) == 0 {
@@ -266,12 +354,13 @@ func (m Model) isFocused() bool {
 // INTERNAL FUNCTIONS
 //================================================
 
-func getColumnConfiguration(failureCauseWidth int, groupNameWidth int, testNameWidth int, styles *styles.Styles) []table.Column {
+func getColumnConfiguration(failureCauseWidth int, changeWidth int, groupNameWidth int, testNameWidth int, styles *styles.Styles) []table.Column {
 	// TODO - syling - refer to https://github.com/Evertras/bubble-table/blob/main/examples/features/main.go
 	headerStyle := lipgloss.NewStyle().Foreground(styles.ResultsSection.TableHeaderTextColor)
 
 	return []table.Column{
 		table.NewColumn(columnKeyTestResult, "", failureCauseWidth).WithStyle(headerStyle.Align(lipgloss.Center)),
+		table.NewColumn(columnKeyChange, "Δ", changeWidth).WithStyle(headerStyle.Align(lipgloss.Center)),
 		// table.NewFlexColumn(columnKeyGroupName, "Group name", groupNameWidth).WithStyle(headerStyle),
 		table.NewFlexColumn(columnKeyTestName, "Test name", testNameWidth).WithStyle(headerStyle),
 	}
@@ -294,6 +383,43 @@ func renderFailureType(result string, styles *styles.Styles) string {
 	}
 }
 
+// changeBadge returns the label of the badge marking how a test changed since
+// the previous run, empty when it didn't.
+func changeBadge(change runner.Change) string {
+	switch change {
+	case runner.ChangeNewlyFailed:
+		return "NEW"
+	case runner.ChangeFixed:
+		return "FIXED"
+	case runner.ChangeStillFailingSameError:
+		return "SAME"
+	case runner.ChangeStillFailingDifferentError:
+		return "DIFF"
+	case runner.ChangeAdded:
+		return "ADDED"
+	default:
+		return ""
+	}
+}
+
+func renderChange(change runner.Change, styles *styles.Styles) string {
+	label := changeBadge(change)
+	switch change {
+	case runner.ChangeNewlyFailed:
+		return styles.ResultsSection.NewlyFailedBadge.Width(changeBadgeWidth).Render(label)
+	case runner.ChangeFixed:
+		return styles.ResultsSection.FixedBadge.Width(changeBadgeWidth).Render(label)
+	case runner.ChangeStillFailingSameError:
+		return styles.ResultsSection.StillFailingBadge.Width(changeBadgeWidth).Render(label)
+	case runner.ChangeStillFailingDifferentError:
+		return styles.ResultsSection.DifferentErrorBadge.Width(changeBadgeWidth).Render(label)
+	case runner.ChangeAdded:
+		return styles.ResultsSection.AddedBadge.Width(changeBadgeWidth).Render(label)
+	default:
+		return ""
+	}
+}
+
 func sortPriority(result testresult.TestResult) int {
 	switch result.Status {
 	case testresult.StatusFail:
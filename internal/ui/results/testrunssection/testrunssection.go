@@ -3,10 +3,15 @@ package testrunssection
 import (
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/adamakhtar/wing_commander/internal/runner"
 	"github.com/adamakhtar/wing_commander/internal/testrun"
 	"github.com/adamakhtar/wing_commander/internal/ui/context"
+	"github.com/adamakhtar/wing_commander/internal/ui/keys"
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 )
 
 const (
@@ -14,17 +19,22 @@ const (
 )
 
 type Model struct {
-	ctx      *context.Context
-	testRuns *testrun.TestRuns
-	focus    bool
-	width    int
-	height   int
+	ctx        *context.Context
+	testRuns   *testrun.TestRuns
+	results    map[int]*runner.TestExecutionResult // Results of the test runs by ID, shared with the results screen
+	diffs      map[int]*runner.Diff                // Changes since the previous run by test run ID, shared with the results screen
+	selectedId int                                 // 0 when no test run is selected
+	focus      bool
+	width      int
+	height     int
 }
 
-func NewModel(ctx *context.Context, testRuns *testrun.TestRuns) Model {
+func NewModel(ctx *context.Context, testRuns *testrun.TestRuns, results map[int]*runner.TestExecutionResult, diffs map[int]*runner.Diff) Model {
 	return Model{
 		ctx:      ctx,
 		testRuns: testRuns,
+		results:  results,
+		diffs:    diffs,
 		focus:    false,
 		width:    0,
 		height:   0,
@@ -36,7 +46,38 @@ func (m Model) Init() tea.Cmd {
 }
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	return m, nil
+	if m.isBlurred() {
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, keys.TestRunsSectionKeys.LineUp):
+			if m.moveCursor(-1) {
+				cmd = selectTestRunCmd(m.selectedId)
+			}
+		case key.Matches(msg, keys.TestRunsSectionKeys.LineDown):
+			if m.moveCursor(1) {
+				cmd = selectTestRunCmd(m.selectedId)
+			}
+		case key.Matches(msg, keys.TestRunsSectionKeys.ReRun):
+			if m.selectedId != 0 {
+				cmd = reRunTestRunCmd(m.selectedId)
+			}
+		case key.Matches(msg, keys.TestRunsSectionKeys.ReRunFailures):
+			if m.selectedId != 0 {
+				cmd = reRunFailuresCmd(m.selectedId)
+			}
+		case key.Matches(msg, keys.TestRunsSectionKeys.Delete):
+			if m.selectedId != 0 {
+				cmd = deleteTestRunCmd(m.selectedId)
+			}
+		}
+	}
+	return m, cmd
 }
 
 func (m Model) View() string {
@@ -46,18 +87,121 @@ func (m Model) View() string {
 	sb.WriteString(m.ctx.Styles.HeadingTextStyle.Width(innerWidth).Render("Recent Test Runs"))
 	sb.WriteString("\n")
 
-	for _, testRun := range m.testRuns.AllRecentFirst() {
-		sb.WriteString(m.ctx.Styles.TestRunsSection.Label.Width(innerWidth).Render(Label(testRun)))
+	testRuns := m.testRuns.AllRecentFirst()
+	now := time.Now()
+	entries := make([]string, len(testRuns))
+	for i, testRun := range testRuns {
+		entries[i] = m.renderEntry(testRun, innerWidth, now)
+	}
+	first, last := m.visibleRange(entries, indexOf(testRuns, m.selectedId))
+	for _, entry := range entries[first:last] {
+		sb.WriteString(entry)
+		sb.WriteString("\n")
+	}
+
+	panelStyle := m.ctx.Styles.Border.Padding(0, paddingX).Width(m.width).Height(m.height)
+	if m.isFocused() {
+		panelStyle = panelStyle.Inherit(m.ctx.Styles.BorderActive)
+	} else {
+		panelStyle = panelStyle.Inherit(m.ctx.Styles.BorderMuted)
+	}
+	return panelStyle.Render(strings.TrimSuffix(sb.String(), "\n"))
+}
+
+//
+// MESSAGES & HANDLERS
+//================================================
+
+// TestRunSelectedMsg asks for the results of a test run to be shown.
+type TestRunSelectedMsg struct {
+	TestRunId int
+}
+
+// ReRunTestRunMsg asks for a test run to be run again exactly as before.
+type ReRunTestRunMsg struct {
+	TestRunId int
+}
+
+// ReRunFailuresMsg asks for the failed tests of a test run to be run again.
+type ReRunFailuresMsg struct {
+	TestRunId int
+}
+
+// DeleteTestRunMsg asks for a test run and its results to be deleted.
+type DeleteTestRunMsg struct {
+	TestRunId int
+}
+
+//
+// COMMANDS
+//================================================
+
+func selectTestRunCmd(testRunId int) tea.Cmd {
+	return func() tea.Msg {
+		return TestRunSelectedMsg{TestRunId: testRunId}
+	}
+}
+
+func reRunTestRunCmd(testRunId int) tea.Cmd {
+	return func() tea.Msg {
+		return ReRunTestRunMsg{TestRunId: testRunId}
 	}
+}
+
+func reRunFailuresCmd(testRunId int) tea.Cmd {
+	return func() tea.Msg {
+		return ReRunFailuresMsg{TestRunId: testRunId}
+	}
+}
 
-	return m.ctx.Styles.Border.Padding(0, paddingX).Inherit(m.ctx.Styles.BorderMuted).Width(m.width).Height(m.height).Render(sb.String())
+func deleteTestRunCmd(testRunId int) tea.Cmd {
+	return func() tea.Msg {
+		return DeleteTestRunMsg{TestRunId: testRunId}
+	}
 }
 
+//
+// EXTERNAL FUNCTIONS
+//================================================
+
 func (m *Model) SetSize(width int, height int) {
 	m.width = width
 	m.height = height
 }
 
+// Select moves the cursor to a test run without showing its results.
+func (m *Model) Select(testRunId int) {
+	m.selectedId = testRunId
+}
+
+// SelectedId returns the ID of the test run under the cursor, 0 when none is.
+func (m Model) SelectedId() int {
+	return m.selectedId
+}
+
+// NeighbourOf returns the test run to select once testRunId is deleted: the
+// next older run, or the next newer one when it is the oldest. Returns 0 when
+// there is no other run.
+func (m Model) NeighbourOf(testRunId int) int {
+	testRuns := m.testRuns.AllRecentFirst()
+	for i, testRun := range testRuns {
+		if testRun.Id != testRunId {
+			continue
+		}
+		if i+1 < len(testRuns) {
+			return testRuns[i+1].Id
+		}
+		if i > 0 {
+			return testRuns[i-1].Id
+		}
+	}
+	return 0
+}
+
+func (m *Model) ToggleFocus() {
+	m.focus = !m.focus
+}
+
 func (m Model) Focus() bool {
 	return m.focus
 }
@@ -70,7 +214,102 @@ func (m Model) isFocused() bool {
 	return m.focus
 }
 
+//
+// INTERNAL FUNCTIONS
+//================================================
+
+// moveCursor moves the cursor by delta runs, recent first. It returns false
+// when the selection didn't change.
+func (m *Model) moveCursor(delta int) bool {
+	testRuns := m.testRuns.AllRecentFirst()
+	if len(testRuns) == 0 {
+		return false
+	}
+
+	index := indexOf(testRuns, m.selectedId)
+	if index == -1 {
+		m.selectedId = testRuns[0].Id
+		return true
+	}
+
+	index = max(0, min(len(testRuns)-1, index+delta))
+	if testRuns[index].Id == m.selectedId {
+		return false
+	}
+	m.selectedId = testRuns[index].Id
+	return true
+}
+
+// renderEntry renders a test run's label, summary and, when it was compared
+// with the previous run, the changes since then.
+func (m Model) renderEntry(testRun testrun.TestRun, width int, now time.Time) string {
+	labelStyle := m.ctx.Styles.TestRunsSection.Label.Width(width)
+	summaryStyle := m.ctx.Styles.TestRunsSection.Summary.Width(width)
+	diffStyle := m.ctx.Styles.TestRunsSection.Diff.Width(width)
+	if testRun.Id == m.selectedId {
+		labelStyle = labelStyle.Inherit(m.ctx.Styles.TestRunsSection.Selected)
+		summaryStyle = summaryStyle.Inherit(m.ctx.Styles.TestRunsSection.Selected)
+		diffStyle = diffStyle.Inherit(m.ctx.Styles.TestRunsSection.Selected)
+	}
+
+	lines := []string{
+		labelStyle.Render(Label(testRun)),
+		summaryStyle.Render(Summary(testRun, m.results[testRun.Id], now)),
+	}
+	if diff, ok := m.diffs[testRun.Id]; ok {
+		lines = append(lines, diffStyle.Render(DiffSummary(*diff)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// visibleRange returns the slice of entries that fits the panel, scrolled so
+// the selected entry is visible.
+func (m Model) visibleRange(entries []string, selected int) (int, int) {
+	// One line goes to the heading
+	available := max(1, m.height-1)
+	heights := make([]int, len(entries))
+	for i, entry := range entries {
+		heights[i] = lipgloss.Height(entry)
+	}
+
+	first := 0
+	for first < selected && sum(heights[first:selected+1]) > available {
+		first++
+	}
+	last, used := first, 0
+	for last < len(entries) && (last == first || used+heights[last] <= available) {
+		used += heights[last]
+		last++
+	}
+	return first, last
+}
+
+func sum(values []int) int {
+	total := 0
+	for _, value := range values {
+		total += value
+	}
+	return total
+}
+
+func indexOf(testRuns []testrun.TestRun, testRunId int) int {
+	for i, testRun := range testRuns {
+		if testRun.Id == testRunId {
+			return i
+		}
+	}
+	return -1
+}
+
 func Label(t testrun.TestRun) string {
+	label := modeLabel(t)
+	if t.Status == testrun.StatusCancelled {
+		return label + " (cancelled)"
+	}
+	return label
+}
+
+func modeLabel(t testrun.TestRun) string {
 	switch testrun.Mode(t.Mode) {
 	case testrun.ModeRunWholeSuite:
 		return "Run whole suite"
@@ -80,6 +319,8 @@ func Label(t testrun.TestRun) string {
 		return "Re-run failure"
 	case testrun.ModeReRunAllFailures:
 		return "Re-run all failed"
+	case testrun.ModeLoadedSummary:
+		return "Loaded summary"
 	default:
 		return formatSelectedPatternsLabel(len(t.Patterns))
 	}
@@ -95,3 +336,90 @@ func formatSelectedPatternsLabel(count int) string {
 		return fmt.Sprintf("Run %d patterns", count)
 	}
 }
+
+// Summary describes the outcome of a test run, e.g. "✓ 12 ✗ 3 · 1 flaky · 1.2s · 5m ago".
+// result is nil when the run has no results.
+func Summary(t testrun.TestRun, result *runner.TestExecutionResult, now time.Time) string {
+	var parts []string
+	switch {
+	case t.Status == testrun.StatusRunning:
+		parts = append(parts, "Running")
+	case result != nil:
+		parts = append(parts, fmt.Sprintf("✓ %d ✗ %d", result.Metrics.PassedTests, result.Metrics.FailedTests))
+		if result.Metrics.FlakyTests > 0 {
+			parts = append(parts, fmt.Sprintf("%d flaky", result.Metrics.FlakyTests))
+		}
+	case t.Status == testrun.StatusFailed:
+		parts = append(parts, "Failed to run")
+	default:
+		parts = append(parts, "No results")
+	}
+
+	if duration := t.Duration(); duration > 0 {
+		parts = append(parts, FormatDuration(duration))
+	}
+	if !t.StartedAt.IsZero() {
+		parts = append(parts, RelativeTime(t.StartedAt, now))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// DiffSummary counts how the tests of a test run changed since the previous
+// run, e.g. "2 newly failed · 1 fixed · 3 still failing (1 different error)".
+func DiffSummary(diff runner.Diff) string {
+	var parts []string
+	if count := diff.Count(runner.ChangeNewlyFailed); count > 0 {
+		parts = append(parts, fmt.Sprintf("%d newly failed", count))
+	}
+	if count := diff.Count(runner.ChangeFixed); count > 0 {
+		parts = append(parts, fmt.Sprintf("%d fixed", count))
+	}
+	different := diff.Count(runner.ChangeStillFailingDifferentError)
+	if still := diff.Count(runner.ChangeStillFailingSameError) + different; still > 0 {
+		part := fmt.Sprintf("%d still failing", still)
+		if different > 0 {
+			part += fmt.Sprintf(" (%d different error)", different)
+		}
+		parts = append(parts, part)
+	}
+	if count := diff.Count(runner.ChangeAdded); count > 0 {
+		parts = append(parts, fmt.Sprintf("%d added", count))
+	}
+	if count := diff.Count(runner.ChangeRemoved); count > 0 {
+		parts = append(parts, fmt.Sprintf("%d removed", count))
+	}
+
+	if len(parts) == 0 {
+		return "No changes since the previous run"
+	}
+	return strings.Join(parts, " · ")
+}
+
+// FormatDuration formats how long a test run took, e.g. "850ms", "1.2s" or
+// "2m5s".
+func FormatDuration(d time.Duration) string {
+	switch {
+	case d < time.Second:
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	case d < time.Minute:
+		return fmt.Sprintf("%.1fs", d.Seconds())
+	default:
+		return d.Round(time.Second).String()
+	}
+}
+
+// RelativeTime formats how long ago t was, e.g. "just now", "5m ago" or
+// "3d ago".
+func RelativeTime(t time.Time, now time.Time) string {
+	d := now.Sub(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	}
+}
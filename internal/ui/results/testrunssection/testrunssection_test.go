@@ -2,8 +2,12 @@ package testrunssection
 
 import (
 	"testing"
+	"time"
 
+	"github.com/adamakhtar/wing_commander/internal/runner"
+	"github.com/adamakhtar/wing_commander/internal/testresult"
 	"github.com/adamakhtar/wing_commander/internal/testrun"
+	tea "github.com/charmbracelet/bubbletea"
 )
 
 func TestLabel(t *testing.T) {
@@ -12,6 +16,7 @@ func TestLabel(t *testing.T) {
 	tests := []struct {
 		name     string
 		mode     testrun.Mode
+		status   testrun.Status
 		patterns []testrun.TestPattern
 		want     string
 	}{
@@ -57,6 +62,19 @@ func TestLabel(t *testing.T) {
 			}(),
 			want: "Re-run all failed",
 		},
+		{
+			name:     "loaded summary",
+			mode:     testrun.ModeLoadedSummary,
+			patterns: nil,
+			want:     "Loaded summary",
+		},
+		{
+			name:     "cancelled run",
+			mode:     testrun.ModeRunWholeSuite,
+			status:   testrun.StatusCancelled,
+			patterns: []testrun.TestPattern{},
+			want:     "Run whole suite (cancelled)",
+		},
 	}
 
 	for _, tt := range tests {
@@ -66,6 +84,7 @@ func TestLabel(t *testing.T) {
 			run := testrun.TestRun{
 				Patterns: tt.patterns,
 				Mode:     string(tt.mode),
+				Status:   tt.status,
 			}
 
 			if got := Label(run); got != tt.want {
@@ -74,3 +93,178 @@ func TestLabel(t *testing.T) {
 		})
 	}
 }
+
+func TestSummary(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 11, 14, 21, 30, 0, 0, time.UTC)
+	startedAt := now.Add(-5 * time.Minute)
+	finished := testrun.TestRun{
+		Status:     testrun.StatusCompleted,
+		StartedAt:  startedAt,
+		FinishedAt: startedAt.Add(1200 * time.Millisecond),
+	}
+	result := runner.NewTestExecutionResult(1, []testresult.TestResult{
+		testresult.NewTestResult("UserTest", "test_a", testresult.StatusPass),
+		testresult.NewTestResult("UserTest", "test_b", testresult.StatusPass),
+		testresult.NewTestResult("UserTest", "test_c", testresult.StatusFail),
+	})
+
+	if got, want := Summary(finished, result, now), "✓ 2 ✗ 1 · 1.2s · 5m ago"; got != want {
+		t.Fatalf("Summary() = %q, want %q", got, want)
+	}
+
+	running := testrun.TestRun{Status: testrun.StatusRunning, StartedAt: now}
+	if got, want := Summary(running, nil, now), "Running · just now"; got != want {
+		t.Fatalf("Summary() = %q, want %q", got, want)
+	}
+
+	flaky := testresult.NewTestResult("UserTest", "test_d", testresult.StatusPass)
+	flaky.Flaky = true
+	withFlaky := runner.NewTestExecutionResult(1, []testresult.TestResult{flaky})
+	if got, want := Summary(finished, withFlaky, now), "✓ 1 ✗ 0 · 1 flaky · 1.2s · 5m ago"; got != want {
+		t.Fatalf("Summary() = %q, want %q", got, want)
+	}
+
+	failed := finished
+	failed.Status = testrun.StatusFailed
+	if got, want := Summary(failed, nil, now), "Failed to run · 1.2s · 5m ago"; got != want {
+		t.Fatalf("Summary() = %q, want %q", got, want)
+	}
+}
+
+func TestDiffSummary(t *testing.T) {
+	t.Parallel()
+
+	failed := func(name string, details string) testresult.TestResult {
+		result := testresult.NewTestResult("UserTest", name, testresult.StatusFail)
+		result.FailureDetails = details
+		return result
+	}
+	previous := runner.NewTestExecutionResult(1, []testresult.TestResult{
+		testresult.NewTestResult("UserTest", "test_breaks", testresult.StatusPass),
+		failed("test_same", "Expected: 1"),
+		failed("test_different", "Expected: 1"),
+		failed("test_removed", "Expected: 1"),
+	})
+	current := runner.NewTestExecutionResult(2, []testresult.TestResult{
+		failed("test_breaks", "Expected: 1"),
+		failed("test_same", "Expected: 1"),
+		failed("test_different", "Expected: 2"),
+	})
+
+	got := DiffSummary(runner.Compare(previous, current))
+	want := "1 newly failed · 2 still failing (1 different error) · 1 removed"
+	if got != want {
+		t.Fatalf("DiffSummary() = %q, want %q", got, want)
+	}
+
+	passing := runner.NewTestExecutionResult(3, []testresult.TestResult{
+		testresult.NewTestResult("UserTest", "test_breaks", testresult.StatusPass),
+	})
+	if got := DiffSummary(runner.Compare(passing, passing)); got != "No changes since the previous run" {
+		t.Fatalf("DiffSummary() of the same run = %q", got)
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	t.Parallel()
+
+	tests := map[time.Duration]string{
+		850 * time.Millisecond:  "850ms",
+		1249 * time.Millisecond: "1.2s",
+		125 * time.Second:       "2m5s",
+	}
+	for duration, want := range tests {
+		if got := FormatDuration(duration); got != want {
+			t.Fatalf("FormatDuration(%s) = %q, want %q", duration, got, want)
+		}
+	}
+}
+
+func TestRelativeTime(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 11, 14, 21, 30, 0, 0, time.UTC)
+	tests := map[time.Duration]string{
+		10 * time.Second: "just now",
+		5 * time.Minute:  "5m ago",
+		3 * time.Hour:    "3h ago",
+		50 * time.Hour:   "2d ago",
+	}
+	for ago, want := range tests {
+		if got := RelativeTime(now.Add(-ago), now); got != want {
+			t.Fatalf("RelativeTime(-%s) = %q, want %q", ago, got, want)
+		}
+	}
+}
+
+func TestModel_CursorNavigation(t *testing.T) {
+	t.Parallel()
+
+	testRuns := testrun.NewTestRuns()
+	oldest, _ := testRuns.Add(nil, testrun.ModeRunWholeSuite)
+	newest, _ := testRuns.Add(nil, testrun.ModeRunWholeSuite)
+	m := NewModel(nil, &testRuns, nil, nil)
+
+	// Blurred sections ignore keys
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if cmd != nil {
+		t.Fatalf("blurred section handled a key")
+	}
+
+	m.ToggleFocus()
+	m.Select(newest.Id)
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m = updated.(Model)
+	if m.SelectedId() != oldest.Id {
+		t.Fatalf("SelectedId() = %d, want %d", m.SelectedId(), oldest.Id)
+	}
+	if msg, ok := cmd().(TestRunSelectedMsg); !ok || msg.TestRunId != oldest.Id {
+		t.Fatalf("down sent %#v, want TestRunSelectedMsg for %d", msg, oldest.Id)
+	}
+
+	// The cursor stops at the oldest run
+	updated, cmd = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m = updated.(Model)
+	if cmd != nil || m.SelectedId() != oldest.Id {
+		t.Fatalf("cursor moved past the oldest run")
+	}
+
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("F")})
+	if msg, ok := cmd().(ReRunFailuresMsg); !ok || msg.TestRunId != oldest.Id {
+		t.Fatalf("F sent %#v, want ReRunFailuresMsg for %d", msg, oldest.Id)
+	}
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	if msg, ok := cmd().(ReRunTestRunMsg); !ok || msg.TestRunId != oldest.Id {
+		t.Fatalf("r sent %#v, want ReRunTestRunMsg for %d", msg, oldest.Id)
+	}
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	if msg, ok := cmd().(DeleteTestRunMsg); !ok || msg.TestRunId != oldest.Id {
+		t.Fatalf("d sent %#v, want DeleteTestRunMsg for %d", msg, oldest.Id)
+	}
+}
+
+func TestModel_NeighbourOf(t *testing.T) {
+	t.Parallel()
+
+	testRuns := testrun.NewTestRuns()
+	oldest, _ := testRuns.Add(nil, testrun.ModeRunWholeSuite)
+	middle, _ := testRuns.Add(nil, testrun.ModeRunWholeSuite)
+	newest, _ := testRuns.Add(nil, testrun.ModeRunWholeSuite)
+	m := NewModel(nil, &testRuns, nil, nil)
+
+	if got := m.NeighbourOf(newest.Id); got != middle.Id {
+		t.Fatalf("NeighbourOf(newest) = %d, want %d", got, middle.Id)
+	}
+	if got := m.NeighbourOf(oldest.Id); got != middle.Id {
+		t.Fatalf("NeighbourOf(oldest) = %d, want %d", got, middle.Id)
+	}
+
+	single := testrun.NewTestRuns()
+	only, _ := single.Add(nil, testrun.ModeRunWholeSuite)
+	if got := NewModel(nil, &single, nil, nil).NeighbourOf(only.Id); got != 0 {
+		t.Fatalf("NeighbourOf(only) = %d, want 0", got)
+	}
+}
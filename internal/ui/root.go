@@ -9,6 +9,7 @@ import (
 	"github.com/adamakhtar/wing_commander/internal/ui/styles"
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/log"
 )
 
 // TYPES
@@ -26,7 +27,27 @@ type Model struct {
 // BUILDERS
 //================================================
 
+// NewModel builds the TUI, restoring the test runs of earlier sessions from
+// the project's history.
 func NewModel(cfg *config.Config, styles styles.Styles) tea.Model {
+	model := newModel(cfg, styles)
+	if err := model.resultsScreen.RestoreHistory(); err != nil {
+		log.Warn("failed to restore test run history", "error", err)
+	}
+	return model
+}
+
+// NewViewModel builds the TUI showing the results of existing summary files
+// instead of starting empty.
+func NewViewModel(cfg *config.Config, styles styles.Styles, summaryPaths []string) (tea.Model, error) {
+	model := newModel(cfg, styles)
+	if err := model.resultsScreen.LoadSummaryFiles(summaryPaths); err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+func newModel(cfg *config.Config, styles styles.Styles) Model {
 	ctx := &context.Context{
 		CurrentScreen: context.ResultsScreen,
 		Config:        cfg,
@@ -64,11 +85,18 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.ctx.CurrentScreen = context.ResultsScreen
 		cmd = m.resultsScreen.Prepare()
 		return m, cmd
+	case results.TestExecutionProgressMsg, results.TestResultStreamedMsg, results.TestsHungMsg, results.TestExecutionCompletedMsg, results.TestExecutionFailedMsg:
+		// Test runs report back to the results screen even while another screen is shown
+		resultsScreen, resultsCmd := m.resultsScreen.Update(msg)
+		m.resultsScreen = resultsScreen.(results.Model)
+		return m, resultsCmd
 	case tea.WindowSizeMsg:
 		m.setSize(msg.Width, msg.Height)
 		m.ready = true
 	case tea.KeyMsg:
 		if key.Matches(msg, keys.AppKeys.Quit) {
+			// The test command runs in its own process group and would outlive us
+			m.resultsScreen.CancelTestRun()
 			return m, tea.Quit
 		}
 	}
@@ -178,15 +178,32 @@ type Styles struct {
 		TableHeaderTextColor lipgloss.Color
 		TableRowTextColor lipgloss.Color
 		TableHighlight lipgloss.Style
+		ProgressLabel lipgloss.Style
+		ProgressBarPassed lipgloss.Style
+		ProgressBarFailed lipgloss.Style
+		ProgressBarEmpty lipgloss.Style
+		HungTestWarning lipgloss.Style
+		NewlyFailedBadge lipgloss.Style
+		FixedBadge lipgloss.Style
+		StillFailingBadge lipgloss.Style
+		DifferentErrorBadge lipgloss.Style
+		AddedBadge lipgloss.Style
 	}
 	PreviewSection struct {
 		BacktracePath lipgloss.Style
 		CodeLine lipgloss.Style
 		HighlightedCodeLine lipgloss.Style
 		SnippetBorder lipgloss.Style
+		OutcomePass lipgloss.Style
+		OutcomeFail lipgloss.Style
+		OutcomeSkip lipgloss.Style
+		Flaky lipgloss.Style
 	}
 	TestRunsSection struct {
 		Label lipgloss.Style
+		Summary lipgloss.Style
+		Selected lipgloss.Style
+		Diff lipgloss.Style
 	}
 	// FaintTextStyle lipgloss.Style
 	// Results struct {
@@ -218,12 +235,29 @@ func BuildStyles(theme Theme) Styles {
 	s.ResultsSection.TableHeaderTextColor = theme.TableHeaderTextColor
 	s.ResultsSection.TableRowTextColor = theme.TableRowTextColor
 	s.ResultsSection.TableHighlight = lipgloss.NewStyle().Background(theme.TableSelectedBackground).Foreground(theme.TableRowTextColor)
+	s.ResultsSection.ProgressLabel = lipgloss.NewStyle().Foreground(theme.BodyTextLight)
+	s.ResultsSection.ProgressBarPassed = lipgloss.NewStyle().Foreground(Green500)
+	s.ResultsSection.ProgressBarFailed = lipgloss.NewStyle().Foreground(Red500)
+	s.ResultsSection.ProgressBarEmpty = lipgloss.NewStyle().Foreground(Gray700)
+	s.ResultsSection.HungTestWarning = lipgloss.NewStyle().Foreground(Yellow400).Bold(true)
+	s.ResultsSection.NewlyFailedBadge = lipgloss.NewStyle().Background(Red500).Foreground(White).Align(lipgloss.Center)
+	s.ResultsSection.FixedBadge = lipgloss.NewStyle().Background(Green500).Foreground(White).Align(lipgloss.Center)
+	s.ResultsSection.StillFailingBadge = lipgloss.NewStyle().Background(Gray700).Foreground(Gray300).Align(lipgloss.Center)
+	s.ResultsSection.DifferentErrorBadge = lipgloss.NewStyle().Background(Amber500).Foreground(Black).Align(lipgloss.Center)
+	s.ResultsSection.AddedBadge = lipgloss.NewStyle().Background(Cyan600).Foreground(White).Align(lipgloss.Center)
 
 	s.PreviewSection.BacktracePath = lipgloss.NewStyle().Underline(true).Foreground(theme.BodyText).Bold(true)
 	s.PreviewSection.CodeLine = lipgloss.NewStyle().Foreground(Gray400)
 	s.PreviewSection.HighlightedCodeLine = lipgloss.NewStyle().Background(Pink800).Foreground(White)
 	s.PreviewSection.SnippetBorder = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(theme.PrimaryBorderColor)
+	s.PreviewSection.OutcomePass = lipgloss.NewStyle().Foreground(Green500)
+	s.PreviewSection.OutcomeFail = lipgloss.NewStyle().Foreground(Red500)
+	s.PreviewSection.OutcomeSkip = lipgloss.NewStyle().Foreground(Gray500)
+	s.PreviewSection.Flaky = lipgloss.NewStyle().Foreground(Amber400)
 
 	s.TestRunsSection.Label = lipgloss.NewStyle().Foreground(theme.BodyTextLight)
+	s.TestRunsSection.Summary = lipgloss.NewStyle().Foreground(Gray400)
+	s.TestRunsSection.Selected = lipgloss.NewStyle().Background(theme.TableSelectedBackground)
+	s.TestRunsSection.Diff = lipgloss.NewStyle().Foreground(Amber400)
 	return s
 }
\ No newline at end of file
@@ -0,0 +1,15 @@
+package worker
+
+// Total sums the prices of the items.
+func Total(prices []int) int {
+	total := 0
+	for i := 0; i <= len(prices); i++ {
+		total += prices[i]
+	}
+	return total
+}
+
+// Discount returns the discounted price.
+func Discount(price int) int {
+	return price - price/10
+}
@@ -0,0 +1,38 @@
+package worker
+
+import "testing"
+
+func TestDiscount(t *testing.T) {
+	if got := Discount(100); got != 90 {
+		t.Errorf("expected 90, got %d", got)
+	}
+}
+
+func TestDiscountTable(t *testing.T) {
+	cases := []struct {
+		name  string
+		price int
+		want  int
+	}{
+		{"round price", 100, 90},
+		{"odd price", 15, 13},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Discount(tc.price); got != tc.want {
+				t.Errorf("Discount(%d) = %d\nwant %d", tc.price, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSkipped(t *testing.T) {
+	t.Skip("not implemented")
+}
+
+func TestTotal(t *testing.T) {
+	t.Log("summing")
+	if Total([]int{1, 2}) != 3 {
+		t.Fatal("wrong total")
+	}
+}
@@ -0,0 +1,5 @@
+const { payments } = require("./payments");
+
+test("pays", () => {
+  expect(payments).toBeDefined();
+});
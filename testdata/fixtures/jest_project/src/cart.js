@@ -0,0 +1,10 @@
+function discount(total) {
+  return total * 0.9;
+}
+
+function checkout(items) {
+  const perItem = items.reduce((sum, item) => sum + item.price, 0) / items.length;
+  return perItem.toFixed(2).padStart(items.length.bogus);
+}
+
+module.exports = { discount, checkout };
@@ -0,0 +1,19 @@
+const { discount, checkout } = require("./cart");
+
+describe("Cart", () => {
+  describe("discount", () => {
+    test("takes ten percent off", () => {
+      expect(discount(100)).toBe(90);
+    });
+
+    test("takes fifteen percent off", () => {
+      expect(discount(100)).toBe(85);
+    });
+  });
+
+  test("checks out", () => {
+    checkout(null);
+  });
+
+  test.skip("refunds", () => {});
+});
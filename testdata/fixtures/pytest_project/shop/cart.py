@@ -0,0 +1,8 @@
+def discount(price):
+    return price - price // 10
+
+
+def checkout(items):
+    total = sum(items)
+    # average price per item
+    return total / len(items)
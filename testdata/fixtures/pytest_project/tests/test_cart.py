@@ -0,0 +1,20 @@
+import pytest
+
+from shop.cart import checkout, discount
+
+
+class TestCart:
+    def test_total(self):
+        assert discount(100) == 90
+
+    def test_discount(self):
+        assert discount(100) == 85
+
+
+def test_checkout():
+    print("checking out")
+    checkout([])
+
+
+def test_skipped():
+    pytest.skip("not ready")